@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/cedana/cedana/api/services/task"
@@ -21,10 +22,14 @@ import (
 // wrapper over filesystem, useful for mocking in tests
 var AppFs = afero.NewOsFs()
 
+// Client is shared by every request the daemon serves, so it must not hold
+// per-operation state; job IDs and workspaces are passed down explicitly.
 type Client struct {
 	CRIU   *Criu
 	logger *zerolog.Logger
-	config *utils.Config
+	// the current config, swapped whole on reload and never modified
+	// in place; see config
+	cfg atomic.Pointer[utils.Config]
 
 	// for dependency-injection of filesystems (useful for testing)
 	fs *afero.Afero
@@ -32,12 +37,7 @@ type Client struct {
 	// db meta/state store
 	db *DB
 
-	// a separate client is created for each connection to the gRPC client,
-	// and the jobID is set at instantiation time. We rely on whatever is callig
-	// InstantiateClient() to set the jobID correctly.
-	jobID string
-
-	// used for perf, CEDANA_OTEL_ENABLED needs to be set
+	// used for perf, otel.enabled needs to be set
 	tracer trace.Tracer
 }
 
@@ -64,14 +64,21 @@ func InstantiateClient() (*Client, error) {
 
 	criu := new(Criu)
 
-	return &Client{
+	c := &Client{
 		CRIU:   criu,
 		logger: &logger,
-		config: config,
 		fs:     fs,
 		db:     db,
 		tracer: otel.Tracer("cedana-daemon"),
-	}, nil
+	}
+	c.cfg.Store(config)
+	return c, nil
+}
+
+// config is the current config. An operation that reads several settings
+// should hold on to one snapshot, so a reload can't change them midway.
+func (c *Client) config() *utils.Config {
+	return c.cfg.Load()
 }
 
 func (c *Client) cleanupClient() error {
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/cedana/cedana/api/services/task"
@@ -26,7 +27,7 @@ func setup(t *testing.T) (task.TaskServiceClient, error) {
 		srv.Stop()
 	})
 
-	mockDB, err := bolt.Open("test.db", 0600, nil)
+	mockDB, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
 	t.Cleanup(func() {
 		mockDB.Close()
 	})
@@ -103,7 +104,7 @@ func TestClient_TryStartJob(t *testing.T) {
 		uid := uint32(os.Getuid())
 		gid := uint32(os.Getgid())
 
-		_, err = client.StartTask(ctx, &task.StartTaskArgs{Task: "test", Id: "test", LogOutputFile: "somefile", UID: uid, GID: gid})
+		_, err = client.StartTask(ctx, &task.StartTaskArgs{Task: "test", Id: "test", LogOutputFile: filepath.Join(t.TempDir(), "somefile"), UID: uid, GID: gid})
 
 		if err != nil {
 			t.Errorf("failed to start task: %v", err)
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -36,13 +37,13 @@ func (c *Criu) sendAndRecv(reqB []byte, sk *os.File) ([]byte, int, error) {
 	return respB, n, nil
 }
 
-func (c *Criu) doSwrk(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *Notify, extraFiles []*os.File) (*rpc.CriuResp, error) {
+func (c *Criu) doSwrk(ctx context.Context, reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *Notify, extraFiles []*os.File) (*rpc.CriuResp, error) {
 	valid, _ := c.IsCriuAtLeast(31700)
 	if !valid {
 		return nil, errors.New("CRIU version is too old, please upgrade to at least 3.17")
 	}
 
-	resp, err := c.doSwrkWithResp(reqType, opts, nfy, extraFiles, nil)
+	resp, err := c.doSwrkWithResp(ctx, reqType, opts, nfy, extraFiles, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +89,10 @@ func SendFile(socket *os.File, file *os.File) error {
 	return err
 }
 
-func (c *Criu) doSwrkWithResp(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *Notify, extraFiles []*os.File, features *rpc.CriuFeatures) (*rpc.CriuResp, error) {
+// doSwrkWithResp drives a single criu swrk child over a seqpacket socket. If ctx is
+// cancelled before CRIU replies, the child is killed and reaped, and ctx.Err() is
+// returned so callers can tell a cancellation apart from a CRIU failure.
+func (c *Criu) doSwrkWithResp(ctx context.Context, reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *Notify, extraFiles []*os.File, features *rpc.CriuFeatures) (*rpc.CriuResp, error) {
 	var resp *rpc.CriuResp
 
 	req := rpc.CriuReq{
@@ -111,9 +115,9 @@ func (c *Criu) doSwrkWithResp(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *
 
 	cln := os.NewFile(uintptr(fds[0]), "criu-xprt-cln")
 	syscall.CloseOnExec(fds[0])
+	defer cln.Close()
 
 	srv := os.NewFile(uintptr(fds[1]), "criu-xprt-srv")
-	defer srv.Close()
 
 	cmd := exec.Command("criu", "swrk", strconv.Itoa(fds[1]))
 
@@ -122,10 +126,33 @@ func (c *Criu) doSwrkWithResp(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *
 	}
 
 	err = cmd.Start()
+	// the child holds its own copy of the server end; dropping ours means a dead
+	// child shows up as EOF on cln instead of a read that blocks forever.
+	srv.Close()
 	if err != nil {
 		return nil, err
 	}
 
+	// kill the swrk child if the caller goes away. Killing it also unblocks the
+	// pending read on cln, since the peer end of the socketpair is closed.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+
+	reaped := false
+	defer func() {
+		if !reaped {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}
+	}()
+
 	for {
 		reqB, err := proto.Marshal(&req)
 		if err != nil {
@@ -133,6 +160,9 @@ func (c *Criu) doSwrkWithResp(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *
 		}
 
 		respB, respS, err := c.sendAndRecv(reqB, cln)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -193,7 +223,11 @@ func (c *Criu) doSwrkWithResp(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *
 	}
 
 	// cleanup
+	reaped = true
 	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -202,17 +236,17 @@ func (c *Criu) doSwrkWithResp(reqType rpc.CriuReqType, opts *rpc.CriuOpts, nfy *
 }
 
 // Dump dumps a process
-func (c *Criu) Dump(opts *rpc.CriuOpts, nfy *Notify) (*rpc.CriuResp, error) {
-	return c.doSwrk(rpc.CriuReqType_DUMP, opts, nfy, nil)
+func (c *Criu) Dump(ctx context.Context, opts *rpc.CriuOpts, nfy *Notify) (*rpc.CriuResp, error) {
+	return c.doSwrk(ctx, rpc.CriuReqType_DUMP, opts, nfy, nil)
 }
 
 // Restore restores a process
-func (c *Criu) Restore(opts *rpc.CriuOpts, nfy *Notify, extraFiles []*os.File) (*rpc.CriuResp, error) {
-	return c.doSwrk(rpc.CriuReqType_RESTORE, opts, nfy, extraFiles)
+func (c *Criu) Restore(ctx context.Context, opts *rpc.CriuOpts, nfy *Notify, extraFiles []*os.File) (*rpc.CriuResp, error) {
+	return c.doSwrk(ctx, rpc.CriuReqType_RESTORE, opts, nfy, extraFiles)
 }
 
 func (c *Criu) GetCriuVersion() (int, error) {
-	resp, err := c.doSwrkWithResp(rpc.CriuReqType_VERSION, nil, nil, nil, nil)
+	resp, err := c.doSwrkWithResp(context.Background(), rpc.CriuReqType_VERSION, nil, nil, nil, nil)
 	if err != nil {
 		return 0, err
 	}
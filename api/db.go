@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/cedana/cedana/api/services/task"
@@ -107,6 +109,45 @@ func (db *DB) GetStateFromID(id string) (*task.ProcessState, error) {
 	return &state, err
 }
 
+// ListJobStates returns the latest state of every job, keyed by job ID.
+func (db *DB) ListJobStates() (map[string]*task.ProcessState, error) {
+	states := make(map[string]*task.ProcessState)
+
+	conn, err := NewROnlyBoltConn()
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	err = conn.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte("default"))
+		if root == nil {
+			// nothing has been started yet
+			return nil
+		}
+
+		return root.ForEachBucket(func(k []byte) error {
+			_, marshaledState := root.Bucket(k).Cursor().Last()
+			if marshaledState == nil {
+				return nil
+			}
+
+			var state task.ProcessState
+			if err := json.Unmarshal(marshaledState, &state); err != nil {
+				return err
+			}
+			states[string(k)] = &state
+			return nil
+		})
+	})
+
+	return states, err
+}
+
 func (db *DB) GetStateFromPID(pid int32) (*task.ProcessState, error) {
 	var state task.ProcessState
 
@@ -288,3 +329,246 @@ func (db *DB) GetLatestLocalCheckpoints(id string) ([]*string, error) {
 
 	return checkpoints, err
 }
+
+// Log segments live in their own bucket (logs -> job -> seq: segment) rather than
+// on ProcessState, since process state gets rewritten wholesale on every dump.
+func (db *DB) AppendLogSegment(id string, segment *task.LogSegment) error {
+	conn, err := NewBoltConn()
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte("logs"))
+		if err != nil {
+			return err
+		}
+
+		job, err := root.CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		seq, err := job.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		marshaledSegment, err := json.Marshal(segment)
+		if err != nil {
+			return err
+		}
+
+		// zero-padded so cursor order matches insertion order
+		return job.Put([]byte(fmt.Sprintf("%020d", seq)), marshaledSegment)
+	})
+}
+
+// GetLogSegments returns a job's log segments, oldest first.
+func (db *DB) GetLogSegments(id string) ([]*task.LogSegment, error) {
+	var segments []*task.LogSegment
+
+	conn, err := NewROnlyBoltConn()
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	err = conn.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte("logs"))
+		if root == nil {
+			return fmt.Errorf("could not find bucket")
+		}
+
+		job := root.Bucket([]byte(id))
+		if job == nil {
+			return fmt.Errorf("could not find logs for job")
+		}
+
+		return job.ForEach(func(k, v []byte) error {
+			var segment task.LogSegment
+			if err := json.Unmarshal(v, &segment); err != nil {
+				return err
+			}
+			segments = append(segments, &segment)
+			return nil
+		})
+	})
+
+	return segments, err
+}
+
+// maxJobEvents is how much of a job's history is kept. Older events are
+// dropped as new ones come in, apart from the latest of each kind a job is
+// summed up from (see newJob).
+const maxJobEvents = 500
+
+// Job history lives in its own bucket (history -> job -> seq: event), like log
+// segments, so it survives the job's process state being rewritten.
+func (db *DB) AppendJobEvent(id string, event *task.JobEvent) error {
+	conn, err := NewBoltConn()
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte("history"))
+		if err != nil {
+			return err
+		}
+
+		job, err := root.CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		seq, err := job.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		marshaledEvent, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		// zero-padded so cursor order matches insertion order
+		if err := job.Put([]byte(fmt.Sprintf("%020d", seq)), marshaledEvent); err != nil {
+			return err
+		}
+		return trimJobEvents(job)
+	})
+}
+
+// trimJobEvents drops a job's oldest events beyond maxJobEvents, keeping the
+// latest start, restore and checkpoint wherever they are, as the job's PID,
+// tags and last checkpoint come from them.
+func trimJobEvents(job *bolt.Bucket) error {
+	var keys [][]byte
+	if err := job.ForEach(func(k, _ []byte) error {
+		keys = append(keys, append([]byte(nil), k...))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(keys) <= maxJobEvents {
+		return nil
+	}
+
+	keep := map[string]bool{}
+	latest := map[task.JobEvent_EventType]bool{}
+	c := job.Cursor()
+	for k, v := c.Last(); k != nil && len(latest) < 3; k, v = c.Prev() {
+		var event task.JobEvent
+		if err := json.Unmarshal(v, &event); err != nil {
+			return err
+		}
+		switch event.Type {
+		case task.JobEvent_START, task.JobEvent_RESTORE, task.JobEvent_CHECKPOINT:
+			if !latest[event.Type] {
+				latest[event.Type] = true
+				keep[string(k)] = true
+			}
+		}
+	}
+
+	drop := len(keys) - maxJobEvents
+	for _, k := range keys {
+		if drop == 0 {
+			break
+		}
+		if keep[string(k)] {
+			continue
+		}
+		if err := job.Delete(k); err != nil {
+			return err
+		}
+		drop--
+	}
+	return nil
+}
+
+// ListJobEvents returns the history of every job, oldest first, read in one
+// transaction.
+func (db *DB) ListJobEvents() (map[string][]*task.JobEvent, error) {
+	history := make(map[string][]*task.JobEvent)
+
+	conn, err := NewROnlyBoltConn()
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	err = conn.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte("history"))
+		if root == nil {
+			return nil
+		}
+
+		return root.ForEachBucket(func(id []byte) error {
+			events, err := readJobEvents(root.Bucket(id))
+			if err != nil {
+				return err
+			}
+			history[string(id)] = events
+			return nil
+		})
+	})
+
+	return history, err
+}
+
+// GetJobEvents returns a job's history, oldest first. Jobs from before
+// history was kept have none.
+func (db *DB) GetJobEvents(id string) ([]*task.JobEvent, error) {
+	var events []*task.JobEvent
+
+	conn, err := NewROnlyBoltConn()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	err = conn.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte("history"))
+		if root == nil {
+			return nil
+		}
+
+		job := root.Bucket([]byte(id))
+		if job == nil {
+			return nil
+		}
+
+		events, err = readJobEvents(job)
+		return err
+	})
+
+	return events, err
+}
+
+func readJobEvents(job *bolt.Bucket) ([]*task.JobEvent, error) {
+	var events []*task.JobEvent
+	err := job.ForEach(func(k, v []byte) error {
+		var event task.JobEvent
+		if err := json.Unmarshal(v, &event); err != nil {
+			return err
+		}
+		events = append(events, &event)
+		return nil
+	})
+	return events, err
+}
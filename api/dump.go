@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -85,15 +88,19 @@ func (c *Client) prepareDump(ctx context.Context, pid int32, dir string, opts *r
 	formattedProcessName := regexp.MustCompile("[^a-zA-Z0-9_.-]").ReplaceAllString(*pname, "_")
 	formattedProcessName = strings.ReplaceAll(formattedProcessName, ".", "_")
 	processCheckpointDir := strings.Join([]string{formattedProcessName, time.Now().Format("02_01_2006_1504")}, "_")
-	checkpointFolderPath := filepath.Join(dir, processCheckpointDir)
-	_, err = os.Stat(filepath.Join(checkpointFolderPath))
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return "", err
+	}
+
+	// unique per dump, so concurrent dumps of same-named processes within the
+	// same minute don't write into each other's images
+	checkpointFolderPath, err := os.MkdirTemp(dir, processCheckpointDir+"_*")
 	if err != nil {
-		if err := os.MkdirAll(checkpointFolderPath, 0o777); err != nil {
-			return "", err
-		}
+		return "", err
 	}
 
-	err = chmodRecursive(checkpointFolderPath, 0o777)
+	// the images hold the process's memory, credentials and all
+	err = chmodRecursive(checkpointFolderPath, 0o700)
 	if err != nil {
 		return "", err
 	}
@@ -109,52 +116,318 @@ func (c *Client) prepareDump(ctx context.Context, pid int32, dir string, opts *r
 	return checkpointFolderPath, nil
 }
 
-func (c *Client) postDump(ctx context.Context, dumpdir string, state *task.ProcessState) {
-	_, postDumpSpan := c.tracer.Start(ctx, "post-dump")
+// postDump tars up dumpdir, compressed with compression, and records the
+// checkpoint against jobID. Dumps that aren't tied to a job (jobID == "") skip
+// the db update. cfg is the config the dump started with.
+func (c *Client) postDump(ctx context.Context, cfg *utils.Config, jobID, dumpdir string, state *task.ProcessState, compression utils.Compression) error {
+	ctx, postDumpSpan := c.tracer.Start(ctx, "post-dump")
 	defer postDumpSpan.End()
 	compressedCheckpointPath := strings.Join([]string{dumpdir, ".tar"}, "")
 
 	state.CheckpointPath = compressedCheckpointPath
 	state.CheckpointState = task.CheckpointState_CHECKPOINTED
+	state.Compression = compression.String()
 	// sneak in a serialized state obj
 	err := c.SerializeStateToDir(dumpdir, state)
 	if err != nil {
 		postDumpSpan.RecordError(err)
-		c.logger.Fatal().Err(err)
+		return err
 	}
 
-	c.logger.Info().Msgf("compressing checkpoint to %s", compressedCheckpointPath)
-
-	err = utils.TarFolder(dumpdir, compressedCheckpointPath)
+	tarOpts, err := c.tarEncryption(ctx, cfg, jobID)
 	if err != nil {
 		postDumpSpan.RecordError(err)
-		c.logger.Fatal().Err(err)
+		return err
 	}
 
-	err = c.db.UpdateProcessStateWithID(c.jobID, state)
+	var stats utils.TarStats
+	tarOpts = append(tarOpts, utils.WithCompression(compression), utils.WithThreads(cfg.Client.CompressionThreads), utils.WithStats(&stats))
+
+	c.logger.Info().Msgf("compressing checkpoint to %s with %s", compressedCheckpointPath, compression)
+
+	err = utils.TarFolder(ctx, dumpdir, compressedCheckpointPath, tarOpts...)
 	if err != nil {
 		postDumpSpan.RecordError(err)
-		c.logger.Fatal().Err(err)
+		return err
+	}
+	state.CompressionRatio = stats.Ratio()
+	c.logger.Info().Msgf("checkpoint is %d bytes, compression ratio %.2f", stats.CompressedSize, state.CompressionRatio)
+
+	if err := c.signCheckpoint(ctx, cfg, jobID, compressedCheckpointPath); err != nil {
+		postDumpSpan.RecordError(err)
+		return err
+	}
+
+	if jobID != "" {
+		err = c.db.UpdateProcessStateWithID(jobID, state)
+		if err != nil {
+			postDumpSpan.RecordError(err)
+			return err
+		}
 	}
 	// get size of compressed checkpoint
 	info, err := os.Stat(compressedCheckpointPath)
 	if err != nil {
 		postDumpSpan.RecordError(err)
-		c.logger.Fatal().Err(err)
+		return err
 	}
 
-	postDumpSpan.SetAttributes(attribute.Int("ckpt-size", int(info.Size())))
+	postDumpSpan.SetAttributes(attribute.Int("ckpt-size", int(info.Size())), attribute.String("compression", state.Compression))
+	return nil
+}
+
+// compression resolves a dump's compression: spec as in DumpArgs.Compression,
+// or the configured codec if spec is empty.
+func (c *Client) compression(cfg *utils.Config, spec string) (utils.Compression, error) {
+	if spec == "" {
+		spec = cfg.Client.Compression
+	}
+	return utils.ParseCompression(spec)
+}
+
+// tarEncryption returns the options that encrypt jobID's checkpoint archive
+// under its own key, if encryption is enabled.
+func (c *Client) tarEncryption(ctx context.Context, cfg *utils.Config, jobID string) ([]utils.TarOption, error) {
+	if !cfg.Encryption.Enabled {
+		return nil, nil
+	}
+
+	keys, err := utils.NewKeyProvider(cfg.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint encryption: %w", err)
+	}
+	keyID, key, err := keys.JobKey(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint encryption: %w", err)
+	}
+	opts := []utils.TarOption{utils.WithEncryption(key, keyID)}
+	if cfg.Encryption.Deterministic {
+		opts = append(opts, utils.WithDeterministicEncryption())
+	}
+	return opts, nil
+}
+
+// checkpointStream is a dump being archived and pushed to a streaming store
+// while CRIU is still dumping, see DumpToStore.
+type checkpointStream struct {
+	capture   *utils.ImageCapture
+	spill     *os.File
+	spillPath string
+	key       ed25519.PrivateKey
+	stats     utils.TarStats
+
+	// takes the dump's outcome once the image dir is complete, or the dump
+	// has failed
+	dumped chan error
+	// closed once the push is over, with meta and err set
+	done chan struct{}
+	meta *utils.CheckpointMeta
+	err  error
+}
+
+// startStream starts archiving and pushing jobID's checkpoint before CRIU
+// runs. With criu-image-streamer, opts is set up for CRIU to send its images
+// through it, straight into the archive, so they never land on disk; without
+// it, the images in dumpdir are archived once CRIU is done. Only the small
+// files left in dumpdir, and a copy of the archive if store.spill is set, are
+// written locally.
+func (c *Client) startStream(ctx context.Context, cfg *utils.Config, jobID, dumpdir string, compression utils.Compression, store utils.StreamingStore, opts *rpc.CriuOpts) (_ *checkpointStream, err error) {
+	tarOpts, err := c.tarEncryption(ctx, cfg, jobID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.nodeKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &checkpointStream{key: key, dumped: make(chan error, 1), done: make(chan struct{})}
+
+	pr, pw := io.Pipe()
+	var w io.Writer = pw
+	if cfg.Store.Spill {
+		s.spillPath = dumpdir + ".tar"
+		if s.spill, err = os.OpenFile(s.spillPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				s.spill.Close()
+				os.Remove(s.spillPath)
+			}
+		}()
+		w = io.MultiWriter(pw, s.spill)
+	}
+
+	var images io.Reader
+	if binary := c.imageStreamer(cfg); binary != "" {
+		if s.capture, err = utils.StartImageCapture(ctx, binary, dumpdir); err != nil {
+			return nil, err
+		}
+		opts.ConfigFile = proto.String(s.capture.ConfigFile())
+		images = s.capture.Stream()
+		c.logger.Info().Msgf("streaming images of %s to the store through %s", dumpdir, binary)
+	} else {
+		c.logger.Info().Msgf("streaming checkpoint of %s to the store once it's dumped", dumpdir)
+	}
+	ready := func() error {
+		err := <-s.dumped
+		if s.capture != nil {
+			if werr := s.capture.Wait(); err == nil {
+				err = werr
+			}
+		}
+		return err
+	}
+	tarOpts = append(tarOpts, utils.WithCompression(compression), utils.WithThreads(cfg.Client.CompressionThreads),
+		utils.WithStats(&s.stats), utils.WithImageStream(images, ready))
+
+	tarErr := make(chan error, 1)
+	go func() {
+		err := utils.WriteTar(ctx, dumpdir, w, tarOpts...)
+		// CRIU would block on images nobody reads
+		if err != nil && s.capture != nil {
+			s.capture.Kill()
+		}
+		pw.CloseWithError(err)
+		tarErr <- err
+	}()
+	go func() {
+		defer close(s.done)
+		s.meta, s.err = store.PushCheckpointStream(ctx, jobID, pr, key)
+		// stops the tar if the push gave up first
+		pr.CloseWithError(s.err)
+		if err := <-tarErr; s.err == nil {
+			s.err = err
+		}
+	}()
+	return s, nil
+}
+
+// finishStream is postDump for a streaming store: it adds the checkpoint's
+// state to the image dir, lets the archive finish with the files there, and
+// waits for the push. The image dir is removed once the checkpoint is stored.
+func (c *Client) finishStream(ctx context.Context, jobID, dumpdir string, state *task.ProcessState, compression utils.Compression, s *checkpointStream) (_ *utils.CheckpointMeta, err error) {
+	ctx, streamSpan := c.tracer.Start(ctx, "stream-dump")
+	defer streamSpan.End()
+	defer func() {
+		if err != nil {
+			streamSpan.RecordError(err)
+		}
+	}()
+
+	state.CheckpointPath = s.spillPath
+	state.CheckpointState = task.CheckpointState_CHECKPOINTED
+	state.Compression = compression.String()
+	if err := c.SerializeStateToDir(dumpdir, state); err != nil {
+		return nil, err
+	}
+
+	s.dumped <- nil
+	<-s.done
+	if s.spill != nil {
+		if cerr := s.spill.Close(); s.err == nil {
+			s.err = cerr
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	state.CompressionRatio = s.stats.Ratio()
+
+	if s.spillPath != "" && s.key != nil {
+		if err := utils.SignArchive(ctx, s.spillPath, jobID, s.key); err != nil {
+			return nil, err
+		}
+	}
+	if jobID != "" {
+		if err := c.db.UpdateProcessStateWithID(jobID, state); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.RemoveAll(dumpdir); err != nil {
+		c.logger.Warn().Err(err).Msgf("could not remove image dir %s", dumpdir)
+	}
+
+	streamSpan.SetAttributes(attribute.Int("ckpt-size", int(s.meta.Size)))
+	return s.meta, nil
+}
+
+// abort stops a stream whose dump failed with err, so nothing is stored
+func (s *checkpointStream) abort(err error) {
+	if s.capture != nil {
+		s.capture.Kill()
+	}
+	select {
+	case s.dumped <- err:
+	default:
+	}
+	<-s.done
+	if s.spill != nil {
+		s.spill.Close()
+		os.Remove(s.spillPath)
+	}
+}
+
+// imageStreamer returns the criu-image-streamer dumps stream their images
+// through, or "" if there's none and CRIU has to write them to disk first.
+func (c *Client) imageStreamer(cfg *utils.Config) string {
+	if cfg.Store.ImageStreamer == "" {
+		return ""
+	}
+	path, err := exec.LookPath(cfg.Store.ImageStreamer)
+	if err != nil {
+		c.logger.Warn().Err(err).Msgf("no %s, so images are written to disk before they're streamed", cfg.Store.ImageStreamer)
+		return ""
+	}
+	return path
+}
+
+// signCheckpoint writes the archive's manifest, signed with the node key, so
+// restores that enforce signatures will accept it. Nothing is written unless
+// signing is configured.
+func (c *Client) signCheckpoint(ctx context.Context, cfg *utils.Config, jobID, path string) error {
+	key, err := c.nodeKey(cfg)
+	if err != nil || key == nil {
+		return err
+	}
+	return utils.SignArchive(ctx, path, jobID, key)
+}
+
+// nodeKey returns the key checkpoints are signed with, or nil if cfg doesn't
+// sign them.
+func (c *Client) nodeKey(cfg *utils.Config) (ed25519.PrivateKey, error) {
+	if !cfg.Signing.Signs() {
+		return nil, nil
+	}
+	key, err := utils.LoadNodeKey(cfg.Signing.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint signing: %w", err)
+	}
+	return key, nil
+}
+
+// abortDump undoes a dump that failed or was cancelled: the partial image dir
+// (and any half-written tarball) is removed and the process is sent SIGCONT in
+// case CRIU was killed, or failed, while the tree was still stopped.
+func (c *Client) abortDump(dumpdir string, pid int32) {
+	c.logger.Warn().Msgf("dump of pid %d failed, cleaning up %s", pid, dumpdir)
+	os.RemoveAll(dumpdir)
+	os.Remove(strings.Join([]string{dumpdir, ".tar"}, ""))
+
+	if err := syscall.Kill(int(pid), syscall.SIGCONT); err != nil {
+		c.logger.Warn().Msgf("could not resume pid %d: %v", pid, err)
+	}
 }
 
 func (c *Client) discardParentFds() {
 
 }
 
-func (c *Client) prepareCheckpointOpts() *rpc.CriuOpts {
+func (c *Client) prepareCheckpointOpts(cfg *utils.Config) *rpc.CriuOpts {
 	opts := rpc.CriuOpts{
 		LogLevel:     proto.Int32(4),
 		LogFile:      proto.String("dump.log"),
-		LeaveRunning: proto.Bool(c.config.Client.LeaveRunning),
+		LeaveRunning: proto.Bool(cfg.Client.LeaveRunning),
 		GhostLimit:   proto.Uint32(uint32(10000000)),
 	}
 	return &opts
@@ -249,7 +522,7 @@ func patchPodmanDump(containerId, imgPath string) error {
 
 }
 
-func (c *Client) RuncDump(ctx context.Context, root, containerId string, opts *container.CriuOpts) error {
+func (c *Client) RuncDump(ctx context.Context, jobID, root, containerId string, opts *container.CriuOpts) error {
 	_, dumpSpan := c.tracer.Start(ctx, "dump")
 	dumpSpan.SetAttributes(attribute.Bool("container", true))
 
@@ -304,9 +577,17 @@ func (c *Client) RuncDump(ctx context.Context, root, containerId string, opts *c
 		return err
 	}
 
+	cfg := c.config()
+	compression, err := c.compression(cfg, "")
+	if err != nil {
+		return err
+	}
+
 	// CRIU ntfy hooks get run before this,
 	// so have to ensure that image files aren't tampered with
-	c.postDump(ctx, opts.ImagesDirectory, state)
+	if err := c.postDump(ctx, cfg, jobID, opts.ImagesDirectory, state, compression); err != nil {
+		return err
+	}
 	c.cleanupClient()
 
 	return nil
@@ -333,32 +614,83 @@ func (c *Client) ContainerDump(imagePath, containerId string) error {
 		return err
 	}
 
+	cfg := c.config()
+	compression, err := c.compression(cfg, "")
+	if err != nil {
+		return err
+	}
+
 	// CRIU ntfy hooks get run before this,
 	// so have to ensure that image files aren't tampered with
-	c.postDump(context.Background(), imagePath, state)
+	if err := c.postDump(context.Background(), cfg, "", imagePath, state, compression); err != nil {
+		return err
+	}
 	c.cleanupClient()
 
 	return nil
 }
 
-func (c *Client) Dump(ctx context.Context, dir string, pid int32) error {
-	opts := c.prepareCheckpointOpts()
+// Dump checkpoints pid into an archive under dir. cfg is read once by the
+// caller, so a config reload mid-dump can't change how the dump is made.
+func (c *Client) Dump(ctx context.Context, cfg *utils.Config, jobID, dir string, pid int32, compression utils.Compression) error {
+	return c.dump(ctx, cfg, jobID, dir, pid, nil, func(ctx context.Context, jobID, dumpdir string, state *task.ProcessState) error {
+		if err := c.postDump(ctx, cfg, jobID, dumpdir, state, compression); err != nil {
+			return err
+		}
+		// the archive is the checkpoint, and may be encrypted; the images it
+		// was made from aren't
+		if err := os.RemoveAll(dumpdir); err != nil {
+			c.logger.Warn().Err(err).Msgf("could not remove image dir %s", dumpdir)
+		}
+		return nil
+	})
+}
+
+// DumpToStore is Dump for a store that takes checkpoints as a stream: the
+// archive is written and pushed to store as CRIU dumps, see startStream.
+func (c *Client) DumpToStore(ctx context.Context, cfg *utils.Config, jobID, dir string, pid int32, compression utils.Compression, store utils.StreamingStore) (*utils.CheckpointMeta, error) {
+	var stream *checkpointStream
+	var meta *utils.CheckpointMeta
+	err := c.dump(ctx, cfg, jobID, dir, pid, func(ctx context.Context, dumpdir string, opts *rpc.CriuOpts) (err error) {
+		stream, err = c.startStream(ctx, cfg, jobID, dumpdir, compression, store, opts)
+		return err
+	}, func(ctx context.Context, jobID, dumpdir string, state *task.ProcessState) (err error) {
+		meta, err = c.finishStream(ctx, jobID, dumpdir, state, compression, stream)
+		return err
+	})
+	if err != nil && stream != nil {
+		stream.abort(err)
+	}
+	return meta, err
+}
+
+// dump checkpoints pid into a new image dir under dir, then hands the images
+// to post to be archived. start, if set, is called just before CRIU runs, and
+// may change the options it runs with.
+func (c *Client) dump(ctx context.Context, cfg *utils.Config, jobID, dir string, pid int32, start func(ctx context.Context, dumpdir string, opts *rpc.CriuOpts) error, post func(ctx context.Context, jobID, dumpdir string, state *task.ProcessState) error) (err error) {
+	opts := c.prepareCheckpointOpts(cfg)
 	dumpdir, err := c.prepareDump(ctx, pid, dir, opts)
 	if err != nil {
 		return err
 	}
+	// nothing of a failed dump is kept, and the process mustn't stay stopped
+	defer func() {
+		if err != nil {
+			c.abortDump(dumpdir, pid)
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+		}
+	}()
 
 	// TODO NR:add another check here for task running w/ accel resources
 	var GPUCheckpointed bool
-	if os.Getenv("CEDANA_GPU_ENABLED") == "true" {
+	if cfg.GPU.Enabled {
 		err = c.gpuCheckpoint(ctx, dumpdir)
 		if err != nil {
 			return err
 		}
 		GPUCheckpointed = true
-		if err != nil {
-			return err
-		}
 	}
 
 	img, err := os.Open(dumpdir)
@@ -382,17 +714,28 @@ func (c *Client) Dump(ctx context.Context, dir string, pid int32) error {
 		return err
 	}
 
+	if start != nil {
+		if err := start(ctx, dumpdir, opts); err != nil {
+			return err
+		}
+	}
+
 	_, dumpSpan := c.tracer.Start(ctx, "dump")
 	dumpSpan.SetAttributes(attribute.Bool("container", false))
-	_, err = c.CRIU.Dump(opts, &nfy)
+	_, err = c.CRIU.Dump(ctx, opts, &nfy)
 	if err != nil {
+		dumpSpan.RecordError(err)
+		dumpSpan.End()
+		if ctx.Err() != nil {
+			return err
+		}
+
 		// check for sudo error
 		if strings.Contains(err.Error(), "errno 0") {
 			c.logger.Warn().Msgf("error dumping, cedana is not running as root: %v", err)
 			return err
 		}
 
-		dumpSpan.RecordError(err)
 		c.logger.Warn().Msgf("error dumping process: %v", err)
 		return err
 	}
@@ -400,7 +743,13 @@ func (c *Client) Dump(ctx context.Context, dir string, pid int32) error {
 	dumpSpan.End()
 
 	state.GPUCheckpointed = GPUCheckpointed
-	c.postDump(ctx, dumpdir, state)
+	// recorded so a restore can be matched to a CRIU that can read the images
+	if version, err := c.CRIU.GetCriuVersion(); err == nil {
+		state.CRIUVersion = int32(version)
+	}
+	if err := post(ctx, jobID, dumpdir, state); err != nil {
+		return err
+	}
 	c.cleanupClient()
 
 	return nil
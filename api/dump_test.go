@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/utils"
+	"github.com/checkpoint-restore/go-criu/v6/rpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMain(m *testing.M) {
+	// the test binary stands in for criu, see withFakeCriu
+	if filepath.Base(os.Args[0]) == "criu" {
+		fakeCriu(os.Args[1:])
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// fakeCriu answers criu swrk requests: it reports a version new enough to
+// dump with, and hangs on anything else until it's killed, like a dump of a
+// large process would. With CEDANA_TEST_CRIU_FAIL set, it fails them instead.
+func fakeCriu(args []string) {
+	if len(args) != 2 || args[0] != "swrk" {
+		os.Exit(1)
+	}
+	fd, err := strconv.Atoi(args[1])
+	if err != nil {
+		os.Exit(1)
+	}
+	sk := os.NewFile(uintptr(fd), "criu-xprt-srv")
+
+	buf := make([]byte, 4096)
+	n, err := sk.Read(buf)
+	if err != nil {
+		os.Exit(1)
+	}
+	var req rpc.CriuReq
+	if err := proto.Unmarshal(buf[:n], &req); err != nil {
+		os.Exit(1)
+	}
+
+	if req.GetType() != rpc.CriuReqType_VERSION {
+		if os.Getenv("CEDANA_TEST_CRIU_FAIL") != "" {
+			resp, _ := proto.Marshal(&rpc.CriuResp{Type: req.Type, Success: proto.Bool(false), CrErrmsg: proto.String("dump failed")})
+			sk.Write(resp)
+			os.Exit(0)
+		}
+		time.Sleep(time.Hour)
+		os.Exit(1)
+	}
+	resp, _ := proto.Marshal(&rpc.CriuResp{
+		Type:    rpc.CriuReqType_VERSION.Enum(),
+		Success: proto.Bool(true),
+		Version: &rpc.CriuVersion{MajorNumber: proto.Int32(3), MinorNumber: proto.Int32(19)},
+	})
+	sk.Write(resp)
+}
+
+// withFakeCriu puts the test binary first on PATH as criu
+func withFakeCriu(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Symlink(exe, filepath.Join(dir, "criu")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// stoppedProcess starts a process and stops it, as CRIU does while it dumps
+func stoppedProcess(t *testing.T) int32 {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	pid := cmd.Process.Pid
+	if err := syscall.Kill(pid, syscall.SIGSTOP); err != nil {
+		t.Fatal(err)
+	}
+	waitForState(t, pid, "T")
+	return int32(pid)
+}
+
+// waitForState waits for pid's state in /proc/<pid>/stat to be one of states
+func waitForState(t *testing.T, pid int, states string) {
+	t.Helper()
+	var state string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the state follows the parenthesised command name
+		fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+		if state = fields[0]; strings.Contains(states, state) {
+			return
+		}
+	}
+	t.Fatalf("pid %d is in state %s, expected one of %s", pid, state, states)
+}
+
+func TestDump_CancelledDuringCRIU(t *testing.T) {
+	withFakeCriu(t)
+	c, err := InstantiateClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := stoppedProcess(t)
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Dump(ctx, &utils.Config{}, "", dir, pid, utils.Compression{})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the dump to stop with its context, got %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("dump didn't stop when its context was done")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("expected the aborted dump to be cleaned up, found %s", e.Name())
+	}
+	waitForState(t, int(pid), "RS")
+}
+
+func TestDump_CRIUFails(t *testing.T) {
+	withFakeCriu(t)
+	t.Setenv("CEDANA_TEST_CRIU_FAIL", "1")
+	c, err := InstantiateClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := stoppedProcess(t)
+	dir := t.TempDir()
+
+	if err := c.Dump(context.Background(), &utils.Config{}, "", dir, pid, utils.Compression{}); err == nil {
+		t.Fatal("expected the dump to fail")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("expected the failed dump to be cleaned up, found %s", e.Name())
+	}
+	waitForState(t, int(pid), "RS")
+}
+
+func TestAbortDump(t *testing.T) {
+	c, err := InstantiateClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := stoppedProcess(t)
+
+	dumpdir := filepath.Join(t.TempDir(), "sleep_dump")
+	if err := os.MkdirAll(dumpdir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dumpdir, "pages-1.img"), []byte("pages"), 0o600)
+	os.WriteFile(dumpdir+".tar", []byte("half a tarball"), 0o600)
+
+	c.abortDump(dumpdir, pid)
+
+	for _, path := range []string{dumpdir, dumpdir + ".tar"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", path, err)
+		}
+	}
+	waitForState(t, int(pid), "RS")
+}
+
+func TestSignCheckpoint_OptIn(t *testing.T) {
+	ctx := context.Background()
+	c := &Client{}
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "node_key")
+	archive := filepath.Join(dir, "checkpoint.tar")
+	os.WriteFile(archive, []byte("a checkpoint"), 0o600)
+
+	if err := c.signCheckpoint(ctx, &utils.Config{Signing: utils.Signing{KeyFile: keyFile}}, "job-a", archive); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{keyFile, archive + ".manifest"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be written unless signing is on, got %v", path, err)
+		}
+	}
+
+	if err := c.signCheckpoint(ctx, &utils.Config{Signing: utils.Signing{Enabled: true, KeyFile: keyFile}}, "job-a", archive); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := utils.VerifyArchive(ctx, archive, nil); err != nil {
+		t.Errorf("expected a signed archive, got %v", err)
+	}
+}
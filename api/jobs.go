@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/shirou/gopsutil/v3/process"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordEvent adds event to the job's history. History is a record of what
+// happened, so failing to write it is logged rather than failing the request.
+func (s *service) recordEvent(jobID string, event *task.JobEvent) {
+	if jobID == "" {
+		return
+	}
+	if event.Time == 0 {
+		event.Time = time.Now().Unix()
+	}
+	if err := s.client.db.AppendJobEvent(jobID, event); err != nil {
+		s.logger.Warn().Msgf("could not record %s in the history of job %s: %v", event.Type, jobID, err)
+	}
+}
+
+// checkpointSize is the size of the archive at path, 0 if there isn't one
+func checkpointSize(path string) int64 {
+	if path == "" {
+		return 0
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// jobRuntime is what runs the job: "process" for jobs cedana started itself,
+// otherwise the container runtime
+func jobRuntime(state *task.ProcessState) string {
+	if state.ContainerId == "" {
+		return "process"
+	}
+	return strings.ToLower(state.ContainerRuntime.String())
+}
+
+// newJob puts a job's state and history together with what its current
+// process is doing now.
+func newJob(jobID string, state *task.ProcessState, events []*task.JobEvent) *task.Job {
+	job := &task.Job{
+		JobID:                   jobID,
+		PID:                     state.PID,
+		Flag:                    state.Flag,
+		CheckpointState:         state.CheckpointState,
+		CheckpointFailureReason: state.CheckpointFailureReason,
+		Runtime:                 jobRuntime(state),
+		CheckpointPath:          state.CheckpointPath,
+		RemoteState:             state.RemoteState,
+	}
+
+	// restores don't touch the job's state, so its current process comes from
+	// the latest event that had one
+	for _, event := range events {
+		switch event.Type {
+		case task.JobEvent_START:
+			job.PID, job.StartedAt, job.Tags = event.PID, event.Time, event.Tags
+		case task.JobEvent_RESTORE:
+			job.PID, job.StartedAt = event.PID, event.Time
+		case task.JobEvent_CHECKPOINT:
+			job.LastCheckpoint = event
+		}
+	}
+
+	// jobs from before history was kept
+	if job.LastCheckpoint == nil {
+		if n := len(state.RemoteState); n > 0 {
+			job.LastCheckpoint = &task.JobEvent{
+				Type:         task.JobEvent_CHECKPOINT,
+				Time:         state.RemoteState[n-1].Timestamp,
+				CheckpointID: state.RemoteState[n-1].CheckpointID,
+			}
+		} else if state.CheckpointPath != "" {
+			job.LastCheckpoint = &task.JobEvent{
+				Type:           task.JobEvent_CHECKPOINT,
+				CheckpointPath: state.CheckpointPath,
+				Size:           checkpointSize(state.CheckpointPath),
+			}
+		}
+	}
+
+	if job.PID == 0 {
+		return job
+	}
+	p, err := process.NewProcess(job.PID)
+	if err != nil {
+		return job
+	}
+	job.Running, _ = p.IsRunning()
+	if !job.Running {
+		return job
+	}
+	job.MemoryPercent, _ = p.MemoryPercent()
+	if st, err := p.Status(); err == nil {
+		job.Status = strings.Join(st, "")
+	}
+	if job.StartedAt == 0 {
+		if created, err := p.CreateTime(); err == nil {
+			job.StartedAt = created / 1000
+		}
+	}
+	return job
+}
+
+// jobFilter is what ListJobs is asked to narrow the jobs down to
+type jobFilter struct {
+	flags   map[task.FlagEnum]bool
+	tags    map[string]*string
+	runtime string
+}
+
+func newJobFilter(args *task.ListJobsArgs) (*jobFilter, error) {
+	f := &jobFilter{runtime: strings.ToLower(args.Runtime)}
+
+	for _, state := range args.States {
+		name := strings.ToUpper(state)
+		if !strings.HasPrefix(name, "JOB_") {
+			name = "JOB_" + name
+		}
+		flag, ok := task.FlagEnum_value[name]
+		if !ok {
+			var known []string
+			for _, name := range task.FlagEnum_name {
+				known = append(known, strings.ToLower(strings.TrimPrefix(name, "JOB_")))
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown state %q, expected one of %s", state, strings.Join(known, ", "))
+		}
+		if f.flags == nil {
+			f.flags = make(map[task.FlagEnum]bool)
+		}
+		f.flags[task.FlagEnum(flag)] = true
+	}
+
+	switch f.runtime {
+	case "", "process", "runc", "containerd":
+	default:
+		return nil, fmt.Errorf("unknown runtime %q, expected one of process, runc, containerd", args.Runtime)
+	}
+
+	for _, tag := range args.Tags {
+		if f.tags == nil {
+			f.tags = make(map[string]*string)
+		}
+		key, value, hasValue := strings.Cut(tag, "=")
+		if key == "" {
+			return nil, fmt.Errorf("bad tag %q, expected key or key=value", tag)
+		}
+		f.tags[key] = nil
+		if hasValue {
+			f.tags[key] = &value
+		}
+	}
+
+	return f, nil
+}
+
+func (f *jobFilter) match(job *task.Job) bool {
+	if f.flags != nil && !f.flags[job.Flag] {
+		return false
+	}
+	if f.runtime != "" && job.Runtime != f.runtime {
+		return false
+	}
+	for key, value := range f.tags {
+		tagged, ok := job.Tags[key]
+		if !ok || (value != nil && tagged != *value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListJobs returns every managed job matching args, one per job.
+func (s *service) ListJobs(ctx context.Context, args *task.ListJobsArgs) (*task.ListJobsResp, error) {
+	filter, err := newJobFilter(args)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	states, err := s.client.db.ListJobStates()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	history, err := s.client.db.ListJobEvents()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	ids := make([]string, 0, len(states))
+	for id := range states {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var resp task.ListJobsResp
+	for _, id := range ids {
+		if job := newJob(id, states[id], history[id]); filter.match(job) {
+			resp.Jobs = append(resp.Jobs, job)
+		}
+	}
+
+	return &resp, nil
+}
+
+// GetJob returns a job along with its history of starts, checkpoints and
+// restores.
+func (s *service) GetJob(ctx context.Context, args *task.GetJobArgs) (*task.GetJobResp, error) {
+	if args.JobID == "" {
+		return nil, status.Error(codes.InvalidArgument, "job id cannot be empty")
+	}
+
+	states, err := s.client.db.ListJobStates()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	state, ok := states[args.JobID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no job %s", args.JobID)
+	}
+
+	events, err := s.client.db.GetJobEvents(args.JobID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &task.GetJobResp{Job: newJob(args.JobID, state, events), History: events}, nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cedana/cedana/api/services/task"
+)
+
+func TestNewJob(t *testing.T) {
+	self := int32(os.Getpid())
+	state := &task.ProcessState{PID: 1 << 22, Flag: task.FlagEnum_JOB_RUNNING, CheckpointPath: "/ckpt/a.tar"}
+	events := []*task.JobEvent{
+		{Type: task.JobEvent_START, Time: 100, PID: 1 << 22, Tags: map[string]string{"team": "ml"}},
+		{Type: task.JobEvent_CHECKPOINT, Time: 200, PID: 1 << 22, CheckpointPath: "/ckpt/a.tar", Size: 4096},
+		{Type: task.JobEvent_RESTORE_FAILED, Time: 250, Error: "criu failed"},
+		{Type: task.JobEvent_RESTORE, Time: 300, PID: self, CheckpointPath: "/ckpt/a.tar"},
+	}
+
+	job := newJob("job-1", state, events)
+	// the restore didn't touch the state, so the PID comes from the history
+	if job.PID != self || job.StartedAt != 300 || !job.Running {
+		t.Errorf("expected the restored process %d started at 300 and running, got %d at %d (running %v)", self, job.PID, job.StartedAt, job.Running)
+	}
+	if job.Tags["team"] != "ml" {
+		t.Errorf("expected the tags the job was started with, got %v", job.Tags)
+	}
+	if job.LastCheckpoint == nil || job.LastCheckpoint.Time != 200 || job.LastCheckpoint.Size != 4096 {
+		t.Errorf("expected the checkpoint at 200, got %v", job.LastCheckpoint)
+	}
+	if job.Runtime != "process" {
+		t.Errorf("expected a process job, got %s", job.Runtime)
+	}
+
+	// jobs from before history was kept
+	state = &task.ProcessState{
+		PID:         1 << 22,
+		ContainerId: "ctr",
+		RemoteState: []*task.RemoteState{{CheckpointID: "old", Timestamp: 10}, {CheckpointID: "new", Timestamp: 20}},
+	}
+	state.ContainerRuntime = task.ProcessState_RUNC
+	job = newJob("job-2", state, nil)
+	if job.PID != 1<<22 || job.Running {
+		t.Errorf("expected the state's PID, which isn't running, got %d (running %v)", job.PID, job.Running)
+	}
+	if job.LastCheckpoint == nil || job.LastCheckpoint.CheckpointID != "new" || job.LastCheckpoint.Time != 20 {
+		t.Errorf("expected the latest remote checkpoint, got %v", job.LastCheckpoint)
+	}
+	if job.Runtime != "runc" {
+		t.Errorf("expected a runc job, got %s", job.Runtime)
+	}
+}
+
+func TestJobFilter(t *testing.T) {
+	running := &task.Job{Flag: task.FlagEnum_JOB_RUNNING, Runtime: "process", Tags: map[string]string{"team": "ml", "env": "prod"}}
+	done := &task.Job{Flag: task.FlagEnum_JOB_DONE, Runtime: "runc"}
+
+	tests := []struct {
+		name        string
+		args        *task.ListJobsArgs
+		wantRunning bool
+		wantDone    bool
+	}{
+		{"everything", &task.ListJobsArgs{}, true, true},
+		{"state", &task.ListJobsArgs{States: []string{"running"}}, true, false},
+		{"states are any of", &task.ListJobsArgs{States: []string{"JOB_DONE", "Running"}}, true, true},
+		{"tag key", &task.ListJobsArgs{Tags: []string{"team"}}, true, false},
+		{"tag value", &task.ListJobsArgs{Tags: []string{"team=ml"}}, true, false},
+		{"tags are all of", &task.ListJobsArgs{Tags: []string{"team=ml", "env=dev"}}, false, false},
+		{"runtime", &task.ListJobsArgs{Runtime: "runc"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newJobFilter(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.match(running); got != tt.wantRunning {
+				t.Errorf("running job: got %v, want %v", got, tt.wantRunning)
+			}
+			if got := f.match(done); got != tt.wantDone {
+				t.Errorf("done job: got %v, want %v", got, tt.wantDone)
+			}
+		})
+	}
+
+	for _, args := range []*task.ListJobsArgs{
+		{States: []string{"sleeping"}},
+		{Runtime: "docker"},
+		{Tags: []string{"=ml"}},
+	} {
+		if _, err := newJobFilter(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestJobEvents_Capped(t *testing.T) {
+	db := NewDB()
+	jobID := fmt.Sprintf("history-cap-test-%d", os.Getpid())
+
+	events := []*task.JobEvent{
+		{Type: task.JobEvent_START, Time: 1, Tags: map[string]string{"team": "ml"}},
+		{Type: task.JobEvent_RESTORE, Time: 2, PID: 1 << 22},
+	}
+	for i := 0; i < maxJobEvents+10; i++ {
+		events = append(events, &task.JobEvent{Type: task.JobEvent_RESTORE_FAILED, Time: int64(3 + i)})
+	}
+	for _, event := range events {
+		if err := db.AppendJobEvent(jobID, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := db.ListJobEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := history[jobID]
+	if len(kept) != maxJobEvents {
+		t.Fatalf("expected %d events kept, got %d", maxJobEvents, len(kept))
+	}
+	// what the job is summed up from outlives the cap
+	if kept[0].Type != task.JobEvent_START || kept[1].Type != task.JobEvent_RESTORE {
+		t.Errorf("expected the start and restore to be kept, got %v, %v", kept[0], kept[1])
+	}
+	if last := kept[len(kept)-1]; last.Time != events[len(events)-1].Time {
+		t.Errorf("expected the newest event last, got %v", last)
+	}
+	if got := kept[2].Time; got != 3+12 {
+		t.Errorf("expected the oldest failures to be dropped first, the first left is at %d", got)
+	}
+
+	single, err := db.GetJobEvents(jobID)
+	if err != nil || len(single) != len(kept) {
+		t.Errorf("expected GetJobEvents to agree, got %d events, %v", len(single), err)
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// jobLocks serializes operations on the same job (or container) while letting
+// different jobs proceed in parallel. Rather than queueing, a second operation
+// on a busy job is rejected with codes.Aborted so the caller can decide whether
+// to retry. The zero value is ready to use.
+type jobLocks struct {
+	mu   sync.Mutex
+	busy map[string]string // key -> operation currently holding it
+}
+
+// tryLock claims key for op, returning the release func. If key is already
+// held, a codes.Aborted status naming the running operation is returned.
+func (l *jobLocks) tryLock(key, op string) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.busy == nil {
+		l.busy = make(map[string]string)
+	}
+
+	if running, ok := l.busy[key]; ok {
+		return nil, status.Errorf(codes.Aborted, "%s is busy: %s already in progress", key, running)
+	}
+
+	l.busy[key] = op
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.busy, key)
+	}, nil
+}
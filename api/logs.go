@@ -0,0 +1,285 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	logPollInterval = 250 * time.Millisecond
+	logChunkSize    = 32 * 1024
+)
+
+// stderrLogPath derives where a job's stderr goes from its stdout log path,
+// e.g. /var/log/cedana-output.log -> /var/log/cedana-output.stderr.log
+func stderrLogPath(stdoutPath string) string {
+	ext := filepath.Ext(stdoutPath)
+	return strings.TrimSuffix(stdoutPath, ext) + ".stderr" + ext
+}
+
+// newRestoreLogs creates the files a restored process' stdout/stderr get
+// redirected into. Unless split is set, both go into the one file, which is
+// returned for both. The random suffix keeps two restores in the same second
+// from sharing output files.
+func newRestoreLogs(split bool) (*os.File, *os.File, error) {
+	stdout, err := os.CreateTemp("/var/log", fmt.Sprintf("cedana-output-%d-*.log", time.Now().Unix()))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := stdout.Chmod(0o644); err != nil {
+		stdout.Close()
+		os.Remove(stdout.Name())
+		return nil, nil, err
+	}
+	if !split {
+		return stdout, stdout, nil
+	}
+
+	stderr, err := os.OpenFile(stderrLogPath(stdout.Name()), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		stdout.Close()
+		os.Remove(stdout.Name())
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// closeRestoreLogs closes the files newRestoreLogs made, removing them as
+// well if remove is set. An unsplit stderr is the stdout file, which is only
+// closed and removed once.
+func closeRestoreLogs(stdout, stderr *os.File, remove bool) error {
+	files := []*os.File{stdout}
+	if stderr != stdout {
+		files = append(files, stderr)
+	}
+	var first error
+	for _, f := range files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+		if !remove {
+			continue
+		}
+		if err := os.Remove(f.Name()); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// splitsStderr reports whether a job was started with its stderr split out,
+// which its restores carry on with
+func (db *DB) splitsStderr(jobID string) bool {
+	if jobID == "" {
+		return false
+	}
+	segments, err := db.GetLogSegments(jobID)
+	if err != nil || len(segments) == 0 {
+		return false
+	}
+	return segments[len(segments)-1].Stderr != ""
+}
+
+// logTailer reads one output stream (stdout or stderr) of a job as a single
+// logical file, stitched together from every segment the job has had, so
+// offsets and follow mode carry on across restores.
+type logTailer struct {
+	source   string // "stdout" or "stderr"
+	segments func() ([]*task.LogSegment, error)
+	send     func(*task.LogStreamingArgs) error
+}
+
+func (t *logTailer) path(seg *task.LogSegment) string {
+	if t.source == "stderr" {
+		return seg.Stderr
+	}
+	return seg.Stdout
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// run streams from offset (negative counts back from the current end) until
+// the last segment is exhausted, or forever if follow is set.
+func (t *logTailer) run(ctx context.Context, offset int64, follow bool) error {
+	segments, err := t.segments()
+	if err != nil {
+		return err
+	}
+
+	if offset < 0 {
+		var total int64
+		for _, seg := range segments {
+			total += fileSize(t.path(seg))
+		}
+		offset += total
+		if offset < 0 {
+			offset = 0
+		}
+	}
+
+	// base is the logical offset at which segments[i] starts
+	var base int64
+	buf := make([]byte, logChunkSize)
+
+	for i := 0; ; i++ {
+		for i >= len(segments) {
+			if !follow {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(logPollInterval):
+			}
+			if segments, err = t.segments(); err != nil {
+				return err
+			}
+		}
+
+		path := t.path(segments[i])
+		if path == "" {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			// rotated away or never created; nothing to read from this segment
+			continue
+		}
+
+		pos := int64(0)
+		if offset > base {
+			pos, err = f.Seek(offset-base, io.SeekStart)
+			if err != nil {
+				f.Close()
+				return err
+			}
+		}
+
+		for {
+			n, rerr := f.Read(buf)
+			if n > 0 {
+				msg := &task.LogStreamingArgs{
+					Timestamp: time.Now().Local().Format(time.RFC3339),
+					Source:    t.source,
+					Level:     "INFO",
+					Msg:       string(buf[:n]),
+					Offset:    base + pos,
+				}
+				if t.source == "stderr" {
+					msg.Level = "ERROR"
+				}
+				if err := t.send(msg); err != nil {
+					f.Close()
+					return err
+				}
+				pos += int64(n)
+				continue
+			}
+			if rerr != nil && rerr != io.EOF {
+				f.Close()
+				return rerr
+			}
+
+			// at EOF: move on if a newer segment exists, otherwise wait for more
+			if i+1 < len(segments) || !follow {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				f.Close()
+				return nil
+			case <-time.After(logPollInterval):
+			}
+			if segments, err = t.segments(); err != nil {
+				f.Close()
+				return err
+			}
+		}
+
+		// the offset may have been seeked past the end of this segment, so
+		// advance by what the segment actually holds
+		length := pos
+		if info, err := f.Stat(); err == nil && info.Size() < length {
+			length = info.Size()
+		}
+		f.Close()
+		base += length
+	}
+}
+
+// StreamJobLogs tails a job's stdout/stderr, across restores.
+func (s *service) StreamJobLogs(args *task.StreamJobLogsArgs, stream task.TaskService_StreamJobLogsServer) error {
+	if args.JobID == "" {
+		return status.Error(codes.InvalidArgument, "job id cannot be empty")
+	}
+
+	if _, err := s.client.db.GetLogSegments(args.JobID); err != nil {
+		return status.Errorf(codes.NotFound, "no logs for job %s: %v", args.JobID, err)
+	}
+
+	var sources []string
+	switch args.Stream {
+	case task.StreamJobLogsArgs_STDOUT:
+		sources = []string{"stdout"}
+	case task.StreamJobLogsArgs_STDERR:
+		sources = []string{"stderr"}
+	default:
+		sources = []string{"stdout", "stderr"}
+	}
+
+	ctx := stream.Context()
+
+	// grpc streams aren't safe for concurrent Send
+	var sendMu sync.Mutex
+	send := func(msg *task.LogStreamingArgs) error {
+		msg.JobID = args.JobID
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(msg)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(sources))
+	for _, source := range sources {
+		tailer := &logTailer{
+			source: source,
+			segments: func() ([]*task.LogSegment, error) {
+				return s.client.db.GetLogSegments(args.JobID)
+			},
+			send: send,
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- tailer.run(ctx, args.Offset, args.Follow)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+)
+
+// tailerFixture serves a mutable segment list, as the DB would across restores
+type tailerFixture struct {
+	mu       sync.Mutex
+	segments []*task.LogSegment
+	out      strings.Builder
+	offsets  []int64
+}
+
+func (f *tailerFixture) addSegment(t *testing.T, stdout string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.log")
+	if err := os.WriteFile(path, []byte(stdout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	f.segments = append(f.segments, &task.LogSegment{Stdout: path})
+	f.mu.Unlock()
+	return path
+}
+
+func (f *tailerFixture) tailer() *logTailer {
+	return &logTailer{
+		source: "stdout",
+		segments: func() ([]*task.LogSegment, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return append([]*task.LogSegment(nil), f.segments...), nil
+		},
+		send: func(msg *task.LogStreamingArgs) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.out.WriteString(msg.Msg)
+			f.offsets = append(f.offsets, msg.Offset)
+			return nil
+		},
+	}
+}
+
+func (f *tailerFixture) output() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.out.String()
+}
+
+func TestLogTailer_Offsets(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int64
+		want   string
+	}{
+		{"from start", 0, "before restore\nafter restore\n"},
+		{"inside first segment", 7, "restore\nafter restore\n"},
+		{"inside second segment", 21, "restore\n"},
+		{"from end", -8, "restore\n"},
+		{"before start", -1000, "before restore\nafter restore\n"},
+		{"past end", 1000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f tailerFixture
+			f.addSegment(t, "before restore\n")
+			f.addSegment(t, "after restore\n")
+
+			if err := f.tailer().run(context.Background(), tt.offset, false); err != nil {
+				t.Fatal(err)
+			}
+			if got := f.output(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogTailer_OffsetsAreLogical(t *testing.T) {
+	var f tailerFixture
+	f.addSegment(t, "0123456789")
+	f.addSegment(t, "abc")
+
+	if err := f.tailer().run(context.Background(), 0, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.offsets) != 2 || f.offsets[0] != 0 || f.offsets[1] != 10 {
+		t.Errorf("expected chunks at logical offsets [0 10], got %v", f.offsets)
+	}
+}
+
+func TestLogTailer_FollowAcrossRestore(t *testing.T) {
+	var f tailerFixture
+	first := f.addSegment(t, "one\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- f.tailer().run(ctx, 0, true) }()
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for f.output() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %q, have %q", want, f.output())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	waitFor("one\n")
+
+	// the running process keeps writing
+	file, err := os.OpenFile(first, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("two\n")
+	file.Close()
+	waitFor("one\ntwo\n")
+
+	// then gets checkpointed and restored into a new log file
+	f.addSegment(t, "three\n")
+	waitFor("one\ntwo\nthree\n")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartTask_LogOutput(t *testing.T) {
+	client, err := setup(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	waitFor := func(path, want string) {
+		t.Helper()
+		var data []byte
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if data, _ = os.ReadFile(path); string(data) == want {
+				return
+			}
+		}
+		t.Errorf("%s: got %q, want %q", filepath.Base(path), data, want)
+	}
+
+	for _, split := range []bool{false, true} {
+		log := filepath.Join(dir, fmt.Sprintf("split-%v.log", split))
+		_, err := client.StartTask(ctx, &task.StartTaskArgs{
+			Task:          "echo out; echo err >&2",
+			Id:            fmt.Sprintf("log-output-test-%d-%v", os.Getpid(), split),
+			LogOutputFile: log,
+			UID:           uint32(os.Getuid()),
+			GID:           uint32(os.Getgid()),
+			SplitStderr:   split,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !split {
+			// both streams in the one file, as ever
+			waitFor(log, "out\nerr\n")
+			if _, err := os.Stat(stderrLogPath(log)); !os.IsNotExist(err) {
+				t.Errorf("expected no separate stderr log unless asked for, got %v", err)
+			}
+			continue
+		}
+		waitFor(log, "out\n")
+		waitFor(stderrLogPath(log), "err\n")
+	}
+}
+
+func TestCloseRestoreLogs(t *testing.T) {
+	for _, split := range []bool{false, true} {
+		stdout, stderr, err := newRestoreLogs(split)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (stdout == stderr) == split {
+			t.Fatalf("split %v: expected stderr to be its own file only when split", split)
+		}
+		// a shared file is closed and removed once, without errors
+		if err := closeRestoreLogs(stdout, stderr, true); err != nil {
+			t.Errorf("split %v: %v", split, err)
+		}
+		for _, f := range []*os.File{stdout, stderr} {
+			if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
+				t.Errorf("split %v: expected %s to be removed, got %v", split, f.Name(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	defaultPreemptionPollInterval = 5 * time.Second
+	defaultPreemptionParallel     = 2
+	defaultPreemptionReportPath   = "/var/log/cedana-emergency-checkpoint.json"
+	// spot providers typically give two minutes' warning; assumed when a
+	// notice doesn't say when the instance goes
+	defaultPreemptionLeadTime = 2 * time.Minute
+	// with less time than this left, checkpoints skip compression
+	emergencyCompressionCutoff = time.Minute
+)
+
+const (
+	noticeTermination = "termination"
+	noticeRebalance   = "rebalance"
+)
+
+type preemptionNotice struct {
+	Kind     string    `json:"kind"`
+	Action   string    `json:"action,omitempty"`
+	Deadline time.Time `json:"deadline"`
+	Source   string    `json:"source"`
+}
+
+// imdsNotice is the JSON served by EC2-style metadata endpoints, covering both
+// spot/instance-action ({"action", "time"}) and rebalance recommendations
+// ({"noticeTime"}).
+type imdsNotice struct {
+	Action     string `json:"action"`
+	Time       string `json:"time"`
+	NoticeTime string `json:"noticeTime"`
+}
+
+func parseNotice(data []byte, source string, now time.Time) (*preemptionNotice, error) {
+	notice := &preemptionNotice{
+		Kind:     noticeTermination,
+		Deadline: now.Add(defaultPreemptionLeadTime),
+		Source:   source,
+	}
+
+	// an empty notice file is still a notice
+	if len(bytes.TrimSpace(data)) == 0 {
+		return notice, nil
+	}
+
+	var raw imdsNotice
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse notice from %s: %w", source, err)
+	}
+
+	switch {
+	case raw.Action != "":
+		notice.Action = raw.Action
+		if raw.Time != "" {
+			deadline, err := time.Parse(time.RFC3339, raw.Time)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse notice time %q from %s: %w", raw.Time, source, err)
+			}
+			notice.Deadline = deadline
+		}
+	case raw.NoticeTime != "":
+		notice.Kind = noticeRebalance
+	default:
+		return nil, fmt.Errorf("notice from %s has neither an action nor a noticeTime", source)
+	}
+
+	return notice, nil
+}
+
+// fetchMetadataNotice polls an IMDS-style endpoint, which answers 404 until
+// there's a notice.
+func fetchMetadataNotice(ctx context.Context, client *http.Client, url string) (*preemptionNotice, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("metadata endpoint %s returned %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+	return parseNotice(data, url, time.Now())
+}
+
+// readNoticeFile treats the file appearing as the notice.
+func readNoticeFile(path string) (*preemptionNotice, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseNotice(data, path, time.Now())
+}
+
+// preemptionWatcher checks for spot interruption or rebalance notices and,
+// on the first one, checkpoints every running job.
+type preemptionWatcher struct {
+	svc  *service
+	cfg  utils.Preemption
+	http *http.Client
+}
+
+func newPreemptionWatcher(svc *service, cfg utils.Preemption) *preemptionWatcher {
+	return &preemptionWatcher{
+		svc:  svc,
+		cfg:  cfg,
+		http: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// check looks for a notice from every configured source. A source that can't
+// be read doesn't hide a notice from another; its error is only returned if
+// no notice was found.
+func (w *preemptionWatcher) check(ctx context.Context) (*preemptionNotice, error) {
+	var errs []error
+	if w.cfg.MetadataURL != "" {
+		notice, err := fetchMetadataNotice(ctx, w.http, w.cfg.MetadataURL)
+		if notice != nil {
+			return notice, nil
+		}
+		errs = append(errs, err)
+	}
+	if w.cfg.NoticeFile != "" {
+		notice, err := readNoticeFile(w.cfg.NoticeFile)
+		if notice != nil {
+			return notice, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// run polls until a notice arrives and the emergency checkpoint is done, or
+// ctx is cancelled. The instance is going away after a notice, so there's
+// nothing left to watch for.
+func (w *preemptionWatcher) run(ctx context.Context) {
+	interval := defaultPreemptionPollInterval
+	if w.cfg.PollInterval > 0 {
+		interval = time.Duration(w.cfg.PollInterval) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		notice, err := w.check(ctx)
+		if err != nil {
+			w.svc.logger.Warn().Err(err).Msg("could not check for preemption notice")
+		} else if notice != nil {
+			w.svc.logger.Warn().Msgf("%s notice from %s, deadline %s: checkpointing all jobs", notice.Kind, notice.Source, notice.Deadline.Format(time.RFC3339))
+			if _, err := w.svc.emergencyCheckpoint(notice, w.cfg); err != nil {
+				w.svc.logger.Error().Err(err).Msg("emergency checkpoint failed")
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type emergencyJobResult struct {
+	JobID          string     `json:"job_id"`
+	Status         string     `json:"status"`
+	CheckpointID   string     `json:"checkpoint_id,omitempty"`
+	CheckpointPath string     `json:"checkpoint_path,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// emergencyReport is written to Preemption.ReportPath as the emergency
+// checkpoint progresses, so whatever picks the jobs up elsewhere can tell
+// which made it even if the instance dies mid-way.
+type emergencyReport struct {
+	Notice             *preemptionNotice     `json:"notice"`
+	StartedAt          time.Time             `json:"started_at"`
+	FinishedAt         *time.Time            `json:"finished_at,omitempty"`
+	SkippedCompression bool                  `json:"skipped_compression"`
+	Jobs               []*emergencyJobResult `json:"jobs"`
+}
+
+func writeEmergencyReport(path string, report *emergencyReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write then rename, so readers never see half a report
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// prioritizeJobs orders jobs for an emergency checkpoint: the configured
+// priority jobs first, then the rest smallest first, so as many jobs as
+// possible make it before the deadline.
+func prioritizeJobs(ids []string, priority []string, size func(id string) uint64) {
+	rank := make(map[string]int, len(priority))
+	for i, id := range priority {
+		rank[id] = i
+	}
+	rankOf := func(id string) int {
+		if r, ok := rank[id]; ok {
+			return r
+		}
+		return len(priority)
+	}
+
+	sizes := make(map[string]uint64, len(ids))
+	for _, id := range ids {
+		sizes[id] = size(id)
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		ri, rj := rankOf(ids[i]), rankOf(ids[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return sizes[ids[i]] < sizes[ids[j]]
+	})
+}
+
+// jobMemory is the resident set size of a job's process, used as a proxy for
+// how long it takes to checkpoint.
+func (s *service) jobMemory(id string) uint64 {
+	pid, err := s.client.db.GetPID(id)
+	if err != nil {
+		return 0
+	}
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return 0
+	}
+	mem, err := p.MemoryInfo()
+	if err != nil {
+		return 0
+	}
+	return mem.RSS
+}
+
+// emergencyCheckpoint checkpoints every running job before notice.Deadline,
+// recording progress in the report as each job finishes.
+func (s *service) emergencyCheckpoint(notice *preemptionNotice, cfg utils.Preemption) (*emergencyReport, error) {
+	reportPath := cfg.ReportPath
+	if reportPath == "" {
+		reportPath = defaultPreemptionReportPath
+	}
+	parallel := cfg.MaxParallel
+	if parallel <= 0 {
+		parallel = defaultPreemptionParallel
+	}
+
+	ids, err := s.runningJobs()
+	if err != nil {
+		return nil, err
+	}
+	prioritizeJobs(ids, cfg.PriorityJobs, s.jobMemory)
+
+	ctx, cancel := terminationContext(notice.Deadline.Unix())
+	defer cancel()
+
+	opts := checkpointOpts{parallel: parallel, reason: instanceTermination}
+	report := &emergencyReport{
+		Notice:    notice,
+		StartedAt: time.Now(),
+	}
+	if time.Until(notice.Deadline) < emergencyCompressionCutoff {
+		opts.compression = "none"
+		report.SkippedCompression = true
+	}
+
+	results := make(map[string]*emergencyJobResult, len(ids))
+	for _, id := range ids {
+		result := &emergencyJobResult{JobID: id, Status: "PENDING"}
+		results[id] = result
+		report.Jobs = append(report.Jobs, result)
+	}
+
+	if err := writeEmergencyReport(reportPath, report); err != nil {
+		s.logger.Warn().Err(err).Msgf("could not write emergency report to %s", reportPath)
+	}
+
+	s.checkpointJobs(ctx, ids, opts, func(resp *task.MetaStateStreamingResp) {
+		result := results[resp.JobID]
+		result.Status = resp.Status
+		result.CheckpointID = resp.CheckpointID
+		result.CheckpointPath = resp.CheckpointPath
+		result.Error = resp.Error
+		finished := time.Now()
+		result.FinishedAt = &finished
+
+		if resp.Status == metaStatusCheckpointFailed {
+			s.logger.Error().Msgf("could not checkpoint job %s before %s: %s", resp.JobID, notice.Kind, resp.Error)
+		}
+		if err := writeEmergencyReport(reportPath, report); err != nil {
+			s.logger.Warn().Err(err).Msgf("could not write emergency report to %s", reportPath)
+		}
+	})
+
+	finished := time.Now()
+	report.FinishedAt = &finished
+	return report, writeEmergencyReport(reportPath, report)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+)
+
+func TestParseNotice(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		data     string
+		kind     string
+		deadline time.Time
+		wantErr  bool
+	}{
+		{"spot interruption", `{"action": "terminate", "time": "2024-01-01T12:01:30Z"}`, noticeTermination, now.Add(90 * time.Second), false},
+		{"no time given", `{"action": "stop"}`, noticeTermination, now.Add(defaultPreemptionLeadTime), false},
+		{"rebalance", `{"noticeTime": "2024-01-01T11:59:00Z"}`, noticeRebalance, now.Add(defaultPreemptionLeadTime), false},
+		{"empty file", "\n", noticeTermination, now.Add(defaultPreemptionLeadTime), false},
+		{"garbage", "<html>", "", time.Time{}, true},
+		{"bad time", `{"action": "terminate", "time": "soon"}`, "", time.Time{}, true},
+		{"unknown shape", `{"state": "ok"}`, "", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notice, err := parseNotice([]byte(tt.data), "test", now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", notice)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if notice.Kind != tt.kind || !notice.Deadline.Equal(tt.deadline) {
+				t.Errorf("got %s by %v, want %s by %v", notice.Kind, notice.Deadline, tt.kind, tt.deadline)
+			}
+		})
+	}
+}
+
+func TestPrioritizeJobs(t *testing.T) {
+	sizes := map[string]uint64{"big": 300, "medium": 200, "small": 100, "vip": 1000}
+	ids := []string{"big", "medium", "small", "vip"}
+
+	prioritizeJobs(ids, []string{"vip"}, func(id string) uint64 { return sizes[id] })
+
+	want := []string{"vip", "small", "medium", "big"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("got order %v, want %v", ids, want)
+		}
+	}
+}
+
+// A stub metadata service answers 404 for a while, then serves a spot
+// interruption notice; the watcher should checkpoint every job and report.
+func TestPreemptionWatcher_MetadataNotice(t *testing.T) {
+	var polls int32
+	deadline := time.Now().Add(30 * time.Second).UTC().Truncate(time.Second)
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"action": "terminate", "time": %q}`, deadline.Format(time.RFC3339))
+	}))
+	defer imds.Close()
+
+	c, err := InstantiateClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := utils.GetLogger()
+
+	var mu sync.Mutex
+	var order []string
+	svc := &service{client: c, logger: &logger}
+	svc.dump = func(ctx context.Context, args *task.DumpArgs) (*task.DumpResp, error) {
+		if args.Compression != "none" {
+			t.Errorf("expected compression to be skipped with %v left, got %q", time.Until(deadline), args.Compression)
+		}
+		mu.Lock()
+		order = append(order, args.JobID)
+		mu.Unlock()
+		return &task.DumpResp{CheckpointID: "ckpt-" + args.JobID}, nil
+	}
+
+	tmp := t.TempDir()
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("preemption-test-%d-%d", os.Getpid(), i)
+		resp, err := svc.StartTask(context.Background(), &task.StartTaskArgs{
+			Task:          "sleep 60",
+			Id:            id,
+			LogOutputFile: filepath.Join(tmp, id+".log"),
+			UID:           uint32(os.Getuid()),
+			GID:           uint32(os.Getgid()),
+		})
+		if err != nil {
+			t.Fatalf("failed to start task: %v", err)
+		}
+		t.Cleanup(func() { syscall.Kill(int(resp.PID), syscall.SIGKILL) })
+		ids = append(ids, id)
+	}
+
+	reportPath := filepath.Join(tmp, "report.json")
+	watcher := newPreemptionWatcher(svc, utils.Preemption{
+		MetadataURL:  imds.URL,
+		PollInterval: 1,
+		PriorityJobs: []string{ids[2]},
+		MaxParallel:  1,
+		ReportPath:   reportPath,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	watcher.run(ctx)
+	if ctx.Err() != nil {
+		t.Fatal("watcher never acted on the notice")
+	}
+
+	// other tests' jobs may be running too, but the priority job goes first
+	if len(order) == 0 || order[0] != ids[2] {
+		t.Errorf("expected %s to be checkpointed first, got %v", ids[2], order)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report emergencyReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.FinishedAt == nil || !report.SkippedCompression || !report.Notice.Deadline.Equal(deadline) {
+		t.Errorf("unexpected report: %s", data)
+	}
+	statuses := map[string]string{}
+	for _, job := range report.Jobs {
+		statuses[job.JobID] = job.Status
+	}
+	for _, id := range ids {
+		if statuses[id] != metaStatusCheckpointed {
+			t.Errorf("job %s: expected %s in report, got %q", id, metaStatusCheckpointed, statuses[id])
+		}
+	}
+}
+
+func TestPreemptionWatcher_NoticeFile(t *testing.T) {
+	noticeFile := filepath.Join(t.TempDir(), "notice")
+	watcher := &preemptionWatcher{cfg: utils.Preemption{NoticeFile: noticeFile}}
+
+	notice, err := watcher.check(context.Background())
+	if err != nil || notice != nil {
+		t.Fatalf("expected no notice before the file exists, got %v, %v", notice, err)
+	}
+
+	if err := os.WriteFile(noticeFile, []byte(`{"noticeTime": "2024-01-01T12:00:00Z"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	notice, err = watcher.check(context.Background())
+	if err != nil || notice == nil || notice.Kind != noticeRebalance {
+		t.Fatalf("expected rebalance notice, got %v, %v", notice, err)
+	}
+}
+
+func TestPreemptionWatcher_MetadataDown(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer metadata.Close()
+
+	noticeFile := filepath.Join(t.TempDir(), "notice")
+	watcher := newPreemptionWatcher(nil, utils.Preemption{MetadataURL: metadata.URL, NoticeFile: noticeFile})
+
+	if notice, err := watcher.check(context.Background()); err == nil || notice != nil {
+		t.Fatalf("expected the endpoint's error with no notice anywhere, got %v, %v", notice, err)
+	}
+
+	// a failing endpoint doesn't hide the notice file
+	if err := os.WriteFile(noticeFile, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	notice, err := watcher.check(context.Background())
+	if err != nil || notice == nil || notice.Source != noticeFile {
+		t.Fatalf("expected the file's notice, got %v, %v", notice, err)
+	}
+}
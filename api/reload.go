@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// restartOnlyConfig are the keys, or sections of them, the daemon only reads
+// when it starts. Everything else is read as requests need it, so a reload
+// takes effect:
+//
+//   - client.log_level at once
+//   - client, connection and shared_storage from the next dump or restore,
+//     client.state_stream_interval from the next state stream
+//   - store, encryption and signing from the next checkpoint pushed or
+//     pulled
+//   - gpu.enabled from the next dump or restore, though the GPU controller
+//     binaries are only pulled at startup
+var restartOnlyConfig = []string{"preemption.", "otel.", "kubernetes."}
+
+// reloadConfig loads the config again and swaps it in. If it doesn't load or
+// isn't valid, the current config is kept.
+func (s *service) reloadConfig() (*task.ReloadConfigResp, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cfg, err := utils.InitConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.SetLogLevel(cfg.Client.LogLevel); err != nil {
+		return nil, err
+	}
+	old := s.client.cfg.Swap(cfg)
+
+	resp := &task.ReloadConfigResp{Changed: utils.ChangedKeys(old, cfg)}
+	for _, key := range resp.Changed {
+		for _, prefix := range restartOnlyConfig {
+			if strings.HasPrefix(key, prefix) {
+				resp.RestartRequired = append(resp.RestartRequired, key)
+				break
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (s *service) ReloadConfig(ctx context.Context, args *task.ReloadConfigArgs) (*task.ReloadConfigResp, error) {
+	resp, err := s.reloadConfig()
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("keeping the current config: %v", err))
+	}
+	s.logReload(resp)
+	return resp, nil
+}
+
+// reloadOnSignal reloads the config whenever a signal, i.e. SIGHUP, arrives on
+// signals, until ctx is done.
+func (s *service) reloadOnSignal(ctx context.Context, signals <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			resp, err := s.reloadConfig()
+			if err != nil {
+				s.logger.Error().Err(err).Msg("keeping the current config")
+				continue
+			}
+			s.logReload(resp)
+		}
+	}
+}
+
+func (s *service) logReload(resp *task.ReloadConfigResp) {
+	s.logger.Info().Strs("changed", resp.Changed).Msg("config reloaded")
+	if len(resp.RestartRequired) > 0 {
+		s.logger.Warn().Strs("keys", resp.RestartRequired).Msg("config changes that only take effect once the daemon restarts")
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReloadConfig(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "client_config.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"client": {"compression": "lz4"}}`)
+	utils.SetConfigOptions(utils.ConfigOptions{SystemFile: filepath.Join(dir, "none"), UserFile: path, Env: []string{}})
+	level := zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		utils.SetConfigOptions(utils.ConfigOptions{})
+		zerolog.SetGlobalLevel(level)
+	})
+
+	c, err := InstantiateClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := utils.GetLogger()
+	svc := &service{client: c, logger: &logger}
+	before := c.config()
+
+	write(`{"client": {"compression": "zstd"}, "preemption": {"poll_interval": 10}}`)
+	resp, err := svc.ReloadConfig(ctx, &task.ReloadConfigArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(resp.Changed, " ") != "client.compression preemption.poll_interval" ||
+		strings.Join(resp.RestartRequired, " ") != "preemption.poll_interval" {
+		t.Errorf("unexpected changes reported: %v, restart required for %v", resp.Changed, resp.RestartRequired)
+	}
+	if c.config().Client.Compression != "zstd" || before.Client.Compression != "lz4" {
+		t.Errorf("expected a new snapshot, leaving the old one as it was")
+	}
+
+	write(`{"client": {"compression": "brotli"}}`)
+	if _, err := svc.ReloadConfig(ctx, &task.ReloadConfigArgs{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected an invalid config to be refused, got %v", err)
+	}
+	if c.config().Client.Compression != "zstd" {
+		t.Errorf("expected the current config to be kept, got %q", c.config().Client.Compression)
+	}
+
+	write(`{"client": {"compression": "gzip", "log_level": "warn"}}`)
+	signals := make(chan os.Signal)
+	sctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go svc.reloadOnSignal(sctx, signals)
+	signals <- syscall.SIGHUP
+	for deadline := time.Now().Add(5 * time.Second); c.config().Client.Compression != "gzip"; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected SIGHUP to reload the config")
+		}
+	}
+	if zerolog.GlobalLevel() != zerolog.WarnLevel {
+		t.Errorf("expected the log level to change live, got %v", zerolog.GlobalLevel())
+	}
+}
@@ -30,7 +30,11 @@ import (
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 )
 
-func (c *Client) prepareRestore(ctx context.Context, opts *rpc.CriuOpts, checkpointPath string) (*string, *task.ProcessState, []*os.File, error) {
+// prepareRestore extracts the checkpoint and sets up fd inheritance so the
+// restored process' stdout/stderr land in the given log files. Once trusted
+// keys are configured, the checkpoint is verified against the signed manifest
+// beside it before anything is extracted.
+func (c *Client) prepareRestore(ctx context.Context, cfg *utils.Config, opts *rpc.CriuOpts, checkpointPath string, stdout, stderr *os.File) (_ *string, _ *task.ProcessState, _ []*os.File, err error) {
 	var isShellJob bool
 	var inheritFds []*rpc.InheritFd
 	var tcpEstablished bool
@@ -38,63 +42,62 @@ func (c *Client) prepareRestore(ctx context.Context, opts *rpc.CriuOpts, checkpo
 
 	_, prepareRestoreSpan := c.tracer.Start(ctx, "prepare_restore")
 	defer prepareRestoreSpan.End()
-	tmpdir := "/tmp/cedana_restore"
-
-	// check if tmpdir exists
-	if _, err := os.Stat(tmpdir); os.IsNotExist(err) {
-		err := os.Mkdir(tmpdir, 0755)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-	} else {
-		// likely an old checkpoint hanging around, delete
-		err := os.RemoveAll(tmpdir)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		err = os.Mkdir(tmpdir, 0755)
+	// each restore gets its own workspace so concurrent restores don't extract
+	// over each other; Restore removes it once CRIU is done with it. Decrypted,
+	// the images are the process's memory in the clear, so the workspace is
+	// private to us (MkdirTemp's 0700), while what's extracted into it keeps the
+	// modes the archive recorded.
+	tmpdir, err := os.MkdirTemp("", "cedana_restore_")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, nil, nil, err
+			os.RemoveAll(tmpdir)
 		}
+	}()
+
+	trusted, err := utils.NewTrustedKeys(cfg.Signing)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	tarOpts := []utils.TarOption{c.tarDecryption(ctx, cfg), utils.WithThreads(cfg.Client.CompressionThreads), utils.WithTrustedKeys(trusted),
+		utils.WithImageStreamer(cfg.Store.ImageStreamer)}
 
 	c.logger.Info().Msgf("decompressing %s to %s", checkpointPath, tmpdir)
-	err := utils.UntarFolder(checkpointPath, tmpdir)
+	err = utils.UntarFolder(ctx, checkpointPath, tmpdir, tarOpts...)
 
 	if err != nil {
-		c.logger.Fatal().Err(err).Msg("error decompressing checkpoint")
+		c.logger.Error().Err(err).Msg("error decompressing checkpoint")
+		return nil, nil, nil, err
 	}
 
 	// read serialized cedanaCheckpoint
 	_, err = os.Stat(filepath.Join(tmpdir, "checkpoint_state.json"))
 	if err != nil {
-		c.logger.Fatal().Err(err).Msg("checkpoint_state.json not found, likely error in creating checkpoint")
+		c.logger.Error().Err(err).Msg("checkpoint_state.json not found, likely error in creating checkpoint")
 		return nil, nil, nil, err
 	}
 
 	data, err := os.ReadFile(filepath.Join(tmpdir, "checkpoint_state.json"))
 	if err != nil {
-		c.logger.Fatal().Err(err).Msg("error reading checkpoint_state.json")
+		c.logger.Error().Err(err).Msg("error reading checkpoint_state.json")
 		return nil, nil, nil, err
 	}
 
 	var checkpointState task.ProcessState
 	err = json.Unmarshal(data, &checkpointState)
 	if err != nil {
-		c.logger.Fatal().Err(err).Msg("error unmarshaling checkpoint_state.json")
+		c.logger.Error().Err(err).Msg("error unmarshaling checkpoint_state.json")
 		return nil, nil, nil, err
 	}
 
-	open_fds := checkpointState.ProcessInfo.OpenFds
-
-	// create logfile for redirection
-	filename := fmt.Sprintf("/var/log/cedana-output-%s.log", fmt.Sprint(time.Now().Unix()))
-	file, err := os.Create(filename)
-	if err != nil {
-		c.logger.Fatal().Err(err).Msg("error creating logfile")
-		return nil, nil, nil, err
+	if checkpointState.ProcessInfo == nil {
+		return nil, nil, nil, fmt.Errorf("checkpoint_state.json has no process info")
 	}
 
+	open_fds := checkpointState.ProcessInfo.OpenFds
+
 	for _, f := range open_fds {
 		if strings.Contains(f.Path, "pts") {
 			isShellJob = true
@@ -104,6 +107,10 @@ func (c *Client) prepareRestore(ctx context.Context, opts *rpc.CriuOpts, checkpo
 			// strip leading slash from f
 			f.Path = strings.TrimPrefix(f.Path, "/")
 
+			file := stdout
+			if f.Stream == task.OpenFilesStat_STDERR {
+				file = stderr
+			}
 			extraFiles = append(extraFiles, file)
 			inheritFds = append(inheritFds, &rpc.InheritFd{
 				Fd:  proto.Int32(2 + int32(len(extraFiles))),
@@ -123,20 +130,47 @@ func (c *Client) prepareRestore(ctx context.Context, opts *rpc.CriuOpts, checkpo
 	opts.InheritFd = inheritFds
 	opts.TcpEstablished = proto.Bool(tcpEstablished)
 
-	if err := chmodRecursive(tmpdir, 0o777); err != nil {
-		c.logger.Fatal().Err(err).Msg("error changing permissions")
-		return nil, nil, nil, err
-	}
-
 	return &tmpdir, &checkpointState, extraFiles, nil
 }
 
+// tarDecryption lets encrypted checkpoints be restored whenever a key is
+// configured, whether or not new checkpoints are being encrypted. The key
+// provider is only set up once an encrypted checkpoint turns up.
+func (c *Client) tarDecryption(ctx context.Context, cfg *utils.Config) utils.TarOption {
+	return utils.WithDecryption(func(keyID string) ([]byte, error) {
+		keys, err := utils.NewKeyProvider(cfg.Encryption)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint is encrypted: %w", err)
+		}
+		return keys.Key(ctx, keyID)
+	})
+}
+
+// refuseUnverifiable refuses restores from checkpoints that can't carry a
+// signature, like runc image dirs and containerd images, once trusted keys
+// are configured and restores must be from a trusted checkpoint.
+func (c *Client) refuseUnverifiable(cfg *utils.Config, what string) error {
+	trusted, err := utils.NewTrustedKeys(cfg.Signing)
+	if err != nil {
+		return err
+	}
+	if trusted != nil {
+		return fmt.Errorf("%w: %s can't be verified, and signing.trusted_keys only allows verified restores", utils.ErrUntrustedCheckpoint, what)
+	}
+	return nil
+}
+
 // chmodRecursive changes the permissions of the given path and all its contents.
+// Symlinks are skipped, as chmod would follow them, and ones extracted from a
+// checkpoint can point anywhere.
 func chmodRecursive(path string, mode os.FileMode) error {
 	return filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
 		return os.Chmod(filePath, mode)
 	})
 }
@@ -204,13 +238,14 @@ func (c *Client) criuRestore(ctx context.Context, opts *rpc.CriuOpts, nfy Notify
 
 	img, err := os.Open(dir)
 	if err != nil {
-		c.logger.Fatal().Err(err).Msg("could not open directory")
+		c.logger.Error().Err(err).Msg("could not open directory")
+		return nil, err
 	}
 	defer img.Close()
 
 	opts.ImagesDirFd = proto.Int32(int32(img.Fd()))
 
-	resp, err := c.CRIU.Restore(opts, &nfy, extraFiles)
+	resp, err := c.CRIU.Restore(ctx, opts, &nfy, extraFiles)
 	if err != nil {
 		// cleanup along the way
 		os.RemoveAll(dir)
@@ -558,15 +593,32 @@ func (c *Client) Restore(ctx context.Context, args *task.RestoreArgs) (*int32, e
 	var dir *string
 	var pid *int32
 
+	cfg := c.config()
 	opts := c.prepareRestoreOpts()
 	nfy := Notify{
 		Logger: c.logger,
 	}
 
-	dir, state, extraFiles, err := c.prepareRestore(ctx, opts, args.CheckpointPath)
+	// the restored process inherits its own copies of these, so the daemon's
+	// can be closed once CRIU is done
+	split := c.db.splitsStderr(args.JobID)
+	stdout, stderr, err := newRestoreLogs(split)
 	if err != nil {
+		c.logger.Error().Err(err).Msg("error creating logfile")
 		return nil, err
 	}
+	restored := false
+	defer func() {
+		if err := closeRestoreLogs(stdout, stderr, !restored); err != nil {
+			c.logger.Warn().Err(err).Msg("could not clean up restore log files")
+		}
+	}()
+
+	dir, state, extraFiles, err := c.prepareRestore(ctx, cfg, opts, args.CheckpointPath, stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(*dir)
 
 	var gpuCmd *exec.Cmd
 	if state.GPUCheckpointed {
@@ -584,6 +636,21 @@ func (c *Client) Restore(ctx context.Context, args *task.RestoreArgs) (*int32, e
 	if err != nil {
 		return nil, err
 	}
+	restored = true
+
+	if args.JobID != "" {
+		segment := &task.LogSegment{
+			Stdout:    stdout.Name(),
+			PID:       *pid,
+			StartedAt: time.Now().Unix(),
+		}
+		if split {
+			segment.Stderr = stderr.Name()
+		}
+		if err := c.db.AppendLogSegment(args.JobID, segment); err != nil {
+			c.logger.Warn().Err(err).Msgf("could not record log files for job %s", args.JobID)
+		}
+	}
 
 	if state.GPUCheckpointed {
 		go func() {
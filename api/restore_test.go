@@ -0,0 +1,28 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChmodRecursive_SkipsSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(root, "outside")
+	os.WriteFile(outside, []byte("secret"), 0o600)
+	images := filepath.Join(root, "images")
+	os.Mkdir(images, 0o755)
+	os.WriteFile(filepath.Join(images, "pages-1.img"), nil, 0o644)
+	// as a hostile checkpoint could have extracted
+	os.Symlink("../outside", filepath.Join(images, "link"))
+
+	if err := chmodRecursive(images, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(outside); fi.Mode() != 0o600 {
+		t.Errorf("expected the file outside to be left alone, got %v", fi.Mode())
+	}
+	if fi, _ := os.Stat(filepath.Join(images, "pages-1.img")); fi.Mode() != 0o700 {
+		t.Errorf("expected the images to be chmod'ed, got %v", fi.Mode())
+	}
+}
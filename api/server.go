@@ -3,12 +3,13 @@ package api
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,7 +25,6 @@ import (
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sys/unix"
-	"golang.org/x/time/rate"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -50,167 +50,323 @@ type UploadResponse struct {
 }
 
 type service struct {
-	client            *Client
-	logger            *zerolog.Logger
-	ClientLogStream   task.TaskService_LogStreamingServer
-	ClientStateStream task.TaskService_ClientStateStreamingServer
-	r                 *os.File
-	w                 *os.File
-	task.UnimplementedTaskServiceServer
-}
+	client *Client
+	logger *zerolog.Logger
 
-func (s *service) Dump(ctx context.Context, args *task.DumpArgs) (*task.DumpResp, error) {
-	var pid int32
+	// serializes operations on the same job/container
+	locks jobLocks
 
-	ctx, dumpTracer := s.client.tracer.Start(ctx, "dump-ckpt")
-	dumpTracer.SetAttributes(attribute.String("jobID", args.JobID))
-	defer dumpTracer.End()
-	s.client.jobID = args.JobID
+	// publishes job states to ClientStateStreaming subscribers
+	states stateBroadcaster
 
-	s.r.Close()
-	s.w.Close()
+	// checkpoints jobs for termination notices; nil means s.Dump
+	dump func(context.Context, *task.DumpArgs) (*task.DumpResp, error)
 
-	cfg, err := utils.InitConfig()
+	// serializes config reloads, see reloadConfig
+	reloadMu sync.Mutex
+
+	task.UnimplementedTaskServiceServer
+}
+
+// lockDump claims what a Dump serializes on, returning the release func: the
+// PID being checkpointed, however the request named it, so a dump by job and
+// one by raw PID of the same process exclude each other; and the job, if one
+// is given, so it isn't restored or started underneath the dump.
+func (s *service) lockDump(jobID string, pid int32) (func(), error) {
+	unlockPID, err := s.locks.tryLock(fmt.Sprintf("pid %d", pid), "dump")
 	if err != nil {
-		err = status.Error(codes.Internal, err.Error())
 		return nil, err
 	}
+	if jobID == "" {
+		return unlockPID, nil
+	}
+	unlockJob, err := s.locks.tryLock("job "+jobID, "dump")
+	if err != nil {
+		unlockPID()
+		return nil, err
+	}
+	return func() {
+		unlockJob()
+		unlockPID()
+	}, nil
+}
 
-	store := utils.NewCedanaStore(cfg, s.client.tracer)
-
+// dumpPID resolves the PID a Dump checkpoints: the one given, or the job's.
+// A RetryPush dump checkpoints nothing, and gets the PID its archive was
+// dumped from.
+func (s *service) dumpPID(args *task.DumpArgs) (int32, error) {
+	if args.RetryPush {
+		return s.lastCheckpointArchive(args)
+	}
 	// job vs process checkpointing, where a PID is provided directly
 	if args.PID != 0 {
-		pid = args.PID
-	} else {
-		pid, err = s.client.db.GetPID(args.JobID)
-		if err != nil {
-			err = status.Error(codes.Internal, err.Error())
-			return nil, err
-		}
+		return args.PID, nil
 	}
+	pid, err := s.client.db.GetPID(args.JobID)
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	return pid, nil
+}
 
-	s.client.generateState(pid)
-	var state task.ProcessState
+func (s *service) Dump(ctx context.Context, args *task.DumpArgs) (*task.DumpResp, error) {
+	start := time.Now()
+	// one config for the whole dump, however long it runs
+	cfg := s.client.config()
 
-	state.Flag = task.FlagEnum_JOB_RUNNING
-	state.PID = pid
+	compression, err := s.client.compression(cfg, args.Compression)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-	err = s.client.db.CreateOrUpdateCedanaProcess(args.JobID, &state)
+	pid, err := s.dumpPID(args)
 	if err != nil {
-		err = status.Error(codes.Internal, err.Error())
 		return nil, err
 	}
-
-	err = s.client.Dump(ctx, args.Dir, pid)
+	unlock, err := s.lockDump(args.JobID, pid)
 	if err != nil {
-		st := status.New(codes.Internal, err.Error())
-		dumpTracer.RecordError(st.Err())
-		return nil, st.Err()
+		return nil, err
 	}
+	defer unlock()
+	// state streaming subscribers hear about the outcome right away
+	defer s.states.notify()
 
-	var resp task.DumpResp
-
-	switch args.Type {
-	case task.DumpArgs_LOCAL:
-		resp = task.DumpResp{
-			Message: fmt.Sprintf("Dumped process %d to %s", pid, args.Dir),
-		}
+	ctx, dumpTracer := s.client.tracer.Start(ctx, "dump-ckpt")
+	dumpTracer.SetAttributes(attribute.String("jobID", args.JobID))
+	defer dumpTracer.End()
 
-	case task.DumpArgs_REMOTE:
-		state, err := s.client.db.GetStateFromID(args.JobID)
-		if err != nil {
-			st := status.New(codes.Internal, err.Error())
-			return nil, st.Err()
+	// resolved up front so a misconfigured store or key fails before anything
+	// is dumped
+	var store utils.Store
+	if args.Type == task.DumpArgs_REMOTE {
+		if store, err = s.store(cfg); err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
+	}
+	if _, err := s.client.tarEncryption(ctx, cfg, args.JobID); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if _, err := s.client.nodeKey(cfg); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
 
-		if state == nil {
-			st := status.New(codes.NotFound, fmt.Sprintf("state not found for job %v", args.JobID))
-			st.WithDetails(&errdetails.ErrorInfo{
-				Reason: err.Error(),
-			})
-			return nil, st.Err()
-		}
+	var meta *utils.CheckpointMeta
+	// a RetryPush dump has nothing to dump, the archive the last dump left is
+	// pushed below
+	if !args.RetryPush {
+		s.client.generateState(pid)
+		var state task.ProcessState
 
-		checkpointPath := state.CheckpointPath
+		state.Flag = task.FlagEnum_JOB_RUNNING
+		state.PID = pid
 
-		file, err := os.Open(checkpointPath)
+		err = s.client.db.CreateOrUpdateCedanaProcess(args.JobID, &state)
 		if err != nil {
-			st := status.New(codes.NotFound, "checkpoint zip not found")
-			st.WithDetails(&errdetails.ErrorInfo{
-				Reason: err.Error(),
-			})
-			return nil, st.Err()
+			err = status.Error(codes.Internal, err.Error())
+			return nil, err
 		}
-		defer file.Close()
 
-		fileInfo, err := file.Stat()
+		// streaming stores take the checkpoint as it's archived; any other
+		// store gets the finished archive pushed below
+		if streaming, ok := store.(utils.StreamingStore); ok {
+			meta, err = s.client.DumpToStore(ctx, cfg, args.JobID, args.Dir, pid, compression, streaming)
+		} else {
+			err = s.client.Dump(ctx, cfg, args.JobID, args.Dir, pid, compression)
+		}
 		if err != nil {
-			st := status.New(codes.Internal, "checkpoint zip stat failed")
-			st.WithDetails(&errdetails.ErrorInfo{
-				Reason: err.Error(),
-			})
+			s.markCheckpointFailed(ctx, args, pid, err)
+			st := status.New(checkpointErrCode(ctx), err.Error())
+			dumpTracer.RecordError(st.Err())
 			return nil, st.Err()
 		}
+	}
 
-		// Get the size
-		size := fileInfo.Size()
-
-		// zipFileSize += 4096
-		checkpointFullSize := int64(size)
+	var resp task.DumpResp
 
-		ctx, uploadSpan := s.client.tracer.Start(ctx, "upload-ckpt")
-		multipartCheckpointResp, cid, err := store.CreateMultiPartUpload(ctx, checkpointFullSize)
-		if err != nil {
-			st := status.New(codes.Internal, fmt.Sprintf("CreateMultiPartUpload failed with error: %s", err.Error()))
-			uploadSpan.RecordError(err)
-			return nil, st.Err()
+	switch args.Type {
+	case task.DumpArgs_LOCAL:
+		resp = task.DumpResp{
+			Message: fmt.Sprintf("Dumped process %d to %s", pid, args.Dir),
+		}
+		if state, err := s.client.db.GetStateFromID(args.JobID); err == nil {
+			resp.CheckpointPath = state.CheckpointPath
 		}
 
-		err = store.StartMultiPartUpload(ctx, cid, multipartCheckpointResp, checkpointPath)
+	case task.DumpArgs_REMOTE:
+		state, err := s.client.db.GetStateFromID(args.JobID)
 		if err != nil {
-			st := status.New(codes.Internal, fmt.Sprintf("StartMultiPartUpload failed with error: %s", err.Error()))
-			uploadSpan.RecordError(err)
+			st := status.New(codes.Internal, err.Error())
 			return nil, st.Err()
 		}
 
-		err = store.CompleteMultiPartUpload(ctx, *multipartCheckpointResp, cid)
-		if err != nil {
-			st := status.New(codes.Internal, fmt.Sprintf("CompleteMultiPartUpload failed with error: %s", err.Error()))
-			uploadSpan.RecordError(err)
-			return nil, st.Err()
+		if meta == nil {
+			ctx, uploadSpan := s.client.tracer.Start(ctx, "upload-ckpt")
+			meta, err = store.PushCheckpoint(ctx, args.JobID, state.CheckpointPath)
+			if err != nil {
+				s.markCheckpointFailed(ctx, args, pid, err)
+				st := status.New(checkpointErrCode(ctx), fmt.Sprintf("pushing checkpoint failed with error: %s; the archive is kept at %s, dump the job with --retry-push to push it again", err.Error(), state.CheckpointPath))
+				uploadSpan.RecordError(err)
+				uploadSpan.End()
+				return nil, st.Err()
+			}
+			uploadSpan.End()
 		}
-		uploadSpan.End()
 
-		remoteState := &task.RemoteState{CheckpointID: cid, UploadID: multipartCheckpointResp.UploadID, Timestamp: time.Now().Unix()}
+		remoteState := &task.RemoteState{CheckpointID: meta.ID, UploadID: meta.UploadID, Timestamp: time.Now().Unix()}
 
 		state.RemoteState = append(state.RemoteState, remoteState)
 
 		s.client.db.UpdateProcessStateWithID(args.JobID, state)
 
 		resp = task.DumpResp{
-			Message:      fmt.Sprintf("Dumped process %d to %s, multipart checkpoint id: %s", pid, args.Dir, multipartCheckpointResp.UploadID),
-			CheckpointID: cid,
-			UploadID:     multipartCheckpointResp.UploadID,
+			Message:        fmt.Sprintf("Dumped process %d to %s, checkpoint id: %s", pid, args.Dir, meta.ID),
+			CheckpointID:   meta.ID,
+			UploadID:       meta.UploadID,
+			DedupRatio:     meta.DedupRatio,
+			UploadedBytes:  meta.UploadedBytes,
+			CheckpointPath: state.CheckpointPath,
 		}
+		if meta.Chunks > 0 {
+			resp.Message += fmt.Sprintf(" (%d chunks, %.0f%% already stored)", meta.Chunks, meta.DedupRatio*100)
+		}
+	}
+	resp.PID = pid
+
+	event := &task.JobEvent{
+		Type:           task.JobEvent_CHECKPOINT,
+		PID:            pid,
+		CheckpointPath: resp.CheckpointPath,
+		CheckpointID:   resp.CheckpointID,
+		Size:           checkpointSize(resp.CheckpointPath),
+		DurationMS:     time.Since(start).Milliseconds(),
+		Reason:         args.Reason,
+	}
+	if meta != nil && meta.Size > 0 {
+		event.Size = int64(meta.Size)
 	}
+	s.recordEvent(args.JobID, event)
 
 	return &resp, nil
 }
 
-func (s *service) Restore(ctx context.Context, args *task.RestoreArgs) (*task.RestoreResp, error) {
+// lastCheckpointArchive checks that a RetryPush dump has an archive to push,
+// and returns the PID it was dumped from.
+func (s *service) lastCheckpointArchive(args *task.DumpArgs) (int32, error) {
+	if args.Type != task.DumpArgs_REMOTE {
+		return 0, status.Error(codes.InvalidArgument, "only a REMOTE dump can retry a push")
+	}
+	state, err := s.client.db.GetStateFromID(args.JobID)
+	if err != nil {
+		return 0, status.Error(codes.NotFound, err.Error())
+	}
+	if state.CheckpointPath == "" {
+		return 0, status.Errorf(codes.FailedPrecondition, "job %s has no checkpoint archive to push", args.JobID)
+	}
+	if _, err := os.Stat(state.CheckpointPath); err != nil {
+		return 0, status.Errorf(codes.FailedPrecondition, "archive of the last checkpoint of job %s: %v", args.JobID, err)
+	}
+	return state.PID, nil
+}
+
+// store returns the checkpoint store cfg configures. cfg is the config the
+// client holds, not re-read from disk; re-reading it per request would
+// rewrite the file underneath concurrent requests.
+func (s *service) store(cfg *utils.Config) (utils.Store, error) {
+	return utils.NewStore(cfg, s.client.tracer)
+}
+
+// markCheckpointFailed records CHECKPOINT_FAILED against the dump's job. A dump
+// that died because the caller cancelled is recorded with reason "cancelled".
+func (s *service) markCheckpointFailed(ctx context.Context, args *task.DumpArgs, pid int32, err error) {
+	jobID := args.JobID
+	reason := err.Error()
+	if ctx.Err() != nil {
+		reason = "cancelled"
+	}
+
+	state, dberr := s.client.db.GetStateFromID(jobID)
+	if dberr != nil || state.PID != pid {
+		state = &task.ProcessState{PID: pid, Flag: task.FlagEnum_JOB_RUNNING}
+	}
+
+	state.CheckpointState = task.CheckpointState_CHECKPOINT_FAILED
+	state.CheckpointFailureReason = reason
+
+	if dberr := s.client.db.CreateOrUpdateCedanaProcess(jobID, state); dberr != nil {
+		s.logger.Warn().Msgf("could not record failed checkpoint for job %s: %v", jobID, dberr)
+	}
+	s.recordEvent(jobID, &task.JobEvent{Type: task.JobEvent_CHECKPOINT_FAILED, PID: pid, Error: reason, Reason: args.Reason})
+}
+
+// checkpointErrCode maps a failed checkpoint/restore to codes.Canceled if the
+// caller went away, and codes.Internal otherwise.
+func checkpointErrCode(ctx context.Context) codes.Code {
+	if ctx.Err() != nil {
+		return codes.Canceled
+	}
+	return codes.Internal
+}
+
+// restoreErrCode is checkpointErrCode for fetching and verifying the
+// checkpoint to restore: NotFound if it's missing, PermissionDenied if it
+// isn't signed by a trusted key, DataLoss if its archive is unsafe to extract.
+func restoreErrCode(ctx context.Context, err error) codes.Code {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return codes.NotFound
+	case errors.Is(err, utils.ErrUntrustedCheckpoint):
+		return codes.PermissionDenied
+	case errors.Is(err, utils.ErrUnsafeArchive):
+		return codes.DataLoss
+	}
+	return checkpointErrCode(ctx)
+}
+
+func (s *service) Restore(ctx context.Context, args *task.RestoreArgs) (_ *task.RestoreResp, err error) {
+	start := time.Now()
+
+	// restores without a job are keyed on what they restore from
+	lockKey := "job " + args.JobID
+	if args.JobID == "" {
+		lockKey = "checkpoint " + args.CheckpointId + args.CheckpointPath
+	}
+	unlock, err := s.locks.tryLock(lockKey, "restore")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	defer s.states.notify()
+
 	ctx, restoreTracer := s.client.tracer.Start(ctx, "restore-ckpt")
 	restoreTracer.SetAttributes(attribute.String("jobID", args.JobID))
 	defer restoreTracer.End()
 	var resp task.RestoreResp
 
+	// err is named so whichever way the restore ends goes in the job's history
+	defer func() {
+		event := &task.JobEvent{
+			Type:           task.JobEvent_RESTORE,
+			PID:            resp.NewPID,
+			CheckpointPath: args.CheckpointPath,
+			CheckpointID:   args.CheckpointId,
+			Size:           checkpointSize(args.CheckpointPath),
+			DurationMS:     time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			event.Type, event.Error = task.JobEvent_RESTORE_FAILED, status.Convert(err).Message()
+		}
+		s.recordEvent(args.JobID, event)
+	}()
+
 	switch args.Type {
 
 	case task.RestoreArgs_LOCAL:
 		// get checkpointPath from db
-		// assume a suitable file has been passed to args
+		// assume a suitable file has been passed to args; it's verified as
+		// it's extracted
 		pid, err := s.client.Restore(ctx, args)
 		if err != nil {
-			staterr := status.Error(codes.Internal, fmt.Sprintf("failed to restore process: %v", err))
+			staterr := status.Error(restoreErrCode(ctx, err), fmt.Sprintf("failed to restore process: %v", err))
 			restoreTracer.RecordError(staterr)
 			return nil, staterr
 		}
@@ -225,27 +381,30 @@ func (s *service) Restore(ctx context.Context, args *task.RestoreArgs) (*task.Re
 			return nil, status.Error(codes.InvalidArgument, "checkpoint id cannot be empty")
 		}
 
-		cfg, err := utils.InitConfig()
+		store, err := s.store(s.client.config())
 		if err != nil {
-			err = status.Error(codes.Internal, err.Error())
-			return nil, err
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
 
-		store := utils.NewCedanaStore(cfg, s.client.tracer)
-
-		zipFile, err := store.GetCheckpoint(ctx, args.CheckpointId)
+		// the signed manifest comes along, and the archive is verified
+		// against it as it's extracted, as a local one is
+		zipFile, release, err := store.GetCheckpoint(ctx, args.CheckpointId)
 		if err != nil {
-			return nil, err
+			return nil, status.Error(restoreErrCode(ctx, err), err.Error())
 		}
+		defer release()
 
 		pid, err := s.client.Restore(ctx, &task.RestoreArgs{
 			Type:           task.RestoreArgs_REMOTE,
 			CheckpointId:   args.CheckpointId,
 			CheckpointPath: *zipFile,
+			JobID:          args.JobID,
+			UID:            args.UID,
+			GID:            args.GID,
 		})
 
 		if err != nil {
-			staterr := status.Error(codes.Internal, fmt.Sprintf("failed to restore process: %v", err))
+			staterr := status.Error(restoreErrCode(ctx, err), fmt.Sprintf("failed to restore process: %v", err))
 			restoreTracer.RecordError(staterr)
 			return nil, staterr
 		}
@@ -260,7 +419,13 @@ func (s *service) Restore(ctx context.Context, args *task.RestoreArgs) (*task.Re
 }
 
 func (s *service) ContainerDump(ctx context.Context, args *task.ContainerDumpArgs) (*task.ContainerDumpResp, error) {
-	err := s.client.ContainerDump(args.Ref, args.ContainerId)
+	unlock, err := s.locks.tryLock("container "+args.ContainerId, "containerd dump")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	err = s.client.ContainerDump(args.Ref, args.ContainerId)
 	if err != nil {
 		err = status.Error(codes.Internal, err.Error())
 		return nil, err
@@ -269,7 +434,16 @@ func (s *service) ContainerDump(ctx context.Context, args *task.ContainerDumpArg
 }
 
 func (s *service) ContainerRestore(ctx context.Context, args *task.ContainerRestoreArgs) (*task.ContainerRestoreResp, error) {
-	err := s.client.ContainerRestore(args.ImgPath, args.ContainerId)
+	unlock, err := s.locks.tryLock("container "+args.ContainerId, "containerd restore")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := s.client.refuseUnverifiable(s.client.config(), "containerd checkpoint "+args.ImgPath); err != nil {
+		return nil, status.Error(restoreErrCode(ctx, err), err.Error())
+	}
+	err = s.client.ContainerRestore(args.ImgPath, args.ContainerId)
 	if err != nil {
 		err = status.Error(codes.InvalidArgument, "arguments are invalid, container not found")
 		return nil, err
@@ -280,6 +454,14 @@ func (s *service) ContainerRestore(ctx context.Context, args *task.ContainerRest
 func (s *service) RuncDump(ctx context.Context, args *task.RuncDumpArgs) (*task.RuncDumpResp, error) {
 	var uploadID string
 	var checkpointId string
+	start := time.Now()
+	unlock, err := s.locks.tryLock("container "+args.ContainerId, "runc dump")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	defer s.states.notify()
+
 	//TODO BS: This will be done at controller level, just doing it here for now...
 	jobId := uuid.New().String()
 	pid, err := runc.GetPidByContainerId(args.ContainerId, args.Root)
@@ -292,6 +474,8 @@ func (s *service) RuncDump(ctx context.Context, args *task.RuncDumpArgs) (*task.
 
 	state.Flag = task.FlagEnum_JOB_RUNNING
 	state.PID = int32(pid)
+	state.ContainerId = args.ContainerId
+	state.ContainerRuntime = task.ProcessState_RUNC
 
 	err = s.client.db.CreateOrUpdateCedanaProcess(jobId, &state)
 	if err != nil {
@@ -299,22 +483,13 @@ func (s *service) RuncDump(ctx context.Context, args *task.RuncDumpArgs) (*task.
 		return nil, err
 	}
 
-	s.client.jobID = jobId
-
 	criuOpts := &container.CriuOpts{
 		ImagesDirectory: args.CriuOpts.ImagesDirectory,
 		WorkDirectory:   args.CriuOpts.WorkDirectory,
 		LeaveRunning:    true,
 		TcpEstablished:  args.CriuOpts.TcpEstablished,
 	}
-	cfg, err := utils.InitConfig()
-	if err != nil {
-		err = status.Error(codes.Internal, err.Error())
-		return nil, err
-	}
-	store := utils.NewCedanaStore(cfg, s.client.tracer)
-
-	err = s.client.RuncDump(ctx, args.Root, args.ContainerId, criuOpts)
+	err = s.client.RuncDump(ctx, jobId, args.Root, args.ContainerId, criuOpts)
 	if err != nil {
 		st := status.New(codes.Internal, "Runc dump failed")
 		st.WithDetails(&errdetails.ErrorInfo{
@@ -324,81 +499,51 @@ func (s *service) RuncDump(ctx context.Context, args *task.RuncDumpArgs) (*task.
 	}
 
 	if args.Type == task.RuncDumpArgs_REMOTE {
-		state, err := s.client.db.GetStateFromID(jobId)
-		if err != nil {
-			st := status.New(codes.Internal, err.Error())
-			return nil, st.Err()
-		}
-
-		if state == nil {
-			st := status.New(codes.NotFound, fmt.Sprintf("state not found for job %v", jobId))
-			st.WithDetails(&errdetails.ErrorInfo{
-				Reason: err.Error(),
-			})
-			return nil, st.Err()
-		}
-
-		checkpointPath := state.CheckpointPath
-
-		file, err := os.Open(checkpointPath)
-		if err != nil {
-			st := status.New(codes.NotFound, "checkpoint zip not found")
-			st.WithDetails(&errdetails.ErrorInfo{
-				Reason: err.Error(),
-			})
-			return nil, st.Err()
-		}
-		defer file.Close()
-
-		fileInfo, err := file.Stat()
+		store, err := s.store(s.client.config())
 		if err != nil {
-			st := status.New(codes.Internal, "checkpoint zip stat failed")
-			st.WithDetails(&errdetails.ErrorInfo{
-				Reason: err.Error(),
-			})
-			return nil, st.Err()
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
 
-		// Get the size
-		size := fileInfo.Size()
-
-		// zipFileSize += 4096
-		checkpointFullSize := int64(size)
-
-		multipartCheckpointResp, cid, err := store.CreateMultiPartUpload(ctx, checkpointFullSize)
+		state, err := s.client.db.GetStateFromID(jobId)
 		if err != nil {
-			st := status.New(codes.Internal, fmt.Sprintf("CreateMultiPartUpload failed with error: %s", err.Error()))
+			st := status.New(codes.Internal, err.Error())
 			return nil, st.Err()
 		}
 
-		checkpointId = cid
-
-		err = store.StartMultiPartUpload(ctx, cid, multipartCheckpointResp, checkpointPath)
+		meta, err := store.PushCheckpoint(ctx, jobId, state.CheckpointPath)
 		if err != nil {
-			st := status.New(codes.Internal, fmt.Sprintf("StartMultiPartUpload failed with error: %s", err.Error()))
+			st := status.New(codes.Internal, fmt.Sprintf("pushing checkpoint failed with error: %s", err.Error()))
 			return nil, st.Err()
 		}
 
-		err = store.CompleteMultiPartUpload(ctx, *multipartCheckpointResp, cid)
-		if err != nil {
-			st := status.New(codes.Internal, fmt.Sprintf("CompleteMultiPartUpload failed with error: %s", err.Error()))
-			return nil, st.Err()
-		}
+		checkpointId = meta.ID
 
-		remoteState := &task.RemoteState{CheckpointID: cid, UploadID: multipartCheckpointResp.UploadID, Timestamp: time.Now().Unix()}
+		remoteState := &task.RemoteState{CheckpointID: meta.ID, UploadID: meta.UploadID, Timestamp: time.Now().Unix()}
 
 		state.RemoteState = append(state.RemoteState, remoteState)
 
-		uploadID = multipartCheckpointResp.UploadID
+		uploadID = meta.UploadID
 
 		s.client.db.UpdateProcessStateWithID(jobId, state)
 
 	}
 
+	event := &task.JobEvent{Type: task.JobEvent_CHECKPOINT, PID: int32(pid), CheckpointID: checkpointId, DurationMS: time.Since(start).Milliseconds()}
+	if state, err := s.client.db.GetStateFromID(jobId); err == nil {
+		event.CheckpointPath, event.Size = state.CheckpointPath, checkpointSize(state.CheckpointPath)
+	}
+	s.recordEvent(jobId, event)
+
 	return &task.RuncDumpResp{Message: fmt.Sprintf("Dumped process %s to %s, multipart checkpoint id: %s", jobId, args.CriuOpts.ImagesDirectory, uploadID), CheckpointId: checkpointId}, nil
 }
 
 func (s *service) RuncRestore(ctx context.Context, args *task.RuncRestoreArgs) (*task.RuncRestoreResp, error) {
+	unlock, err := s.locks.tryLock("container "+args.ContainerId, "runc restore")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	cfg := s.client.config()
 
 	opts := &container.RuncOpts{
 		Root:          args.Opts.Root,
@@ -409,6 +554,9 @@ func (s *service) RuncRestore(ctx context.Context, args *task.RuncRestoreArgs) (
 	}
 	switch args.Type {
 	case task.RuncRestoreArgs_LOCAL:
+		if err := s.client.refuseUnverifiable(cfg, "runc image dir "+args.ImagePath); err != nil {
+			return nil, status.Error(restoreErrCode(ctx, err), err.Error())
+		}
 		err := s.client.RuncRestore(ctx, args.ImagePath, args.ContainerId, args.IsK3S, []string{}, opts)
 		if err != nil {
 			err = status.Error(codes.InvalidArgument, "invalid argument")
@@ -420,17 +568,24 @@ func (s *service) RuncRestore(ctx context.Context, args *task.RuncRestoreArgs) (
 			return nil, status.Error(codes.InvalidArgument, "checkpoint id cannot be empty")
 		}
 
-		cfg, err := utils.InitConfig()
+		store, err := s.store(cfg)
 		if err != nil {
-			err = status.Error(codes.Internal, err.Error())
-			return nil, err
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
 
-		store := utils.NewCedanaStore(cfg, s.client.tracer)
-
-		zipFile, err := store.GetCheckpoint(ctx, args.CheckpointId)
+		zipFile, release, err := store.GetCheckpoint(ctx, args.CheckpointId)
 		if err != nil {
-			return nil, err
+			return nil, status.Error(restoreErrCode(ctx, err), err.Error())
+		}
+		defer release()
+		trusted, err := utils.NewTrustedKeys(cfg.Signing)
+		if err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if trusted != nil {
+			if _, err := utils.VerifyArchive(ctx, *zipFile, trusted); err != nil {
+				return nil, status.Error(restoreErrCode(ctx, err), err.Error())
+			}
 		}
 
 		err = s.client.RuncRestore(ctx, *zipFile, args.ContainerId, args.IsK3S, []string{}, opts)
@@ -498,83 +653,19 @@ func (s *service) GetPausePid(ctx context.Context, args *task.PausePidArgs) (*ta
 	return resp, nil
 }
 
-func (s *service) publishStateContinous(rate int) {
-	// get PID from id
-	pid, err := s.client.db.GetPID(s.client.jobID)
-	if err != nil {
-		s.logger.Warn().Msgf("could not get pid: %v", err)
-	}
-	s.logger.Info().Msgf("pid: %d", pid)
-	ticker := time.NewTicker(time.Duration(rate) * time.Second)
-	for range ticker.C {
-		if pid != 0 {
-			args := &task.ProcessState{}
-
-			if err := s.ClientStateStream.Send(args); err != nil {
-				log.Printf("Error sending LogStreamingArgs to client: %v", err)
-				return
-			}
-		}
-	}
-}
-
-// This is for the orchestrator
+// LogStreaming follows the output tasks started without a LogOutputFile write
+// to, from where it ends now. It's kept for existing clients; StreamJobLogs
+// serves any job's logs.
 func (s *service) LogStreaming(stream task.TaskService_LogStreamingServer) error {
-	limiter := rate.NewLimiter(rate.Every(10*time.Second), 5)
-	buf := make([]byte, 4096)
-
-	for {
-		select {
-		case <-stream.Context().Done():
-			return nil // Client disconnected
-		default:
-			n, err := s.r.Read(buf)
-			if err != nil {
-				break
-			}
-			if limiter.Allow() {
-				// TODO BS Needs implementation
-				response := &task.LogStreamingArgs{
-					Timestamp: time.Now().Local().Format(time.RFC3339),
-					Source:    "Not implemented",
-					Level:     "INFO",
-					Msg:       string(buf[:n]),
-				}
-				if err := stream.Send(response); err != nil {
-					return err
-				}
-			}
-		}
+	tailer := &logTailer{
+		source: "stdout",
+		segments: func() ([]*task.LogSegment, error) {
+			return []*task.LogSegment{{Stdout: defaultLogPath}}, nil
+		},
+		send: stream.Send,
 	}
-}
-
-// This is for the orchestrator
-func (s *service) ClientStateStreaming(stream task.TaskService_ClientStateStreamingServer) error {
-	// Store the client's stream when it connects.
-	s.ClientStateStream = stream
-
-	go s.publishStateContinous(30)
-
-	for {
-		// Here we can do something with LogStreamingResp
-		_, err := stream.Recv()
-		if err == io.EOF {
-			s.logger.Debug().Msgf("Client has closed connection")
-			break
-		}
-		if err != nil {
-			s.logger.Debug().Msgf("Unable to read from client, %v", err)
-			return err
-		}
-
-		if s.ClientStateStream != nil {
-
-			args := &task.ProcessState{}
-			if err := s.ClientStateStream.Send(args); err != nil {
-				s.logger.Debug().Msgf("Issue sending process state")
-				break
-			}
-		}
+	if err := tailer.run(stream.Context(), fileSize(defaultLogPath), true); err != nil {
+		return status.Error(codes.Internal, err.Error())
 	}
 	return nil
 }
@@ -591,7 +682,7 @@ func (s *service) runTask(ctx context.Context, task string, args *task.StartTask
 
 	var gpuCmd *exec.Cmd
 	var err error
-	if os.Getenv("CEDANA_GPU_ENABLED") == "true" {
+	if s.client.config().GPU.Enabled {
 		_, gpuStartSpan := s.client.tracer.Start(ctx, "start-gpu-controller")
 		gpuCmd, err = StartGPUController(args.UID, args.GID, s.logger)
 		if err != nil {
@@ -636,6 +727,17 @@ func (s *service) runTask(ctx context.Context, task string, args *task.StartTask
 	cmd.Stdout = outputFile
 	cmd.Stderr = io.MultiWriter(outputFile, &stderrbuf)
 
+	var errorFile *os.File
+	if args.SplitStderr {
+		// kept apart so StreamJobLogs can serve either stream on its own
+		errorFile, err = os.OpenFile(stderrLogPath(args.LogOutputFile), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o777)
+		if err != nil {
+			outputFile.Close()
+			return 0, err
+		}
+		cmd.Stderr = io.MultiWriter(errorFile, &stderrbuf)
+	}
+
 	if gpuCmd != nil {
 		gpuCmd.Stderr = io.Writer(&gpuerrbuf)
 	}
@@ -643,11 +745,21 @@ func (s *service) runTask(ctx context.Context, task string, args *task.StartTask
 	cmd.Env = args.Env
 	err = cmd.Start()
 	if err != nil {
+		outputFile.Close()
+		if errorFile != nil {
+			errorFile.Close()
+		}
 		return 0, err
 	}
 
 	go func() {
 		err := cmd.Wait()
+		// stderr is copied through a pipe until the task exits
+		outputFile.Close()
+		if errorFile != nil {
+			errorFile.Close()
+		}
+		s.states.notify()
 		if gpuCmd != nil {
 			err = gpuCmd.Process.Kill()
 			if err != nil {
@@ -722,8 +834,15 @@ func (s *service) StartTask(ctx context.Context, args *task.StartTaskArgs) (*tas
 	var state task.ProcessState
 	var taskToRun string
 
+	unlock, err := s.locks.tryLock("job "+args.Id, "start")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	defer s.states.notify()
+
 	if args.Task == "" {
-		taskToRun = s.client.config.Client.Task
+		taskToRun = s.client.config().Client.Task
 	} else {
 		taskToRun = args.Task
 	}
@@ -735,6 +854,19 @@ func (s *service) StartTask(ctx context.Context, args *task.StartTaskArgs) (*tas
 
 		state.Flag = task.FlagEnum_JOB_RUNNING
 		state.PID = pid
+
+		segment := &task.LogSegment{
+			Stdout:    args.LogOutputFile,
+			PID:       pid,
+			StartedAt: time.Now().Unix(),
+		}
+		if args.SplitStderr {
+			segment.Stderr = stderrLogPath(args.LogOutputFile)
+		}
+		if err := s.client.db.AppendLogSegment(args.Id, segment); err != nil {
+			s.client.logger.Warn().Err(err).Msgf("could not record log files for job %s", args.Id)
+		}
+		s.recordEvent(args.Id, &task.JobEvent{Type: task.JobEvent_START, Time: segment.StartedAt, PID: pid, Tags: args.Tags})
 	} else {
 		// TODO BS: this should be at market level
 		s.client.logger.Info().Msgf("failed to run task with error: %v, attempt %d", err, 1)
@@ -762,6 +894,7 @@ func (s *service) StartTask(ctx context.Context, args *task.StartTaskArgs) (*tas
 type Server struct {
 	grpcServer *grpc.Server
 	Lis        net.Listener
+	service    *service
 }
 
 func (s *Server) New() (*grpc.Server, error) {
@@ -780,6 +913,18 @@ func (s *Server) New() (*grpc.Server, error) {
 	}
 
 	task.RegisterTaskServiceServer(grpcServer, service)
+	s.service = service
+
+	if err := utils.SetLogLevel(client.config().Client.LogLevel); err != nil {
+		return nil, err
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go service.reloadOnSignal(context.Background(), hup)
+
+	if cfg := client.config().Preemption; cfg.MetadataURL != "" || cfg.NoticeFile != "" {
+		go newPreemptionWatcher(service, cfg).run(context.Background())
+	}
 
 	reflection.Register(grpcServer)
 
@@ -832,7 +977,7 @@ func StartGRPCServer() (*grpc.Server, error) {
 		<-startCh // Wait for the server to start
 		// Here join netns
 		//TODO find pause bundle path
-		if os.Getenv("IS_K8S") == "1" {
+		if srv.service.client.config().Kubernetes.Enabled {
 			_, bundle, err := runc.GetContainerIdByName(cedanaContainerName, k8sDefaultRuncRoot)
 			if err != nil {
 				fmt.Println(err.Error())
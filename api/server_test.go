@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJobLocks(t *testing.T) {
+	var locks jobLocks
+
+	unlock, err := locks.tryLock("job a", "dump")
+	if err != nil {
+		t.Fatalf("expected lock on idle job, got %v", err)
+	}
+
+	_, err = locks.tryLock("job a", "restore")
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted for busy job, got %v", err)
+	}
+
+	unlockB, err := locks.tryLock("job b", "dump")
+	if err != nil {
+		t.Fatalf("other jobs should not be blocked, got %v", err)
+	}
+	unlockB()
+
+	unlock()
+	unlock, err = locks.tryLock("job a", "restore")
+	if err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+	unlock()
+}
+
+func TestDump_LocksPID(t *testing.T) {
+	c := &Client{db: NewDB(), tracer: trace.NewNoopTracerProvider().Tracer("test")}
+	c.cfg.Store(&utils.Config{})
+	logger := utils.GetLogger()
+	svc := &service{client: c, logger: &logger}
+
+	jobID := fmt.Sprintf("lock-pid-test-%d", os.Getpid())
+	pid := int32(1 << 22)
+	if err := c.db.CreateOrUpdateCedanaProcess(jobID, &task.ProcessState{PID: pid}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a dump of the process by its PID is under way
+	unlock, err := svc.lockDump("", pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Dump(context.Background(), &task.DumpArgs{JobID: jobID, Type: task.DumpArgs_LOCAL}); status.Code(err) != codes.Aborted {
+		t.Errorf("expected a dump of the same process by job to be refused, got %v", err)
+	}
+	if _, err := svc.Dump(context.Background(), &task.DumpArgs{PID: pid, Type: task.DumpArgs_LOCAL}); status.Code(err) != codes.Aborted {
+		t.Errorf("expected a second dump by PID to be refused, got %v", err)
+	}
+	unlock()
+
+	// and one by job keeps the job from being restored meanwhile
+	if unlock, err = svc.lockDump(jobID, pid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Dump(context.Background(), &task.DumpArgs{PID: pid, Type: task.DumpArgs_LOCAL}); status.Code(err) != codes.Aborted {
+		t.Errorf("expected a dump of the same process by PID to be refused, got %v", err)
+	}
+	if _, err := svc.locks.tryLock("job "+jobID, "restore"); status.Code(err) != codes.Aborted {
+		t.Errorf("expected the job to be held, got %v", err)
+	}
+	unlock()
+	if _, err := svc.lockDump(jobID, pid); err != nil {
+		t.Errorf("expected both locks to be released, got %v", err)
+	}
+}
+
+func TestRestore_RefusesUnverifiable(t *testing.T) {
+	ctx := context.Background()
+	pub, _, _ := ed25519.GenerateKey(nil)
+	c := &Client{}
+	c.cfg.Store(&utils.Config{Signing: utils.Signing{
+		KeyFile:     filepath.Join(t.TempDir(), "node_key"),
+		TrustedKeys: []string{utils.PublicKeyString(pub)},
+	}})
+	svc := &service{client: c}
+
+	_, err := svc.ContainerRestore(ctx, &task.ContainerRestoreArgs{ImgPath: "checkpoint:latest", ContainerId: "ctr"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a containerd restore to be refused, got %v", err)
+	}
+	_, err = svc.RuncRestore(ctx, &task.RuncRestoreArgs{ImagePath: t.TempDir(), ContainerId: "ctr", Opts: &task.RuncOpts{}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a runc restore from an image dir to be refused, got %v", err)
+	}
+}
+
+func TestDump_RetryPush(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	c := &Client{db: NewDB(), tracer: trace.NewNoopTracerProvider().Tracer("test")}
+	c.cfg.Store(&utils.Config{
+		Store: utils.StoreConfig{Backend: "local", LocalDir: filepath.Join(dir, "store"), CacheDir: filepath.Join(dir, "cache")},
+	})
+	logger := utils.GetLogger()
+	svc := &service{client: c, logger: &logger}
+
+	jobID := fmt.Sprintf("retry-push-test-%d", os.Getpid())
+	archive := filepath.Join(dir, "checkpoint.tar")
+	if err := os.WriteFile(archive, []byte("a checkpoint"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.db.CreateOrUpdateCedanaProcess(jobID, &task.ProcessState{PID: 1 << 22, CheckpointPath: archive}); err != nil {
+		t.Fatal(err)
+	}
+	args := &task.DumpArgs{JobID: jobID, Type: task.DumpArgs_REMOTE, RetryPush: true}
+
+	// a push cut short leaves the archive to push again
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := svc.Dump(cancelled, args); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected the push to be cancelled, got %v", err)
+	}
+	state, err := c.db.GetStateFromID(jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.CheckpointState != task.CheckpointState_CHECKPOINT_FAILED || state.CheckpointPath != archive {
+		t.Fatalf("expected a failed checkpoint still pointing at %s, got %v", archive, state)
+	}
+
+	resp, err := svc.Dump(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.CheckpointID == "" || resp.PID != 1<<22 {
+		t.Errorf("expected the archive to be pushed for the job's PID, got %v", resp)
+	}
+	if state, err = c.db.GetStateFromID(jobID); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(state.RemoteState); n != 1 || state.RemoteState[0].CheckpointID != resp.CheckpointID {
+		t.Errorf("expected the pushed checkpoint to be recorded, got %v", state.RemoteState)
+	}
+
+	if _, err := svc.Dump(ctx, &task.DumpArgs{JobID: jobID, Type: task.DumpArgs_LOCAL, RetryPush: true}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected a LOCAL retry to be refused, got %v", err)
+	}
+	os.Remove(archive)
+	if _, err := svc.Dump(ctx, args); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected a retry without the archive to be refused, got %v", err)
+	}
+}
+
+// Hammers the service from many goroutines at once. Run with -race; C/R itself
+// isn't expected to succeed here, only to fail cleanly (busy or internal) without
+// requests trampling each other.
+func TestService_ConcurrentRequests(t *testing.T) {
+	client, err := setup(t)
+	if err != nil {
+		t.Fatal("error setting up grpc client")
+	}
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+
+	const jobs = 4
+	const callsPerJob = 8
+
+	var pids []int32
+	t.Cleanup(func() {
+		for _, pid := range pids {
+			syscall.Kill(int(pid), syscall.SIGKILL)
+		}
+	})
+
+	ids := make([]string, jobs)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("concurrency-test-%d-%d", os.Getpid(), i)
+		resp, err := client.StartTask(ctx, &task.StartTaskArgs{
+			Task:          "sleep 60",
+			Id:            ids[i],
+			LogOutputFile: filepath.Join(tmp, ids[i]+".log"),
+			UID:           uint32(os.Getuid()),
+			GID:           uint32(os.Getgid()),
+		})
+		if err != nil {
+			t.Fatalf("failed to start task: %v", err)
+		}
+		pids = append(pids, resp.PID)
+	}
+
+	// a checkpoint that fails to restore, to exercise per-restore workspaces
+	badCheckpoint := filepath.Join(tmp, "bad.tar")
+	if err := os.WriteFile(badCheckpoint, []byte("not a tarball"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, jobs*callsPerJob*2)
+
+	for _, id := range ids {
+		for i := 0; i < callsPerJob; i++ {
+			wg.Add(2)
+			go func(id string) {
+				defer wg.Done()
+				_, err := client.Dump(ctx, &task.DumpArgs{JobID: id, Dir: filepath.Join(tmp, "dumps"), Type: task.DumpArgs_LOCAL})
+				errs <- err
+			}(id)
+			go func(id string) {
+				defer wg.Done()
+				_, err := client.Restore(ctx, &task.RestoreArgs{JobID: id, CheckpointPath: badCheckpoint, Type: task.RestoreArgs_LOCAL})
+				errs <- err
+			}(id)
+		}
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err == nil {
+			continue
+		}
+		switch status.Code(err) {
+		case codes.Aborted, codes.Internal:
+		default:
+			t.Errorf("unexpected error under concurrency: %v", err)
+		}
+	}
+}
+
+func TestMarkCheckpointFailed_RecordsReason(t *testing.T) {
+	c := &Client{db: NewDB()}
+	logger := utils.GetLogger()
+	svc := &service{client: c, logger: &logger}
+
+	jobID := fmt.Sprintf("failed-reason-test-%d", os.Getpid())
+	reason := &task.CheckpointReason{Reason: task.CheckpointReason_INSTANCE_TERMINATION}
+	svc.markCheckpointFailed(context.Background(), &task.DumpArgs{JobID: jobID, Reason: reason}, 1<<22, fmt.Errorf("criu said no"))
+
+	events, err := c.db.GetJobEvents(jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(events); n == 0 || events[n-1].Type != task.JobEvent_CHECKPOINT_FAILED || events[n-1].Reason.GetReason() != reason.Reason {
+		t.Errorf("expected a failed checkpoint recorded with its reason, got %v", events)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// ServiceClient wraps the task service. Every call takes a ctx so callers (e.g. the CLI on Ctrl-C) can cancel an
+// in-flight operation; cancellation propagates to the daemon through gRPC.
 type ServiceClient struct {
 	taskService task.TaskServiceClient
 	taskConn    *grpc.ClientConn
@@ -33,8 +35,8 @@ func NewClient(addr string) (*ServiceClient, error) {
 	return client, nil
 }
 
-func (c *ServiceClient) GetRuncIdByName(args *task.CtrByNameArgs) (*task.CtrByNameResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+func (c *ServiceClient) GetRuncIdByName(ctx context.Context, args *task.CtrByNameArgs) (*task.CtrByNameResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.GetRuncContainerByName(ctx, args)
 	if err != nil {
@@ -43,9 +45,9 @@ func (c *ServiceClient) GetRuncIdByName(args *task.CtrByNameArgs) (*task.CtrByNa
 	return resp, nil
 }
 
-func (c *ServiceClient) CheckpointTask(args *task.DumpArgs) (*task.DumpResp, error) {
+func (c *ServiceClient) CheckpointTask(ctx context.Context, args *task.DumpArgs) (*task.DumpResp, error) {
 	// TODO NR - timeouts here need to be fixed
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.Dump(ctx, args)
 	if err != nil {
@@ -54,8 +56,8 @@ func (c *ServiceClient) CheckpointTask(args *task.DumpArgs) (*task.DumpResp, err
 	return resp, nil
 }
 
-func (c *ServiceClient) RestoreTask(args *task.RestoreArgs) (*task.RestoreResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+func (c *ServiceClient) RestoreTask(ctx context.Context, args *task.RestoreArgs) (*task.RestoreResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.Restore(ctx, args)
 	if err != nil {
@@ -64,8 +66,8 @@ func (c *ServiceClient) RestoreTask(args *task.RestoreArgs) (*task.RestoreResp,
 	return resp, nil
 }
 
-func (c *ServiceClient) CheckpointContainer(args *task.ContainerDumpArgs) (*task.ContainerDumpResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (c *ServiceClient) CheckpointContainer(ctx context.Context, args *task.ContainerDumpArgs) (*task.ContainerDumpResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.ContainerDump(ctx, args)
 	if err != nil {
@@ -74,8 +76,8 @@ func (c *ServiceClient) CheckpointContainer(args *task.ContainerDumpArgs) (*task
 	return resp, nil
 }
 
-func (c *ServiceClient) RestoreContainer(args *task.ContainerRestoreArgs) (*task.ContainerRestoreResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (c *ServiceClient) RestoreContainer(ctx context.Context, args *task.ContainerRestoreArgs) (*task.ContainerRestoreResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.ContainerRestore(ctx, args)
 	if err != nil {
@@ -84,8 +86,8 @@ func (c *ServiceClient) RestoreContainer(args *task.ContainerRestoreArgs) (*task
 	return resp, nil
 }
 
-func (c *ServiceClient) CheckpointRunc(args *task.RuncDumpArgs) (*task.RuncDumpResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (c *ServiceClient) CheckpointRunc(ctx context.Context, args *task.RuncDumpArgs) (*task.RuncDumpResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.RuncDump(ctx, args)
 	if err != nil {
@@ -94,8 +96,8 @@ func (c *ServiceClient) CheckpointRunc(args *task.RuncDumpArgs) (*task.RuncDumpR
 	return resp, nil
 }
 
-func (c *ServiceClient) RuncRestore(args *task.RuncRestoreArgs) (*task.RuncRestoreResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (c *ServiceClient) RuncRestore(ctx context.Context, args *task.RuncRestoreArgs) (*task.RuncRestoreResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.RuncRestore(ctx, args)
 	if err != nil {
@@ -104,8 +106,8 @@ func (c *ServiceClient) RuncRestore(args *task.RuncRestoreArgs) (*task.RuncResto
 	return resp, nil
 }
 
-func (c *ServiceClient) StartTask(args *task.StartTaskArgs) (*task.StartTaskResp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (c *ServiceClient) StartTask(ctx context.Context, args *task.StartTaskArgs) (*task.StartTaskResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	resp, err := c.taskService.StartTask(ctx, args)
 	if err != nil {
@@ -114,6 +116,42 @@ func (c *ServiceClient) StartTask(args *task.StartTaskArgs) (*task.StartTaskResp
 	return resp, nil
 }
 
+// StreamJobLogs has no timeout of its own: in follow mode it runs until ctx is
+// cancelled.
+func (c *ServiceClient) StreamJobLogs(ctx context.Context, args *task.StreamJobLogsArgs) (task.TaskService_StreamJobLogsClient, error) {
+	return c.taskService.StreamJobLogs(ctx, args)
+}
+
+func (c *ServiceClient) ReloadConfig(ctx context.Context, args *task.ReloadConfigArgs) (*task.ReloadConfigResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+	resp, err := c.taskService.ReloadConfig(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ServiceClient) ListJobs(ctx context.Context, args *task.ListJobsArgs) (*task.ListJobsResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+	resp, err := c.taskService.ListJobs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ServiceClient) GetJob(ctx context.Context, args *task.GetJobArgs) (*task.GetJobResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+	resp, err := c.taskService.GetJob(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (c *ServiceClient) Close() {
 	c.taskConn.Close()
 }
@@ -29,13 +29,20 @@ type TaskServiceClient interface {
 	RuncDump(ctx context.Context, in *RuncDumpArgs, opts ...grpc.CallOption) (*RuncDumpResp, error)
 	RuncRestore(ctx context.Context, in *RuncRestoreArgs, opts ...grpc.CallOption) (*RuncRestoreResp, error)
 	StartTask(ctx context.Context, in *StartTaskArgs, opts ...grpc.CallOption) (*StartTaskResp, error)
+	// Deprecated: Do not use.
+	// Deprecated: follows the output of tasks started without a
+	// LogOutputFile only. Use StreamJobLogs, which tails any job by its ID.
 	LogStreaming(ctx context.Context, opts ...grpc.CallOption) (TaskService_LogStreamingClient, error)
+	StreamJobLogs(ctx context.Context, in *StreamJobLogsArgs, opts ...grpc.CallOption) (TaskService_StreamJobLogsClient, error)
 	ClientStateStreaming(ctx context.Context, opts ...grpc.CallOption) (TaskService_ClientStateStreamingClient, error)
 	MetaStateStreaming(ctx context.Context, opts ...grpc.CallOption) (TaskService_MetaStateStreamingClient, error)
 	ListRuncContainers(ctx context.Context, in *RuncRoot, opts ...grpc.CallOption) (*RuncList, error)
 	GetRuncContainerByName(ctx context.Context, in *CtrByNameArgs, opts ...grpc.CallOption) (*CtrByNameResp, error)
 	GetPausePid(ctx context.Context, in *PausePidArgs, opts ...grpc.CallOption) (*PausePidResp, error)
 	ListContainers(ctx context.Context, in *ListArgs, opts ...grpc.CallOption) (*ListResp, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigArgs, opts ...grpc.CallOption) (*ReloadConfigResp, error)
+	ListJobs(ctx context.Context, in *ListJobsArgs, opts ...grpc.CallOption) (*ListJobsResp, error)
+	GetJob(ctx context.Context, in *GetJobArgs, opts ...grpc.CallOption) (*GetJobResp, error)
 }
 
 type taskServiceClient struct {
@@ -109,6 +116,7 @@ func (c *taskServiceClient) StartTask(ctx context.Context, in *StartTaskArgs, op
 	return out, nil
 }
 
+// Deprecated: Do not use.
 func (c *taskServiceClient) LogStreaming(ctx context.Context, opts ...grpc.CallOption) (TaskService_LogStreamingClient, error) {
 	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[0], "/cedana.services.task.TaskService/LogStreaming", opts...)
 	if err != nil {
@@ -140,8 +148,40 @@ func (x *taskServiceLogStreamingClient) Recv() (*LogStreamingArgs, error) {
 	return m, nil
 }
 
+func (c *taskServiceClient) StreamJobLogs(ctx context.Context, in *StreamJobLogsArgs, opts ...grpc.CallOption) (TaskService_StreamJobLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[1], "/cedana.services.task.TaskService/StreamJobLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskServiceStreamJobLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskService_StreamJobLogsClient interface {
+	Recv() (*LogStreamingArgs, error)
+	grpc.ClientStream
+}
+
+type taskServiceStreamJobLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskServiceStreamJobLogsClient) Recv() (*LogStreamingArgs, error) {
+	m := new(LogStreamingArgs)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *taskServiceClient) ClientStateStreaming(ctx context.Context, opts ...grpc.CallOption) (TaskService_ClientStateStreamingClient, error) {
-	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[1], "/cedana.services.task.TaskService/ClientStateStreaming", opts...)
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[2], "/cedana.services.task.TaskService/ClientStateStreaming", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +212,7 @@ func (x *taskServiceClientStateStreamingClient) Recv() (*ProcessState, error) {
 }
 
 func (c *taskServiceClient) MetaStateStreaming(ctx context.Context, opts ...grpc.CallOption) (TaskService_MetaStateStreamingClient, error) {
-	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[2], "/cedana.services.task.TaskService/MetaStateStreaming", opts...)
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[3], "/cedana.services.task.TaskService/MetaStateStreaming", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -238,6 +278,33 @@ func (c *taskServiceClient) ListContainers(ctx context.Context, in *ListArgs, op
 	return out, nil
 }
 
+func (c *taskServiceClient) ReloadConfig(ctx context.Context, in *ReloadConfigArgs, opts ...grpc.CallOption) (*ReloadConfigResp, error) {
+	out := new(ReloadConfigResp)
+	err := c.cc.Invoke(ctx, "/cedana.services.task.TaskService/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListJobs(ctx context.Context, in *ListJobsArgs, opts ...grpc.CallOption) (*ListJobsResp, error) {
+	out := new(ListJobsResp)
+	err := c.cc.Invoke(ctx, "/cedana.services.task.TaskService/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetJob(ctx context.Context, in *GetJobArgs, opts ...grpc.CallOption) (*GetJobResp, error) {
+	out := new(GetJobResp)
+	err := c.cc.Invoke(ctx, "/cedana.services.task.TaskService/GetJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskServiceServer is the server API for TaskService service.
 // All implementations must embed UnimplementedTaskServiceServer
 // for forward compatibility
@@ -249,13 +316,20 @@ type TaskServiceServer interface {
 	RuncDump(context.Context, *RuncDumpArgs) (*RuncDumpResp, error)
 	RuncRestore(context.Context, *RuncRestoreArgs) (*RuncRestoreResp, error)
 	StartTask(context.Context, *StartTaskArgs) (*StartTaskResp, error)
+	// Deprecated: Do not use.
+	// Deprecated: follows the output of tasks started without a
+	// LogOutputFile only. Use StreamJobLogs, which tails any job by its ID.
 	LogStreaming(TaskService_LogStreamingServer) error
+	StreamJobLogs(*StreamJobLogsArgs, TaskService_StreamJobLogsServer) error
 	ClientStateStreaming(TaskService_ClientStateStreamingServer) error
 	MetaStateStreaming(TaskService_MetaStateStreamingServer) error
 	ListRuncContainers(context.Context, *RuncRoot) (*RuncList, error)
 	GetRuncContainerByName(context.Context, *CtrByNameArgs) (*CtrByNameResp, error)
 	GetPausePid(context.Context, *PausePidArgs) (*PausePidResp, error)
 	ListContainers(context.Context, *ListArgs) (*ListResp, error)
+	ReloadConfig(context.Context, *ReloadConfigArgs) (*ReloadConfigResp, error)
+	ListJobs(context.Context, *ListJobsArgs) (*ListJobsResp, error)
+	GetJob(context.Context, *GetJobArgs) (*GetJobResp, error)
 	mustEmbedUnimplementedTaskServiceServer()
 }
 
@@ -287,6 +361,9 @@ func (UnimplementedTaskServiceServer) StartTask(context.Context, *StartTaskArgs)
 func (UnimplementedTaskServiceServer) LogStreaming(TaskService_LogStreamingServer) error {
 	return status.Errorf(codes.Unimplemented, "method LogStreaming not implemented")
 }
+func (UnimplementedTaskServiceServer) StreamJobLogs(*StreamJobLogsArgs, TaskService_StreamJobLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamJobLogs not implemented")
+}
 func (UnimplementedTaskServiceServer) ClientStateStreaming(TaskService_ClientStateStreamingServer) error {
 	return status.Errorf(codes.Unimplemented, "method ClientStateStreaming not implemented")
 }
@@ -305,6 +382,15 @@ func (UnimplementedTaskServiceServer) GetPausePid(context.Context, *PausePidArgs
 func (UnimplementedTaskServiceServer) ListContainers(context.Context, *ListArgs) (*ListResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListContainers not implemented")
 }
+func (UnimplementedTaskServiceServer) ReloadConfig(context.Context, *ReloadConfigArgs) (*ReloadConfigResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedTaskServiceServer) ListJobs(context.Context, *ListJobsArgs) (*ListJobsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedTaskServiceServer) GetJob(context.Context, *GetJobArgs) (*GetJobResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
 func (UnimplementedTaskServiceServer) mustEmbedUnimplementedTaskServiceServer() {}
 
 // UnsafeTaskServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -470,6 +556,27 @@ func (x *taskServiceLogStreamingServer) Recv() (*LogStreamingResp, error) {
 	return m, nil
 }
 
+func _TaskService_StreamJobLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamJobLogsArgs)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskServiceServer).StreamJobLogs(m, &taskServiceStreamJobLogsServer{stream})
+}
+
+type TaskService_StreamJobLogsServer interface {
+	Send(*LogStreamingArgs) error
+	grpc.ServerStream
+}
+
+type taskServiceStreamJobLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskServiceStreamJobLogsServer) Send(m *LogStreamingArgs) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _TaskService_ClientStateStreaming_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(TaskServiceServer).ClientStateStreaming(&taskServiceClientStateStreamingServer{stream})
 }
@@ -594,6 +701,60 @@ func _TaskService_ListContainers_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaskService_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cedana.services.task.TaskService/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ReloadConfig(ctx, req.(*ReloadConfigArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cedana.services.task.TaskService/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ListJobs(ctx, req.(*ListJobsArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cedana.services.task.TaskService/GetJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetJob(ctx, req.(*GetJobArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -645,6 +806,18 @@ var TaskService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListContainers",
 			Handler:    _TaskService_ListContainers_Handler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _TaskService_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _TaskService_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _TaskService_GetJob_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -653,6 +826,11 @@ var TaskService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "StreamJobLogs",
+			Handler:       _TaskService_StreamJobLogs_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ClientStateStreaming",
 			Handler:       _TaskService_ClientStateStreaming_Handler,
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+)
+
+const (
+	defaultStateStreamInterval = 30 * time.Second
+	// how often job states are sampled for changes between full snapshots
+	stateChangePollInterval = 5 * time.Second
+	stateSubscriberBuffer   = 64
+)
+
+// stateBroadcaster fans job states out to every ClientStateStreaming
+// subscriber. It samples jobs while anyone is subscribed, publishing a full
+// snapshot every interval and, in between, only the jobs whose state changed.
+// The zero value is ready to use.
+type stateBroadcaster struct {
+	mu      sync.Mutex
+	subs    map[chan *task.ProcessState]struct{}
+	changed chan struct{}
+	running bool
+
+	// job ID -> fingerprint of the last state sent to subscribers
+	last map[string]string
+}
+
+// subscribe registers a new subscriber, starting the sampler if it's the first.
+// It returns the state of every job for the subscriber to start from; updates
+// then only carry what changed since, and full snapshots. interval gives the
+// time between full snapshots; it's asked again on every sample, so a config
+// reload takes effect without resubscribing. The returned func must be called
+// to unsubscribe.
+func (b *stateBroadcaster) subscribe(interval func() time.Duration, snapshot func() ([]*task.ProcessState, error)) ([]*task.ProcessState, <-chan *task.ProcessState, func(), error) {
+	initial, err := snapshot()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[chan *task.ProcessState]struct{})
+		b.changed = make(chan struct{}, 1)
+	}
+
+	ch := make(chan *task.ProcessState, stateSubscriberBuffer)
+	b.subs[ch] = struct{}{}
+
+	if !b.running {
+		b.running = true
+		b.last = make(map[string]string)
+		go b.run(interval, snapshot)
+	}
+	// the subscriber has these already, so they aren't news on the next sample
+	for _, state := range initial {
+		b.last[state.JobID] = stateFingerprint(state)
+	}
+
+	return initial, ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, ch)
+	}, nil
+}
+
+// notify tells the sampler a job's state was just written, so subscribers
+// hear about it without waiting for the next poll.
+func (b *stateBroadcaster) notify() {
+	b.mu.Lock()
+	changed := b.changed
+	b.mu.Unlock()
+
+	if changed == nil {
+		return
+	}
+	select {
+	case changed <- struct{}{}:
+	default:
+		// a notification is already pending
+	}
+}
+
+// changePollInterval is how often jobs are sampled for changes when full
+// snapshots come every interval
+func changePollInterval(interval time.Duration) time.Duration {
+	if interval < stateChangePollInterval {
+		return interval
+	}
+	return stateChangePollInterval
+}
+
+func (b *stateBroadcaster) run(interval func() time.Duration, snapshot func() ([]*task.ProcessState, error)) {
+	current := interval()
+	full := time.NewTicker(current)
+	defer full.Stop()
+	changes := time.NewTicker(changePollInterval(current))
+	defer changes.Stop()
+
+	for {
+		var onlyChanged bool
+		select {
+		case <-full.C:
+		case <-changes.C:
+			onlyChanged = true
+		case <-b.changed:
+			onlyChanged = true
+		}
+
+		// stop sampling once everyone has gone; the next subscriber restarts us
+		b.mu.Lock()
+		if len(b.subs) == 0 {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		b.mu.Unlock()
+
+		if next := interval(); next != current {
+			current = next
+			full.Reset(current)
+			changes.Reset(changePollInterval(current))
+		}
+
+		states, err := snapshot()
+		if err != nil {
+			continue
+		}
+
+		b.publish(states, onlyChanged)
+	}
+}
+
+// publish sends states to every subscriber, or only the ones that changed
+// since they were last sent if onlyChanged is set.
+func (b *stateBroadcaster) publish(states []*task.ProcessState, onlyChanged bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, state := range states {
+		fp := stateFingerprint(state)
+		if onlyChanged && b.last[state.JobID] == fp {
+			continue
+		}
+		b.last[state.JobID] = fp
+		b.send(state)
+	}
+}
+
+func (b *stateBroadcaster) send(state *task.ProcessState) {
+	for ch := range b.subs {
+		select {
+		case ch <- state:
+		default:
+			// slow subscriber; it catches up on the next full snapshot
+		}
+	}
+}
+
+// stateFingerprint captures the parts of a state an orchestrator acts on.
+// Memory usage is left out since it changes on nearly every sample.
+func stateFingerprint(state *task.ProcessState) string {
+	fp := fmt.Sprintf("%d/%v/%v/%s/%s", state.PID, state.Flag, state.CheckpointState, state.CheckpointPath, state.CheckpointFailureReason)
+	if info := state.ProcessInfo; info != nil {
+		fp += fmt.Sprintf("/%v/%s/%d", info.IsRunning, info.Status, len(info.OpenConnections))
+	}
+	return fp
+}
+
+func (s *service) stateStreamInterval() time.Duration {
+	if secs := s.client.config().Client.StateStreamInterval; secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return defaultStateStreamInterval
+}
+
+// jobStates returns the state of every managed job, with live process info
+// for the ones that should be running.
+func (s *service) jobStates() ([]*task.ProcessState, error) {
+	states, err := s.client.db.ListJobStates()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*task.ProcessState
+	for id, state := range states {
+		state.JobID = id
+		if state.Flag == task.FlagEnum_JOB_RUNNING {
+			live, _ := s.client.generateState(state.PID)
+			if live != nil {
+				state.ProcessInfo = live.ProcessInfo
+			} else {
+				// the process is gone but nothing has recorded it yet
+				state.ProcessInfo = &task.ProcessInfo{PID: state.PID}
+			}
+		}
+		out = append(out, state)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].JobID < out[j].JobID })
+	return out, nil
+}
+
+// ClientStateStreaming sends the state of every managed job when a client
+// connects, then whenever a job changes and on a full snapshot every
+// client.state_stream_interval seconds. Any number of clients can subscribe.
+func (s *service) ClientStateStreaming(stream task.TaskService_ClientStateStreamingServer) error {
+	states, updates, unsubscribe, err := s.states.subscribe(s.stateStreamInterval, s.jobStates)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	for _, state := range states {
+		if err := stream.Send(state); err != nil {
+			return err
+		}
+	}
+
+	// the client's messages aren't acted on yet, but Recv is how we learn it left
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				recvErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case state := <-updates:
+			if err := stream.Send(state); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err == io.EOF {
+				s.logger.Debug().Msgf("Client has closed connection")
+				return nil
+			}
+			return err
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+)
+
+type fakeJobs struct {
+	mu     sync.Mutex
+	states map[string]task.FlagEnum
+}
+
+func (f *fakeJobs) set(id string, flag task.FlagEnum) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.states[id] = flag
+}
+
+func (f *fakeJobs) snapshot() ([]*task.ProcessState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*task.ProcessState
+	for id, flag := range f.states {
+		out = append(out, &task.ProcessState{JobID: id, PID: 1, Flag: flag})
+	}
+	return out, nil
+}
+
+func every(d time.Duration) func() time.Duration {
+	return func() time.Duration { return d }
+}
+
+func receive(t *testing.T, ch <-chan *task.ProcessState) *task.ProcessState {
+	t.Helper()
+	select {
+	case state := <-ch:
+		return state
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for state")
+		return nil
+	}
+}
+
+func expectNothing(t *testing.T, ch <-chan *task.ProcessState) {
+	t.Helper()
+	select {
+	case state := <-ch:
+		t.Fatalf("unexpected state %v", state)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func subscribe(t *testing.T, b *stateBroadcaster, interval func() time.Duration, snapshot func() ([]*task.ProcessState, error)) ([]*task.ProcessState, <-chan *task.ProcessState, func()) {
+	t.Helper()
+	initial, updates, unsubscribe, err := b.subscribe(interval, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return initial, updates, unsubscribe
+}
+
+func TestStateBroadcaster_PublishesChangesToAllSubscribers(t *testing.T) {
+	jobs := &fakeJobs{states: map[string]task.FlagEnum{
+		"a": task.FlagEnum_JOB_RUNNING,
+		"b": task.FlagEnum_JOB_RUNNING,
+	}}
+
+	var b stateBroadcaster
+	// an hour-long interval keeps full snapshots out of the way
+	initial1, sub1, unsub1 := subscribe(t, &b, every(time.Hour), jobs.snapshot)
+	defer unsub1()
+	initial2, sub2, unsub2 := subscribe(t, &b, every(time.Hour), jobs.snapshot)
+	defer unsub2()
+
+	// subscribers start from every job
+	for _, initial := range [][]*task.ProcessState{initial1, initial2} {
+		if len(initial) != 2 {
+			t.Fatalf("expected both jobs, got %v", initial)
+		}
+	}
+
+	// and aren't sent them again on the first sample, or any after, unchanged
+	b.notify()
+	expectNothing(t, sub1)
+	expectNothing(t, sub2)
+
+	jobs.set("b", task.FlagEnum_JOB_DONE)
+	b.notify()
+	for _, sub := range []<-chan *task.ProcessState{sub1, sub2} {
+		state := receive(t, sub)
+		if state.JobID != "b" || state.Flag != task.FlagEnum_JOB_DONE {
+			t.Fatalf("expected b to be done, got %v", state)
+		}
+	}
+	expectNothing(t, sub1)
+}
+
+func TestStateBroadcaster_FullSnapshotOnInterval(t *testing.T) {
+	jobs := &fakeJobs{states: map[string]task.FlagEnum{"a": task.FlagEnum_JOB_RUNNING}}
+
+	var b stateBroadcaster
+	_, sub, unsub := subscribe(t, &b, every(20*time.Millisecond), jobs.snapshot)
+	defer unsub()
+
+	// the same, unchanged state keeps arriving
+	for i := 0; i < 3; i++ {
+		if state := receive(t, sub); state.JobID != "a" {
+			t.Fatalf("unexpected job %s", state.JobID)
+		}
+	}
+}
+
+func TestStateBroadcaster_IntervalChanges(t *testing.T) {
+	jobs := &fakeJobs{states: map[string]task.FlagEnum{"a": task.FlagEnum_JOB_RUNNING}}
+	var interval atomic.Int64
+	interval.Store(int64(time.Hour))
+
+	var b stateBroadcaster
+	_, sub, unsub := subscribe(t, &b, func() time.Duration { return time.Duration(interval.Load()) }, jobs.snapshot)
+	defer unsub()
+
+	// as a config reload would, while the sampler is already running
+	interval.Store(int64(20 * time.Millisecond))
+	b.notify()
+	for i := 0; i < 3; i++ {
+		if state := receive(t, sub); state.JobID != "a" {
+			t.Fatalf("unexpected job %s", state.JobID)
+		}
+	}
+}
+
+func TestStateBroadcaster_StopsWithoutSubscribers(t *testing.T) {
+	jobs := &fakeJobs{states: map[string]task.FlagEnum{}}
+
+	var b stateBroadcaster
+	_, _, unsub := subscribe(t, &b, every(10*time.Millisecond), jobs.snapshot)
+	unsub()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		b.mu.Lock()
+		running := b.running
+		b.mu.Unlock()
+		if !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("sampler kept running with no subscribers")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"path/filepath"
 	"syscall"
 	"testing"
 
@@ -71,7 +72,7 @@ func Test_MultiConn(t *testing.T) {
 		srv.Stop()
 	})
 
-	mockDB, err := bolt.Open("test.db", 0600, nil)
+	mockDB, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
 	t.Cleanup(func() {
 		mockDB.Close()
 	})
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	// how long before the provider's termination time checkpoints must be
+	// done, leaving room to report back
+	terminationMargin = 5 * time.Second
+	// how long checkpoints get when the notice came too late to leave the
+	// margin, or after the termination time; some may still make it
+	terminationBestEffort = 10 * time.Second
+)
+
+// instanceTermination is the reason recorded for checkpoints taken because
+// the instance is going away
+var instanceTermination = &task.CheckpointReason{Reason: task.CheckpointReason_INSTANCE_TERMINATION}
+
+// Statuses sent back on MetaStateStreaming
+const (
+	metaStatusAck              = "ACK"
+	metaStatusCheckpointed     = "CHECKPOINTED"
+	metaStatusCheckpointFailed = "CHECKPOINT_FAILED"
+	metaStatusDone             = "DONE"
+)
+
+// dumper returns what checkpoints a job on the daemon's own behalf; tests
+// swap it out so they don't need CRIU.
+func (s *service) dumper() func(context.Context, *task.DumpArgs) (*task.DumpResp, error) {
+	if s.dump != nil {
+		return s.dump
+	}
+	return s.Dump
+}
+
+// runningJobs lists the IDs of jobs whose process is still alive. Jobs whose
+// process died without the DB hearing about it are skipped.
+func (s *service) runningJobs() ([]string, error) {
+	states, err := s.client.db.ListJobStates()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for id, state := range states {
+		if state.Flag != task.FlagEnum_JOB_RUNNING || state.PID == 0 {
+			continue
+		}
+		if alive, _ := process.PidExists(state.PID); !alive {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// checkpointOpts tunes a checkpointJobs run
+type checkpointOpts struct {
+	// how many jobs are checkpointed at once; 0 means all of them
+	parallel int
+	// compression codec for the checkpoints, see DumpArgs.Compression
+	compression string
+	// recorded in each job's history, see DumpArgs.Reason
+	reason *task.CheckpointReason
+}
+
+// checkpointJobs checkpoints the given jobs, taking them in order, uploading
+// too when client.remote is set, and reports each job's outcome as it
+// finishes. Jobs still going when ctx expires are reported as failed.
+func (s *service) checkpointJobs(ctx context.Context, ids []string, opts checkpointOpts, report func(*task.MetaStateStreamingResp)) {
+	cfg := s.client.config()
+	dir := cfg.SharedStorage.DumpStorageDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	dumpType := task.DumpArgs_LOCAL
+	if cfg.Client.Remote {
+		dumpType = task.DumpArgs_REMOTE
+	}
+
+	parallel := opts.parallel
+	if parallel <= 0 || parallel > len(ids) {
+		parallel = len(ids)
+	}
+
+	dump := s.dumper()
+	queue := make(chan string, len(ids))
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	var reportMu sync.Mutex
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				resp := &task.MetaStateStreamingResp{JobID: id, Status: metaStatusCheckpointed}
+				dumpResp, err := dump(ctx, &task.DumpArgs{JobID: id, Dir: dir, Type: dumpType, Compression: opts.compression, Reason: opts.reason})
+				if err != nil {
+					resp.Status = metaStatusCheckpointFailed
+					resp.Error = err.Error()
+				} else {
+					resp.CheckpointID = dumpResp.CheckpointID
+					if state, err := s.client.db.GetStateFromID(id); err == nil {
+						resp.CheckpointPath = state.CheckpointPath
+					}
+				}
+
+				reportMu.Lock()
+				report(resp)
+				reportMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// terminationContext bounds the checkpoints for an instance terminating at
+// terminationTime (unix seconds, 0 if unknown). A notice that leaves less
+// than terminationBestEffort still gets that long, rather than every dump
+// failing without being tried. It deliberately isn't derived from the
+// request: the checkpoints should finish even if the orchestrator's
+// connection drops first.
+func terminationContext(terminationTime int64) (context.Context, context.CancelFunc) {
+	if terminationTime == 0 {
+		return context.WithCancel(context.Background())
+	}
+	deadline := time.Unix(terminationTime, 0).Add(-terminationMargin)
+	if earliest := time.Now().Add(terminationBestEffort); deadline.Before(earliest) {
+		deadline = earliest
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// MetaStateStreaming takes provider events from the orchestrator. When the
+// instance is marked for termination every running job is checkpointed, and
+// the stream gets one reply per job followed by DONE. Other events are
+// acknowledged.
+func (s *service) MetaStateStreaming(stream task.TaskService_MetaStateStreamingServer) error {
+	// instances whose termination was already handled on this stream, so a
+	// provider repeating its notice doesn't checkpoint everything again
+	handled := make(map[string]bool)
+
+	for {
+		args, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		event := args.GetEvent()
+		if !event.GetMarkedForTermination() || handled[event.InstanceID] {
+			if err := stream.Send(&task.MetaStateStreamingResp{Status: metaStatusAck}); err != nil {
+				return err
+			}
+			continue
+		}
+		handled[event.InstanceID] = true
+
+		s.logger.Warn().Msgf("instance %s marked for termination (fault code %q), checkpointing all jobs", event.InstanceID, event.FaultCode)
+
+		ids, err := s.runningJobs()
+		if err != nil {
+			return err
+		}
+
+		// the orchestrator may say why; an instance going away otherwise
+		reason := args.GetCheckpointReason()
+		if reason == nil {
+			reason = instanceTermination
+		}
+
+		ctx, cancel := terminationContext(event.TerminationTime)
+		var sendErr error
+		s.checkpointJobs(ctx, ids, checkpointOpts{reason: reason}, func(resp *task.MetaStateStreamingResp) {
+			if resp.Status == metaStatusCheckpointFailed {
+				s.logger.Error().Msgf("could not checkpoint job %s before termination: %s", resp.JobID, resp.Error)
+			}
+			// keep checkpointing even if the orchestrator went away
+			if sendErr == nil {
+				sendErr = stream.Send(resp)
+			}
+		})
+		cancel()
+
+		if sendErr != nil {
+			return sendErr
+		}
+		if err := stream.Send(&task.MetaStateStreamingResp{Status: metaStatusDone}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+	"google.golang.org/grpc"
+)
+
+// fakeMetaStream plays a scripted list of events, then EOF
+type fakeMetaStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*task.MetaStateStreamingArgs
+	sent   []*task.MetaStateStreamingResp
+}
+
+func (f *fakeMetaStream) Context() context.Context { return f.ctx }
+
+func (f *fakeMetaStream) Recv() (*task.MetaStateStreamingArgs, error) {
+	if len(f.events) == 0 {
+		return nil, io.EOF
+	}
+	event := f.events[0]
+	f.events = f.events[1:]
+	return event, nil
+}
+
+func (f *fakeMetaStream) Send(resp *task.MetaStateStreamingResp) error {
+	f.sent = append(f.sent, resp)
+	return nil
+}
+
+func TestMetaStateStreaming_CheckpointsOnTermination(t *testing.T) {
+	c, err := InstantiateClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := utils.GetLogger()
+
+	terminationTime := time.Now().Add(2 * time.Minute)
+	failing := fmt.Sprintf("termination-test-%d-1", os.Getpid())
+
+	svc := &service{client: c, logger: &logger}
+	svc.dump = func(ctx context.Context, args *task.DumpArgs) (*task.DumpResp, error) {
+		if deadline, ok := ctx.Deadline(); !ok || deadline.After(terminationTime) {
+			t.Errorf("dump of %s not bounded by termination time (deadline %v)", args.JobID, deadline)
+		}
+		if args.Reason.GetReason() != task.CheckpointReason_JOB_TERMINATION {
+			t.Errorf("dump of %s: expected the orchestrator's reason, got %v", args.JobID, args.Reason)
+		}
+		if args.JobID == failing {
+			return nil, fmt.Errorf("criu said no")
+		}
+		return &task.DumpResp{CheckpointID: "ckpt-" + args.JobID}, nil
+	}
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("termination-test-%d-%d", os.Getpid(), i)
+		resp, err := svc.StartTask(ctx, &task.StartTaskArgs{
+			Task:          "sleep 60",
+			Id:            id,
+			LogOutputFile: filepath.Join(tmp, id+".log"),
+			UID:           uint32(os.Getuid()),
+			GID:           uint32(os.Getgid()),
+		})
+		if err != nil {
+			t.Fatalf("failed to start task: %v", err)
+		}
+		t.Cleanup(func() { syscall.Kill(int(resp.PID), syscall.SIGKILL) })
+		ids = append(ids, id)
+	}
+
+	event := &task.MetaStateStreamingArgs{
+		Event: &task.ProviderEvent{
+			InstanceID:           "i-test",
+			MarkedForTermination: true,
+			TerminationTime:      terminationTime.Unix(),
+		},
+		CheckpointReason: &task.CheckpointReason{Reason: task.CheckpointReason_JOB_TERMINATION},
+	}
+	stream := &fakeMetaStream{
+		ctx: ctx,
+		events: []*task.MetaStateStreamingArgs{
+			{Event: &task.ProviderEvent{InstanceID: "i-test"}},
+			event,
+			// providers repeat their notice; it must not checkpoint twice
+			event,
+		},
+	}
+
+	if err := svc.MetaStateStreaming(stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if stream.sent[0].Status != metaStatusAck {
+		t.Errorf("expected plain event to be acked, got %v", stream.sent[0])
+	}
+	if last := stream.sent[len(stream.sent)-1]; last.Status != metaStatusAck {
+		t.Errorf("expected repeated notice to be acked, got %v", last)
+	}
+
+	replies := map[string]*task.MetaStateStreamingResp{}
+	var done bool
+	for _, resp := range stream.sent[1 : len(stream.sent)-1] {
+		if resp.Status == metaStatusDone {
+			done = true
+			continue
+		}
+		if done {
+			t.Errorf("job reply %v after DONE", resp)
+		}
+		replies[resp.JobID] = resp
+	}
+	if !done {
+		t.Error("no DONE reply after checkpointing")
+	}
+
+	for _, id := range ids {
+		resp, ok := replies[id]
+		if !ok {
+			t.Errorf("no reply for job %s", id)
+			continue
+		}
+		if id == failing {
+			if resp.Status != metaStatusCheckpointFailed || resp.Error == "" {
+				t.Errorf("expected failure for %s, got %v", id, resp)
+			}
+			continue
+		}
+		if resp.Status != metaStatusCheckpointed || resp.CheckpointID != "ckpt-"+id {
+			t.Errorf("expected checkpoint for %s, got %v", id, resp)
+		}
+	}
+}
+
+func TestTerminationContext(t *testing.T) {
+	if _, ok := terminationContextDeadline(0); ok {
+		t.Error("expected no deadline without a termination time")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if deadline, _ := terminationContextDeadline(later.Unix()); !deadline.Equal(time.Unix(later.Unix(), 0).Add(-terminationMargin)) {
+		t.Errorf("expected the margin before termination, got %v", deadline)
+	}
+
+	// too late to leave the margin, or already past: still worth trying
+	for _, at := range []time.Time{time.Now().Add(2 * time.Second), time.Now().Add(-time.Minute)} {
+		deadline, ok := terminationContextDeadline(at.Unix())
+		if left := time.Until(deadline); !ok || left < terminationBestEffort-time.Second {
+			t.Errorf("termination at %v: expected a best effort deadline, got %v left", at, left)
+		}
+	}
+}
+
+func terminationContextDeadline(terminationTime int64) (time.Time, bool) {
+	ctx, cancel := terminationContext(terminationTime)
+	defer cancel()
+	return ctx.Deadline()
+}
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+
 	"github.com/cedana/cedana/utils"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -26,7 +30,19 @@ var bootstrapCmd = &cobra.Command{
 	},
 }
 
+// bootstrap writes a sample user config, unless there's one already, and
+// checks the config loads.
 func (b *Bootstrap) bootstrap() {
+	if path := utils.UserConfigPath(); path != "" {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				b.l.Fatal().Err(err).Msg("could not create config directory")
+			}
+			if err := os.WriteFile(path, []byte(utils.GenSampleConfig()), 0o664); err != nil {
+				b.l.Fatal().Err(err).Msg("could not write sample config")
+			}
+		}
+	}
 	_, err := utils.InitConfig()
 	if err != nil {
 		b.l.Fatal().Err(err).Msg("could not initiate generated config")
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -67,7 +68,7 @@ func main() {
 		}
 	}()
 	req := &task.CtrByNameArgs{}
-	cts.GetRuncIdByName(req)
+	cts.GetRuncIdByName(context.Background(), req)
 
 	select {}
 }
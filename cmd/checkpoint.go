@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cedana/cedana/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+)
+
+var trustKeys []string
+
+var checkpointCmd = &cobra.Command{
+	Use:   "checkpoint",
+	Short: "List checkpoints and inspect their signatures",
+}
+
+var checkpointVerifyCmd = &cobra.Command{
+	Use:   "verify <path>",
+	Short: "Check a checkpoint's signature offline",
+	Long: "Check the signature of a checkpoint archive, against the manifest written next to it, " +
+		"or of a chunked checkpoint's manifest. The signer must be one of signing.trusted_keys, " +
+		"--trust or this node; with none of those configured only the signature itself is checked.",
+	Args:    cobra.ExactArgs(1),
+	Example: "cedana checkpoint verify /tmp/dumps/job-1.tar --trust <base64 public key>",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := utils.InitConfig()
+		if err != nil {
+			return err
+		}
+		signing := cfg.Signing
+		signing.TrustedKeys = append(append([]string{}, signing.TrustedKeys...), trustKeys...)
+		trusted, err := utils.NewTrustedKeys(signing)
+		if err != nil {
+			return err
+		}
+
+		manifest, err := utils.VerifyCheckpointFile(cmd.Context(), args[0], trusted)
+		if err != nil {
+			return err
+		}
+		signer, err := manifest.Signer()
+		if err != nil {
+			return err
+		}
+
+		result := verifyResult{
+			Path:      args[0],
+			Signer:    utils.PublicKeyString(signer),
+			Trusted:   trusted != nil,
+			JobID:     manifest.JobID,
+			Created:   manifest.Created,
+			SizeBytes: manifest.Size,
+			Checksum:  manifest.Checksum,
+		}
+		if manifest.Encryption != nil {
+			result.EncryptionKeyID = manifest.Encryption.KeyID
+		}
+		return printResult(result, func() {
+			verdict := "trusted"
+			if trusted == nil {
+				verdict = "no trusted keys configured, signer not checked"
+			}
+			fmt.Fprintf(stdout, "%s: valid signature by %s (%s)\n", args[0], result.Signer, verdict)
+			fmt.Fprintf(stdout, "  job:      %s\n", manifest.JobID)
+			fmt.Fprintf(stdout, "  created:  %s\n", manifest.Created)
+			fmt.Fprintf(stdout, "  size:     %d bytes, %s\n", manifest.Size, manifest.Checksum)
+			if manifest.Encryption != nil {
+				fmt.Fprintf(stdout, "  encrypted with key %s\n", manifest.Encryption.KeyID)
+			}
+		})
+	},
+}
+
+var checkpointKeyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Print this node's public signing key, generating it if needed",
+	Long:  "Print this node's public signing key. Add it to signing.trusted_keys on the nodes that should restore checkpoints made here.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := utils.InitConfig()
+		if err != nil {
+			return err
+		}
+		key, err := utils.LoadNodeKey(cfg.Signing.KeyFile)
+		if err != nil {
+			return err
+		}
+		public := utils.PublicKeyString(key.Public().(ed25519.PublicKey))
+		return printResult(keyResult{PublicKey: public}, func() {
+			fmt.Fprintln(stdout, public)
+		})
+	},
+}
+
+var checkpointListCmd = &cobra.Command{
+	Use:   "list [job]",
+	Short: "List the checkpoints in the configured store",
+	Long: "List the checkpoints in the store the configuration points at, all of them or those of one job. " +
+		"The store is read directly, so the daemon needn't be running.",
+	Args:    cobra.MaximumNArgs(1),
+	Example: "cedana checkpoint list job-1 -o json",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := utils.InitConfig()
+		if err != nil {
+			return err
+		}
+		store, err := utils.NewStore(cfg, otel.Tracer("cedana-cli"))
+		if err != nil {
+			return err
+		}
+		list, err := store.ListCheckpoints(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("listing checkpoints: %w", err)
+		}
+
+		result := checkpointListResult{Checkpoints: []storedCheckpointResult{}}
+		for _, meta := range *list {
+			if len(args) == 1 && meta.JobID != args[0] {
+				continue
+			}
+			result.Checkpoints = append(result.Checkpoints, storedCheckpointResult{
+				CheckpointID: meta.ID,
+				JobID:        meta.JobID,
+				Time:         meta.ModTime.UTC(),
+				SizeBytes:    int64(meta.Size),
+				Checksum:     meta.Checksum,
+			})
+		}
+		sort.SliceStable(result.Checkpoints, func(i, j int) bool {
+			return result.Checkpoints[i].Time.Before(result.Checkpoints[j].Time)
+		})
+
+		return printResult(result, func() {
+			now := time.Now()
+			table := tablewriter.NewWriter(stdout)
+			table.SetHeader([]string{"Checkpoint", "Job", "Created", "Size"})
+			table.SetAutoWrapText(false)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			for _, c := range result.Checkpoints {
+				table.Append([]string{c.CheckpointID, c.JobID, formatAgo(c.Time, now), formatBytes(c.SizeBytes)})
+			}
+			table.Render()
+		})
+	},
+}
+
+func init() {
+	checkpointCmd.AddCommand(checkpointListCmd)
+	checkpointVerifyCmd.Flags().StringSliceVar(&trustKeys, "trust", nil, "additional base64 public key to trust")
+	checkpointCmd.AddCommand(checkpointVerifyCmd)
+	checkpointCmd.AddCommand(checkpointKeyCmd)
+	rootCmd.AddCommand(checkpointCmd)
+}
@@ -1,22 +1,20 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/cedana/cedana/api"
 	"github.com/cedana/cedana/api/services"
 	"github.com/cedana/cedana/api/services/task"
 	"github.com/cedana/cedana/utils"
-	"github.com/olekukonko/tablewriter"
 	"github.com/rs/xid"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc/status"
-
-	bolt "go.etcd.io/bbolt"
 )
 
 var dir string
@@ -40,6 +38,10 @@ var tcpEstablished bool
 // working directory for execTask
 var wd string
 var asRoot bool
+var execTags []string
+var compression string
+var retryPush bool
+var splitStderr bool
 
 type CLI struct {
 	cfg    *utils.Config
@@ -53,9 +55,16 @@ func NewCLI() (*CLI, error) {
 		return nil, err
 	}
 
-	cts, _ := services.NewClient("localhost:8080")
+	cts, err := services.NewClient(daemonAddr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the daemon at %s: %w", daemonAddr, err)
+	}
 
 	logger := utils.GetLogger()
+	if structuredOutput() {
+		// stdout is for the result
+		logger = logger.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	}
 
 	return &CLI{
 		cfg:    cfg,
@@ -64,13 +73,26 @@ func NewCLI() (*CLI, error) {
 	}, nil
 }
 
+// cancelOnInterrupt makes Ctrl-C cancel cmd.Context(), which aborts the in-flight
+// dump/restore on the daemon side too. Once cancelled the default signal
+// behaviour is restored, so a second Ctrl-C kills the CLI outright.
+func cancelOnInterrupt(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	cmd.SetContext(ctx)
+}
+
 // --------------------
 // Top-level Dump/Restore CLI commands
 // --------------------
 
 var dumpCmd = &cobra.Command{
-	Use:   "dump",
-	Short: "Manually checkpoint a process or container to a directory: [process, runc (container), containerd (container)]",
+	Use:              "dump",
+	Short:            "Manually checkpoint a process or container to a directory: [process, runc (container), containerd (container)]",
+	PersistentPreRun: cancelOnInterrupt,
 }
 
 // ---------------
@@ -106,27 +128,22 @@ var dumpProcessCmd = &cobra.Command{
 
 		// always self serve when invoked from CLI
 		cpuDumpArgs := task.DumpArgs{
-			PID:   int32(pid),
-			Dir:   dir,
-			JobID: id,
-			Type:  task.DumpArgs_LOCAL,
-		}
-
-		resp, err := cli.cts.CheckpointTask(&cpuDumpArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v, %v: %v", st.Code(), st.Message(), st.Details())
-			} else {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v", err)
-			}
-		} else {
-			cli.logger.Info().Msgf("Response: %v", resp.Message)
+			PID:         int32(pid),
+			Dir:         dir,
+			JobID:       id,
+			Type:        task.DumpArgs_LOCAL,
+			Compression: compression,
 		}
 
+		start := time.Now()
+		resp, err := cli.cts.CheckpointTask(cmd.Context(), &cpuDumpArgs)
 		cli.cts.Close()
 
-		return nil
+		result := newDumpResult(resp, err, start)
+		result.JobID, result.PID, result.Dir = id, int32(pid), dir
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
@@ -145,21 +162,15 @@ var restoreProcessCmd = &cobra.Command{
 			CheckpointPath: args[0],
 		}
 
-		resp, err := cli.cts.RestoreTask(&restoreArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Restore task failed: %v, %v: %v", st.Code(), st.Message(), st.Details())
-			} else {
-				cli.logger.Error().Msgf("Restore task failed: %v", err)
-			}
-		}
-
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.RestoreTask(cmd.Context(), &restoreArgs)
 		cli.cts.Close()
 
-		return nil
+		result := newRestoreResult(resp, err, start)
+		result.CheckpointPath = restoreArgs.CheckpointPath
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
@@ -189,7 +200,8 @@ var dumpJobCmd = &cobra.Command{
 
 		id := args[0]
 
-		if dir == "" {
+		// a retried push goes from the archive already dumped
+		if dir == "" && !retryPush {
 			if cli.cfg.SharedStorage.DumpStorageDir == "" {
 				return fmt.Errorf("no dump directory specified")
 			}
@@ -198,33 +210,29 @@ var dumpJobCmd = &cobra.Command{
 		}
 
 		var taskType task.DumpArgs_DumpType
-		if os.Getenv("CEDANA_REMOTE") == "true" {
+		if cli.cfg.Client.Remote || retryPush {
 			taskType = task.DumpArgs_REMOTE
 		} else {
 			taskType = task.DumpArgs_LOCAL
 		}
 
 		dumpArgs := task.DumpArgs{
-			JobID: id,
-			Dir:   dir,
-			Type:  taskType,
-		}
-
-		resp, err := cli.cts.CheckpointTask(&dumpArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v: %v", st.Code(), st.Message())
-			} else {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v", err)
-			}
+			JobID:       id,
+			Dir:         dir,
+			Type:        taskType,
+			Compression: compression,
+			RetryPush:   retryPush,
 		}
 
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.CheckpointTask(cmd.Context(), &dumpArgs)
 		cli.cts.Close()
 
-		return err
+		result := newDumpResult(resp, err, start)
+		result.JobID, result.Dir = id, dir
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
@@ -244,9 +252,6 @@ var restoreJobCmd = &cobra.Command{
 			return err
 		}
 
-		// TODO NR: we shouldn't even be reading the db here!!
-		db := api.NewDB()
-
 		var uid uint32
 		var gid uint32
 
@@ -255,21 +260,23 @@ var restoreJobCmd = &cobra.Command{
 			gid = uint32(os.Getgid())
 		}
 
-		var restoreArgs task.RestoreArgs
-		if os.Getenv("CEDANA_REMOTE") == "true" {
-			jobState, err := db.GetStateFromID(args[0])
-			if err != nil {
-				return err
-			}
+		// the daemon knows the job's checkpoints, and says so if there's no
+		// such job
+		job, err := cli.cts.GetJob(cmd.Context(), &task.GetJobArgs{JobID: args[0]})
+		if err != nil {
+			return daemonError(err)
+		}
 
-			remoteState := jobState.GetRemoteState()
-			if remoteState == nil {
-				return fmt.Errorf("no remote state found for id %s", args[0])
+		var restoreArgs task.RestoreArgs
+		if cli.cfg.Client.Remote {
+			remoteState := job.Job.GetRemoteState()
+			if len(remoteState) == 0 {
+				return notFound("no remote state found for id %s", args[0])
 			}
 
 			//For now just grab latest checkpoint
 			if remoteState[len(remoteState)-1].CheckpointID == "" {
-				return fmt.Errorf("no checkpoint found for id %s", args[0])
+				return notFound("no checkpoint found for id %s", args[0])
 			}
 
 			restoreArgs = task.RestoreArgs{
@@ -281,18 +288,15 @@ var restoreJobCmd = &cobra.Command{
 				GID:            gid,
 			}
 		} else {
-			paths, err := db.GetLatestLocalCheckpoints(args[0])
-			if err != nil {
-				return err
+			checkpointPath := job.Job.GetCheckpointPath()
+			if checkpointPath == "" {
+				return notFound("no checkpoint found for id %s", args[0])
 			}
 
-			if len(paths) == 0 {
-				return fmt.Errorf("no checkpoint found for id %s", args[0])
+			if !structuredOutput() {
+				fmt.Printf("path: %v\n", checkpointPath)
 			}
 
-			fmt.Printf("paths: %v\n", paths)
-
-			checkpointPath := *paths[0]
 			restoreArgs = task.RestoreArgs{
 				CheckpointId:   "",
 				CheckpointPath: checkpointPath,
@@ -303,21 +307,15 @@ var restoreJobCmd = &cobra.Command{
 			}
 		}
 		// pass path to restore task
-		resp, err := cli.cts.RestoreTask(&restoreArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Restore task failed: %v: %v", st.Code(), st.Message())
-			} else {
-				cli.logger.Error().Msgf("Restore task failed: %v", err)
-			}
-		}
-
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.RestoreTask(cmd.Context(), &restoreArgs)
 		cli.cts.Close()
 
-		return nil
+		result := newRestoreResult(resp, err, start)
+		result.JobID, result.CheckpointID, result.CheckpointPath = args[0], restoreArgs.CheckpointId, restoreArgs.CheckpointPath
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
@@ -339,21 +337,17 @@ var containerdDumpCmd = &cobra.Command{
 			ContainerId: containerId,
 			Ref:         ref,
 		}
-		resp, err := cli.cts.CheckpointContainer(&dumpArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v, %v", st.Message(), st.Code())
-			} else {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v", err)
-			}
-		}
-
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.CheckpointContainer(cmd.Context(), &dumpArgs)
 		cli.cts.Close()
 
-		return nil
+		result := dumpResult{ContainerID: containerId, DurationMS: since(start), Error: newErrorResult(err)}
+		if resp != nil {
+			result.CheckpointPath, result.Message = resp.CheckpointPath, resp.Message
+		}
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
@@ -372,27 +366,24 @@ var containerdRestoreCmd = &cobra.Command{
 			ContainerId: containerId,
 		}
 
-		resp, err := cli.cts.RestoreContainer(restoreArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Restore task failed: %v, %v", st.Message(), st.Code())
-			} else {
-				cli.logger.Error().Msgf("Restore task failed: %v", err)
-			}
-		}
-
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.RestoreContainer(cmd.Context(), restoreArgs)
 		cli.cts.Close()
 
-		return nil
+		result := restoreResult{ContainerID: containerId, CheckpointPath: ref, DurationMS: since(start), Error: newErrorResult(err)}
+		if resp != nil {
+			result.Message = resp.Message
+		}
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
 var restoreCmd = &cobra.Command{
-	Use:   "restore",
-	Short: "Manually restore a process or container from a checkpoint located at input path: [process, runc (container), containerd (container)]",
+	Use:              "restore",
+	Short:            "Manually restore a process or container from a checkpoint located at input path: [process, runc (container), containerd (container)]",
+	PersistentPreRun: cancelOnInterrupt,
 }
 
 var execTaskCmd = &cobra.Command{
@@ -406,6 +397,11 @@ var execTaskCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, err := parseTags(execTags)
+		if err != nil {
+			return err
+		}
+
 		cli, err := NewCLI()
 		if err != nil {
 			return err
@@ -425,97 +421,41 @@ var execTaskCmd = &cobra.Command{
 		env = os.Environ()
 
 		taskArgs := &task.StartTaskArgs{
-			Task:       taskToExec,
-			Id:         args[1],
-			WorkingDir: wd,
-			Env:        env,
-			UID:        uid,
-			GID:        gid,
-		}
-
-		resp, err := cli.cts.StartTask(taskArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Start task failed: %v, %v", st.Message(), st.Code())
-			} else {
-				cli.logger.Error().Msgf("Start task failed: %v", err)
-			}
-		}
-
+			Task:        taskToExec,
+			Id:          args[1],
+			WorkingDir:  wd,
+			Env:         env,
+			UID:         uid,
+			GID:         gid,
+			Tags:        tags,
+			SplitStderr: splitStderr,
+		}
+
+		start := time.Now()
+		resp, err := cli.cts.StartTask(cmd.Context(), taskArgs)
 		cli.cts.Close()
-		fmt.Print(resp.PID)
-		return nil
-	},
-}
 
-var psCmd = &cobra.Command{
-	Use:   "ps",
-	Short: "List managed processes",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		cli, err := NewCLI()
-		if err != nil {
-			return err
+		result := execResult{JobID: args[1], DurationMS: since(start), Error: newErrorResult(err)}
+		if resp != nil {
+			result.PID, result.Message = resp.PID, resp.Message
 		}
-
-		// open db in read-only mode
-		conn, err := bolt.Open("/tmp/cedana.db", 0600, &bolt.Options{ReadOnly: true})
-		if err != nil {
-			cli.logger.Fatal().Err(err).Msg("Could not open or create db")
-			return err
-		}
-
-		defer conn.Close()
-
-		// job ID, PID, isRunning, CheckpointPath, Remote checkpoint ID
-		var data [][]string
-		err = conn.View(func(tx *bolt.Tx) error {
-			root := tx.Bucket([]byte("default"))
-			if root == nil {
-				return fmt.Errorf("could not find bucket")
-			}
-
-			return root.ForEachBucket(func(k []byte) error {
-				job := root.Bucket(k)
-				jobId := string(k)
-				return job.ForEach(func(k, v []byte) error {
-					var state task.ProcessState
-					var remoteCheckpointID string
-					var status string
-					err := json.Unmarshal(v, &state)
-					if err != nil {
-						return err
-					}
-
-					if state.RemoteState != nil {
-						//For now just grab latest checkpoint
-						remoteCheckpointID = state.RemoteState[len(state.RemoteState)-1].CheckpointID
-					}
-
-					if state.ProcessInfo != nil {
-						status = state.ProcessInfo.Status
-					}
-
-					data = append(data, []string{jobId, string(k), status, state.CheckpointPath, remoteCheckpointID})
-					return nil
-				})
-			})
+		return printResponse(result, err, func() {
+			fmt.Fprint(stdout, resp.PID)
 		})
+	},
+}
 
-		if err != nil {
-			return err
-		}
-
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"Job ID", "PID", "Status", "Local Checkpoint Path", "Remote Checkpoint ID"})
-
-		for _, v := range data {
-			table.Append(v)
+// parseTags parses key=value tags, as given to exec --tag
+func parseTags(tags []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("bad tag %q, expected key=value", tag)
 		}
-
-		table.Render() // Send output
-		return nil
-	},
+		parsed[key] = value
+	}
+	return parsed, nil
 }
 
 func initContainerdCommands() {
@@ -538,9 +478,12 @@ func init() {
 	dumpCmd.AddCommand(dumpProcessCmd)
 	dumpProcessCmd.Flags().StringVarP(&dir, "dir", "d", "", "directory to dump to")
 	dumpProcessCmd.MarkFlagRequired("dir")
+	dumpProcessCmd.Flags().StringVar(&compression, "compression", "", "checkpoint codec[:level], e.g. zstd:19; defaults to the configured one")
 
 	dumpCmd.AddCommand(dumpJobCmd)
 	dumpJobCmd.Flags().StringVarP(&dir, "dir", "d", "", "directory to dump to")
+	dumpJobCmd.Flags().StringVar(&compression, "compression", "", "checkpoint codec[:level], e.g. zstd:19; defaults to the configured one")
+	dumpJobCmd.Flags().BoolVar(&retryPush, "retry-push", false, "push the job's last checkpoint to the store again instead of dumping, resuming a push that failed")
 
 	restoreCmd.AddCommand(restoreProcessCmd)
 	restoreCmd.AddCommand(restoreJobCmd)
@@ -548,11 +491,12 @@ func init() {
 
 	execTaskCmd.Flags().StringVarP(&wd, "working-dir", "w", "", "working directory")
 	execTaskCmd.Flags().BoolVarP(&asRoot, "root", "r", false, "run as root")
+	execTaskCmd.Flags().StringArrayVar(&execTags, "tag", nil, "tag the job with key=value, to filter on with ps; can be repeated")
+	execTaskCmd.Flags().BoolVar(&splitStderr, "split-stderr", false, "log stderr to a file of its own rather than with stdout, so cedana logs --stream can show either alone")
 
 	rootCmd.AddCommand(dumpCmd)
 	rootCmd.AddCommand(restoreCmd)
 	rootCmd.AddCommand(execTaskCmd)
-	rootCmd.AddCommand(psCmd)
 	rootCmd.AddCommand(runcRoot)
 	initRuncCommands()
 
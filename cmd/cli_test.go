@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/cedana/cedana/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeService answers every request with err, or succeeds if it's nil
+type fakeService struct {
+	task.UnimplementedTaskServiceServer
+	err error
+
+	// what ListJobs was last asked for
+	listArgs *task.ListJobsArgs
+}
+
+func (s *fakeService) Dump(ctx context.Context, args *task.DumpArgs) (*task.DumpResp, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &task.DumpResp{Message: "dumped", PID: args.PID, CheckpointPath: filepath.Join(args.Dir, "ckpt.tar")}, nil
+}
+
+func (s *fakeService) Restore(ctx context.Context, args *task.RestoreArgs) (*task.RestoreResp, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &task.RestoreResp{Message: "restored", NewPID: 43}, nil
+}
+
+func (s *fakeService) StartTask(ctx context.Context, args *task.StartTaskArgs) (*task.StartTaskResp, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &task.StartTaskResp{Message: "started", PID: 42}, nil
+}
+
+func (s *fakeService) ListJobs(ctx context.Context, args *task.ListJobsArgs) (*task.ListJobsResp, error) {
+	s.listArgs = args
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &task.ListJobsResp{Jobs: []*task.Job{{
+		JobID:          "job-1",
+		PID:            42,
+		Flag:           task.FlagEnum_JOB_RUNNING,
+		Runtime:        "process",
+		Running:        true,
+		StartedAt:      time.Now().Add(-time.Hour).Unix(),
+		LastCheckpoint: &task.JobEvent{Type: task.JobEvent_CHECKPOINT, Time: 1700000000, CheckpointPath: "/ckpt/a.tar", Size: 4096},
+	}}}, nil
+}
+
+func (s *fakeService) GetJob(ctx context.Context, args *task.GetJobArgs) (*task.GetJobResp, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if args.JobID != "job-1" {
+		return nil, status.Errorf(codes.NotFound, "no job %s", args.JobID)
+	}
+	return &task.GetJobResp{
+		Job: &task.Job{JobID: "job-1", PID: 43, Flag: task.FlagEnum_JOB_RUNNING, Tags: map[string]string{"team": "ml"}, CheckpointPath: "/ckpt/a.tar"},
+		History: []*task.JobEvent{
+			{Type: task.JobEvent_START, Time: 1700000000, PID: 42},
+			{Type: task.JobEvent_CHECKPOINT, Time: 1700000060, PID: 42, CheckpointPath: "/ckpt/a.tar", Size: 4096, DurationMS: 1500,
+				Reason: &task.CheckpointReason{Reason: task.CheckpointReason_INSTANCE_TERMINATION}},
+			{Type: task.JobEvent_RESTORE, Time: 1700000120, PID: 43, CheckpointPath: "/ckpt/a.tar"},
+		},
+	}, nil
+}
+
+// fakeDaemon serves svc where the CLI looks for the daemon
+func fakeDaemon(t *testing.T, svc task.TaskServiceServer) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	task.RegisterTaskServiceServer(srv, svc)
+	go srv.Serve(lis)
+
+	addr := daemonAddr
+	daemonAddr = lis.Addr().String()
+	t.Cleanup(func() {
+		srv.Stop()
+		daemonAddr = addr
+	})
+}
+
+// run runs the command line args, returning the result it printed and its
+// error
+func run(t *testing.T, args ...string) (string, error) {
+	var out bytes.Buffer
+	stdout = &out
+	output = outputTable
+	defer func() {
+		stdout = os.Stdout
+		output = outputTable
+		resetFlags(rootCmd)
+	}()
+
+	rootCmd.SetArgs(append(args, "--config", filepath.Join(t.TempDir(), "none.json")))
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+// resetFlags puts the flags of cmd and its subcommands back to their
+// defaults, as they're package variables that outlive a run
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		err  error
+		args []string
+		code int
+	}{
+		{err: status.Error(codes.NotFound, "no such process"), args: []string{"dump", "process", "1234", "-d", dir}, code: 3},
+		{err: status.Error(codes.NotFound, "no such job"), args: []string{"dump", "job", "job-1", "-d", dir}, code: 3},
+		// used to dereference the missing response
+		{err: status.Error(codes.Internal, "criu failed"), args: []string{"restore", "process", "/tmp/ckpt.tar"}, code: 1},
+		{err: status.Error(codes.Unavailable, "shutting down"), args: []string{"exec", "sleep 1", "job-1"}, code: 4},
+		{err: status.Error(codes.InvalidArgument, "bad codec"), args: []string{"dump", "process", "1234", "-d", dir}, code: 2},
+		{err: status.Error(codes.Canceled, "cancelled"), args: []string{"restore", "process", "/tmp/ckpt.tar"}, code: 130},
+		{err: nil, args: []string{"dump", "process", "1234", "-d", dir}, code: 0},
+		{err: nil, args: []string{"restore", "process", "/tmp/ckpt.tar"}, code: 0},
+		{err: nil, args: []string{"restore", "job", "job-1"}, code: 0},
+		{err: nil, args: []string{"restore", "job", "job-2"}, code: 3},
+	}
+	for _, c := range cases {
+		fakeDaemon(t, &fakeService{err: c.err})
+		_, err := run(t, c.args...)
+		if code := ExitCode(err); code != c.code {
+			t.Errorf("%v with %v: expected to exit with %d, got %d (%v)", c.args, c.err, c.code, code, err)
+		}
+		if c.err != nil && !strings.Contains(fmt.Sprint(err), status.Convert(c.err).Message()) {
+			t.Errorf("%v: expected the daemon's message, got %v", c.args, err)
+		}
+	}
+
+	// no such RPC
+	fakeDaemon(t, &fakeService{})
+	if _, err := run(t, "runc", "get", "-c", "ctr"); ExitCode(err) != 1 {
+		t.Errorf("expected an unimplemented request to exit with 1, got %v", err)
+	}
+}
+
+func TestExitCodes_Unreachable(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := daemonAddr
+	daemonAddr = lis.Addr().String()
+	lis.Close()
+	t.Cleanup(func() { daemonAddr = addr })
+
+	_, err = run(t, "dump", "process", "1234", "-d", t.TempDir())
+	if ExitCode(err) != 4 || !strings.Contains(err.Error(), "can't reach the cedana daemon") {
+		t.Errorf("expected an unreachable daemon to be said so and exit with 4, got %v", err)
+	}
+
+	out, err := run(t, "exec", "sleep 1", "job-1", "-o", "json")
+	var result execResult
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("expected JSON, got %q: %v", out, jerr)
+	}
+	if ExitCode(err) != 4 || result.Error == nil || result.Error.Code != "Unavailable" ||
+		!strings.Contains(result.Error.Message, "can't reach the cedana daemon") {
+		t.Errorf("expected the error in the result, got %+v", result.Error)
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	dir := t.TempDir()
+	fakeDaemon(t, &fakeService{})
+	out, err := run(t, "dump", "process", "1234", "-d", dir, "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", out, err)
+	}
+	for key, want := range map[string]interface{}{
+		"pid":             1234.0,
+		"dir":             dir,
+		"checkpoint_path": filepath.Join(dir, "ckpt.tar"),
+		"message":         "dumped",
+		"error":           nil,
+	} {
+		if result[key] != want {
+			t.Errorf("%s: expected %v, got %v", key, want, result[key])
+		}
+	}
+	for _, key := range []string{"job_id", "checkpoint_id", "duration_ms"} {
+		if _, ok := result[key]; !ok {
+			t.Errorf("expected %s to always be there", key)
+		}
+	}
+
+	fakeDaemon(t, &fakeService{err: status.Error(codes.NotFound, "no such process")})
+	out, err = run(t, "dump", "process", "1234", "-d", dir, "-o", "yaml")
+	if ExitCode(err) != 3 || !strings.Contains(out, "code: NotFound") {
+		t.Errorf("expected the error in the YAML result, got %q (%v)", out, err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	for err, code := range map[error]int{
+		nil:                        0,
+		errors.New("local"):        1,
+		notFound("no job %s", "a"): 3,
+		fmt.Errorf("restoring: %w", status.Error(codes.DeadlineExceeded, "slow")): 5,
+		status.Error(codes.PermissionDenied, "no"):                                1,
+	} {
+		if got := ExitCode(err); got != code {
+			t.Errorf("%v: expected %d, got %d", err, code, got)
+		}
+	}
+}
+
+func TestPs(t *testing.T) {
+	svc := &fakeService{}
+	fakeDaemon(t, svc)
+
+	out, err := run(t, "ps", "--filter", "state=running", "--filter", "tag=team=ml", "--filter", "runtime=process", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.listArgs; got == nil || len(got.States) != 1 || got.States[0] != "running" ||
+		len(got.Tags) != 1 || got.Tags[0] != "team=ml" || got.Runtime != "process" {
+		t.Errorf("expected the filters to be passed on, got %v", got)
+	}
+	var list psResult
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", out, err)
+	}
+	if len(list.Jobs) != 1 {
+		t.Fatalf("expected one job, got %+v", list.Jobs)
+	}
+	job := list.Jobs[0]
+	if job.JobID != "job-1" || job.State != "running" || job.UptimeSeconds < 3600 ||
+		job.LastCheckpoint == nil || job.LastCheckpoint.SizeBytes != 4096 || job.LastCheckpoint.Event != "checkpoint" {
+		t.Errorf("unexpected job %+v", job)
+	}
+	if out, err = run(t, "ps"); err != nil || !strings.Contains(out, "SCHEDULE") {
+		t.Errorf("expected the table to have a schedule column, got %q, %v", out, err)
+	}
+
+	out, err = run(t, "ps", "job-1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var detail jobDetailResult
+	if err := json.Unmarshal([]byte(out), &detail); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", out, err)
+	}
+	if detail.PID != 43 || detail.Tags["team"] != "ml" || len(detail.History) != 3 || detail.History[2].Event != "restore" {
+		t.Errorf("unexpected job detail %+v", detail)
+	}
+	if reason := detail.History[1].Reason; reason != "instance_termination" || detail.History[0].Reason != "" {
+		t.Errorf("expected only the checkpoint to have a reason, got %q", reason)
+	}
+	if out, err = run(t, "ps", "job-1"); err != nil || !strings.Contains(out, "checkpoint (instance termination)") {
+		t.Errorf("expected the table to say why the job was checkpointed, got %q, %v", out, err)
+	}
+
+	if _, err := run(t, "ps", "job-2"); ExitCode(err) != 3 {
+		t.Errorf("expected an unknown job to exit with 3, got %v", err)
+	}
+	if _, err := run(t, "ps", "--filter", "colour=blue"); err == nil || !strings.Contains(err.Error(), "unknown filter") {
+		t.Errorf("expected an unknown filter to be refused, got %v", err)
+	}
+}
+
+func TestCheckpointList(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CEDANA_STORE_BACKEND", "local")
+	t.Setenv("CEDANA_STORE_LOCAL_DIR", dir)
+	t.Setenv("CEDANA_STORE_CACHE_DIR", t.TempDir())
+
+	store, err := utils.NewLocalStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range []string{"job-1", "job-2"} {
+		archive := filepath.Join(t.TempDir(), "ckpt.tar")
+		if err := os.WriteFile(archive, []byte("ckpt "+job), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.PushCheckpoint(context.Background(), job, archive); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := run(t, "checkpoint", "list", "job-1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list checkpointListResult
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", out, err)
+	}
+	if len(list.Checkpoints) != 1 || list.Checkpoints[0].JobID != "job-1" || list.Checkpoints[0].SizeBytes != 10 {
+		t.Errorf("expected job-1's checkpoint, got %+v", list.Checkpoints)
+	}
+
+	// the table goes where results go too
+	out, err = run(t, "checkpoint", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "job-1") || !strings.Contains(out, "job-2") {
+		t.Errorf("expected both jobs in the table, got %q", out)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "-", 512: "512 B", 1536: "1.5 KiB", 3 << 30: "3.0 GiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("%d: expected %s, got %s", n, want, got)
+		}
+	}
+}
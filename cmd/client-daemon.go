@@ -26,7 +26,12 @@ var startDaemonCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := utils.GetLogger()
 
-		stopOtel, err := utils.InitOtel(cmd.Context(), cmd.Parent().Version)
+		cfg, err := utils.InitConfig()
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Could not read config")
+		}
+
+		stopOtel, err := utils.InitOtel(cmd.Context(), cmd.Parent().Version, cfg.Otel)
 		if err != nil {
 			logger.Error().Err(err).Msg("Failed to initialize otel")
 		}
@@ -36,7 +41,7 @@ var startDaemonCmd = &cobra.Command{
 			go startProfiler()
 		}
 
-		if os.Getenv("CEDANA_GPU_ENABLED") == "true" {
+		if cfg.GPU.Enabled {
 			err := pullGPUBinary("gpucontroller", "/usr/local/bin/gpu-controller")
 			if err != nil {
 				logger.Warn().Err(err).Msg("could not pull gpu controller")
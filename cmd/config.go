@@ -3,8 +3,12 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/cedana/cedana/api/services"
+	"github.com/cedana/cedana/api/services/task"
 	"github.com/cedana/cedana/utils"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -25,8 +29,9 @@ var showCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		fmt.Printf("config: %v\n", string(prettycfg))
-		return nil
+		return printResult(cfg, func() {
+			fmt.Fprintf(stdout, "config: %v\n", string(prettycfg))
+		})
 	},
 }
 
@@ -41,8 +46,153 @@ var generateCmd = &cobra.Command{
 	},
 }
 
+var getCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "print the value of a configuration key, e.g. store.s3.bucket",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := utils.InitConfig()
+		if err != nil {
+			return err
+		}
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var setCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "set a configuration key in the user's config file",
+	Long: "Set a configuration key in the user's config file, ~/.cedana/client_config.json unless --config says " +
+		"otherwise. Lists are given comma separated. The file is left as it was if the configuration wouldn't be valid.",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := utils.SetUserConfig(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("set %s in %s, run `cedana config reload` for a running daemon to pick it up\n", args[0], path)
+		return nil
+	},
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "check the configuration, or a config file on its own",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			if err := utils.ValidateConfigFile(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("%s is valid\n", args[0])
+			return nil
+		}
+		if _, err := utils.InitConfig(); err != nil {
+			return err
+		}
+		fmt.Println("config is valid")
+		return nil
+	},
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [key or section...]",
+	Short: "show every configuration value and where it came from",
+	Long: "Show every configuration value and the layer it came from: the defaults, the system file, the user file, " +
+		"server_overrides.json, the environment or a --set flag. Credentials are redacted.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := utils.InitConfig()
+		if err != nil {
+			return err
+		}
+
+		keys := utils.ConfigKeys()
+		if len(args) > 0 {
+			var filtered []string
+			for _, arg := range args {
+				n := len(filtered)
+				for _, key := range keys {
+					if key == arg || strings.HasPrefix(key, arg+".") {
+						filtered = append(filtered, key)
+					}
+				}
+				if len(filtered) == n {
+					return fmt.Errorf("unknown config key or section %q", arg)
+				}
+			}
+			keys = filtered
+		}
+
+		result := explainResult{Keys: []explainedKey{}}
+		for _, key := range keys {
+			value, err := cfg.Get(key)
+			if err != nil {
+				return err
+			}
+			if utils.SecretConfigKey(key) && value != "" {
+				value = "(redacted)"
+			}
+			result.Keys = append(result.Keys, explainedKey{Key: key, Value: value, Layer: cfg.Layer(key), Source: cfg.Source(key)})
+		}
+
+		return printResult(result, func() {
+			table := tablewriter.NewWriter(stdout)
+			table.SetHeader([]string{"Key", "Value", "Source"})
+			table.SetAutoWrapText(false)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			for _, k := range result.Keys {
+				source := k.Source
+				if strings.HasSuffix(k.Layer, "file") {
+					source = k.Layer + " " + source
+				}
+				table.Append([]string{k.Key, k.Value, source})
+			}
+			table.Render()
+		})
+	},
+}
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "have the daemon reload its configuration, as SIGHUP does",
+	Long: "Have the daemon reload its configuration. If the new configuration isn't valid the daemon " +
+		"keeps its current one. Changes to preemption, otel and kubernetes only take effect once the daemon restarts.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cts, err := services.NewClient(daemonAddr)
+		if err != nil {
+			return err
+		}
+		defer cts.Close()
+
+		resp, err := cts.ReloadConfig(cmd.Context(), &task.ReloadConfigArgs{})
+		if err != nil {
+			return daemonError(err)
+		}
+		if len(resp.Changed) == 0 {
+			fmt.Println("config reloaded, nothing changed")
+			return nil
+		}
+		fmt.Printf("config reloaded, changed: %s\n", strings.Join(resp.Changed, ", "))
+		if len(resp.RestartRequired) > 0 {
+			fmt.Printf("restart the daemon for these to take effect: %s\n", strings.Join(resp.RestartRequired, ", "))
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(showCmd)
 	configCmd.AddCommand(generateCmd)
+	configCmd.AddCommand(getCmd)
+	configCmd.AddCommand(setCmd)
+	configCmd.AddCommand(validateCmd)
+	configCmd.AddCommand(explainCmd)
+	configCmd.AddCommand(reloadCmd)
 }
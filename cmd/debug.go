@@ -3,12 +3,12 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/cedana/cedana/api"
 	"github.com/cedana/cedana/container"
 	"github.com/cedana/cedana/utils"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var debugCmd = &cobra.Command{
@@ -27,7 +27,7 @@ var cfgCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		fmt.Printf("config file used: %s", viper.GetViper().ConfigFileUsed())
+		fmt.Printf("config files used: %s\n", strings.Join(cfg.Files(), ", "))
 		// pretty print config for debugging to make sure it's been loaded correctly
 		prettyCfg, err := json.MarshalIndent(cfg, "", "  ")
 		if err != nil {
@@ -42,7 +42,18 @@ var compressCmd = &cobra.Command{
 	Use:  "compress",
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return utils.TarGzFolder(args[0], args[1])
+		spec, _ := cmd.Flags().GetString("compression")
+		threads, _ := cmd.Flags().GetInt("threads")
+		compression, err := utils.ParseCompression(spec)
+		if err != nil {
+			return err
+		}
+		var stats utils.TarStats
+		if err := utils.TarFolder(cmd.Context(), args[0], args[1], utils.WithCompression(compression), utils.WithThreads(threads), utils.WithStats(&stats)); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d bytes to %d, ratio %.2f\n", compression, stats.Size, stats.CompressedSize, stats.Ratio())
+		return nil
 	},
 }
 
@@ -50,7 +61,9 @@ var decompressCmd = &cobra.Command{
 	Use:  "decompress",
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return utils.UntarGzFolder(args[0], args[1])
+		threads, _ := cmd.Flags().GetInt("threads")
+		streamer := utils.DefaultConfig().Store.ImageStreamer
+		return utils.UntarFolder(cmd.Context(), args[0], args[1], utils.WithThreads(threads), utils.WithImageStreamer(streamer))
 	},
 }
 
@@ -115,7 +128,7 @@ var debugRuncDumpCmd = &cobra.Command{
 			TcpEstablished:  false,
 		}
 
-		client.RuncDump(cmd.Context(), root, containerId, criuOpts)
+		client.RuncDump(cmd.Context(), "", root, containerId, criuOpts)
 
 		return nil
 	},
@@ -128,5 +141,9 @@ func init() {
 	debugCmd.AddCommand(debugRuncRestoreCmd)
 	debugCmd.AddCommand(debugRuncDumpCmd)
 	debugCmd.AddCommand(compressCmd)
+	compressCmd.Flags().String("compression", utils.CodecGzip, "codec[:level], one of "+strings.Join(utils.Codecs(), ", "))
 	debugCmd.AddCommand(decompressCmd)
+	for _, cmd := range []*cobra.Command{compressCmd, decompressCmd} {
+		cmd.Flags().Int("threads", 0, "cores to use, 0 for half of them")
+	}
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// daemonAddr is where the CLI reaches the daemon
+var daemonAddr = "localhost:8080"
+
+// exitCodes are what the CLI exits with when a request to the daemon fails
+// with a code; any other failure exits with 1.
+var exitCodes = map[codes.Code]int{
+	codes.InvalidArgument:  2,
+	codes.NotFound:         3,
+	codes.Unavailable:      4,
+	codes.DeadlineExceeded: 5,
+	codes.Canceled:         130, // as for SIGINT, which is how it usually happens
+}
+
+// ExitCode is the status the CLI exits with after err, see exitCodes
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if st, ok := status.FromError(err); ok {
+		if code, ok := exitCodes[st.Code()]; ok {
+			return code
+		}
+	}
+	return 1
+}
+
+// rpcError is an error with a gRPC status, usually that of a failed request
+// to the daemon. It reads as the description of what went wrong, rather than
+// as gRPC's.
+type rpcError struct {
+	st *status.Status
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s: %s", e.st.Code(), e.st.Message())
+}
+
+func (e *rpcError) GRPCStatus() *status.Status {
+	return e.st
+}
+
+// daemonError is the error a command returns for err, from a request to the
+// daemon. A daemon that can't be reached at all is said to be so.
+func daemonError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	// the daemon's own Unavailable errors don't come from the transport
+	if st.Code() == codes.Unavailable && strings.Contains(st.Message(), "connection error") {
+		st = status.Newf(codes.Unavailable, "can't reach the cedana daemon at %s, is it running? Start it with `sudo cedana daemon start` (%s)",
+			daemonAddr, st.Message())
+	}
+	return &rpcError{st: st}
+}
+
+// notFound is an error for something the command was asked for that doesn't
+// exist, which exits as the daemon not finding it would.
+func notFound(format string, args ...interface{}) error {
+	return &rpcError{st: status.Newf(codes.NotFound, format, args...)}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var followLogs bool
+var logsOffset int64
+var logsStream string
+
+var logsCmd = &cobra.Command{
+	Use:     "logs <job id>",
+	Short:   "Print the stdout/stderr of a managed job, across restores",
+	Args:    cobra.ExactArgs(1),
+	PreRun:  cancelOnInterrupt,
+	Example: "cedana logs my-job -f\ncedana logs my-job --offset -4096 --stream stderr",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var stream task.StreamJobLogsArgs_LogStream
+		switch logsStream {
+		case "all", "":
+			stream = task.StreamJobLogsArgs_ALL
+		case "stdout":
+			stream = task.StreamJobLogsArgs_STDOUT
+		case "stderr":
+			stream = task.StreamJobLogsArgs_STDERR
+		default:
+			return fmt.Errorf("unknown stream %q, expected one of all, stdout, stderr", logsStream)
+		}
+
+		cli, err := NewCLI()
+		if err != nil {
+			return err
+		}
+		defer cli.cts.Close()
+
+		logs, err := cli.cts.StreamJobLogs(cmd.Context(), &task.StreamJobLogsArgs{
+			JobID:  args[0],
+			Follow: followLogs,
+			Offset: logsOffset,
+			Stream: stream,
+		})
+		if err != nil {
+			return daemonError(err)
+		}
+
+		for {
+			msg, err := logs.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				// Ctrl-C while following is how the user stops, not an error
+				if status.Code(err) == codes.Canceled {
+					return nil
+				}
+				return daemonError(err)
+			}
+
+			out := os.Stdout
+			if msg.Source == "stderr" {
+				out = os.Stderr
+			}
+			fmt.Fprint(out, msg.Msg)
+		}
+	},
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "keep streaming new output, including after restores")
+	logsCmd.Flags().Int64Var(&logsOffset, "offset", 0, "byte offset to start from; negative counts back from the end")
+	logsCmd.Flags().StringVar(&logsStream, "stream", "all", "which output to print: all, stdout or stderr; stdout includes stderr unless the job was started with --split-stderr")
+
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// outputFormat is the --output flag
+type outputFormat string
+
+func (o *outputFormat) String() string { return string(*o) }
+
+func (o *outputFormat) Set(s string) error {
+	switch s {
+	case outputTable, outputJSON, outputYAML:
+		*o = outputFormat(s)
+		return nil
+	}
+	return fmt.Errorf("expected table, json or yaml")
+}
+
+func (o *outputFormat) Type() string { return "format" }
+
+var output = outputFormat(outputTable)
+
+// stdout is where results are printed
+var stdout io.Writer = os.Stdout
+
+// structuredOutput is whether results are printed for scripts rather than
+// people, with logs kept off stdout.
+func structuredOutput() bool {
+	return output != outputTable
+}
+
+// printResult prints a command's result as JSON or YAML, or with table, which
+// writes it for people to read, if that's what --output asks for.
+func printResult(result interface{}, table func()) error {
+	switch output {
+	case outputJSON:
+		e := json.NewEncoder(stdout)
+		e.SetIndent("", "  ")
+		return e.Encode(result)
+	case outputYAML:
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(data)
+		return err
+	}
+	table()
+	return nil
+}
+
+// printResponse is printResult for the outcome of a request to the daemon.
+// Once the result is printed it returns the request's error, if any, which is
+// all there is to print for it in a table.
+func printResponse(result interface{}, err error, table func()) error {
+	if err != nil && !structuredOutput() {
+		return daemonError(err)
+	}
+	if perr := printResult(result, table); perr != nil {
+		return perr
+	}
+	return daemonError(err)
+}
+
+// The results below are what commands print with --output json or yaml.
+// Fields are only ever added to them, never renamed or dropped, and are
+// always present, zero if they don't apply, so scripts can rely on them.
+
+// errorResult is why a command's request to the daemon failed
+type errorResult struct {
+	// the gRPC code, e.g. "NotFound"; "Unknown" if the daemon wasn't reached
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorResult(err error) *errorResult {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(daemonError(err))
+	return &errorResult{Code: st.Code().String(), Message: st.Message()}
+}
+
+type dumpResult struct {
+	JobID          string  `json:"job_id"`
+	ContainerID    string  `json:"container_id"`
+	PID            int32   `json:"pid"`
+	Dir            string  `json:"dir"`
+	CheckpointPath string  `json:"checkpoint_path"`
+	CheckpointID   string  `json:"checkpoint_id"`
+	UploadID       string  `json:"upload_id"`
+	DedupRatio     float64 `json:"dedup_ratio"`
+	UploadedBytes  uint64  `json:"uploaded_bytes"`
+	Message        string  `json:"message"`
+	DurationMS     int64   `json:"duration_ms"`
+	// nil if the dump succeeded
+	Error *errorResult `json:"error"`
+}
+
+type restoreResult struct {
+	JobID          string `json:"job_id"`
+	ContainerID    string `json:"container_id"`
+	CheckpointID   string `json:"checkpoint_id"`
+	CheckpointPath string `json:"checkpoint_path"`
+	// of the restored process
+	PID        int32  `json:"pid"`
+	Message    string `json:"message"`
+	DurationMS int64  `json:"duration_ms"`
+	// nil if the restore succeeded
+	Error *errorResult `json:"error"`
+}
+
+type execResult struct {
+	JobID      string `json:"job_id"`
+	PID        int32  `json:"pid"`
+	Message    string `json:"message"`
+	DurationMS int64  `json:"duration_ms"`
+	// nil if the job started
+	Error *errorResult `json:"error"`
+}
+
+type psResult struct {
+	Jobs []jobResult `json:"jobs"`
+}
+
+type jobResult struct {
+	JobID  string `json:"job_id"`
+	PID    int32  `json:"pid"`
+	Status string `json:"status"`
+	// e.g. "CHECKPOINTED"
+	CheckpointState         string `json:"checkpoint_state"`
+	CheckpointFailureReason string `json:"checkpoint_failure_reason"`
+	// the latest local checkpoint
+	CheckpointPath string `json:"checkpoint_path"`
+	// remote checkpoints, oldest first
+	Checkpoints []checkpointResult `json:"checkpoints"`
+	// e.g. "running"
+	State   string            `json:"state"`
+	Runtime string            `json:"runtime"`
+	Tags    map[string]string `json:"tags"`
+	// when the current process started; zero if it isn't known
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	Running       bool      `json:"running"`
+	MemoryPercent float32   `json:"memory_percent"`
+	// null if the job has never been checkpointed
+	LastCheckpoint *jobEventResult `json:"last_checkpoint"`
+	// how often the job is checkpointed; always empty, as there's no way to
+	// schedule checkpoints yet
+	Schedule string `json:"schedule"`
+}
+
+type checkpointResult struct {
+	CheckpointID string    `json:"checkpoint_id"`
+	UploadID     string    `json:"upload_id"`
+	Time         time.Time `json:"time"`
+}
+
+// jobDetailResult is a job along with its history, oldest first
+type jobDetailResult struct {
+	jobResult
+	History []jobEventResult `json:"history"`
+}
+
+type jobEventResult struct {
+	// e.g. "checkpoint" or "restore_failed"
+	Event          string    `json:"event"`
+	Time           time.Time `json:"time"`
+	PID            int32     `json:"pid"`
+	CheckpointPath string    `json:"checkpoint_path"`
+	CheckpointID   string    `json:"checkpoint_id"`
+	SizeBytes      int64     `json:"size_bytes"`
+	DurationMS     int64     `json:"duration_ms"`
+	Error          string    `json:"error"`
+	// why the daemon checkpointed on its own, e.g. "instance_termination";
+	// empty for checkpoints that were asked for
+	Reason string `json:"reason"`
+}
+
+func newJobResult(job *task.Job, now time.Time) jobResult {
+	j := jobResult{
+		JobID:                   job.JobID,
+		PID:                     job.PID,
+		Status:                  job.Status,
+		CheckpointState:         job.CheckpointState.String(),
+		CheckpointFailureReason: job.CheckpointFailureReason,
+		CheckpointPath:          job.CheckpointPath,
+		Checkpoints:             []checkpointResult{},
+		State:                   jobState(job.Flag),
+		Runtime:                 job.Runtime,
+		Tags:                    job.Tags,
+		Running:                 job.Running,
+		MemoryPercent:           job.MemoryPercent,
+	}
+	if j.Tags == nil {
+		j.Tags = map[string]string{}
+	}
+	if job.StartedAt > 0 {
+		j.StartedAt = time.Unix(job.StartedAt, 0).UTC()
+		if job.Running {
+			j.UptimeSeconds = int64(now.Sub(j.StartedAt).Seconds())
+		}
+	}
+	if job.LastCheckpoint != nil {
+		last := newJobEventResult(job.LastCheckpoint)
+		j.LastCheckpoint = &last
+	}
+	for _, remote := range job.RemoteState {
+		j.Checkpoints = append(j.Checkpoints, checkpointResult{
+			CheckpointID: remote.CheckpointID,
+			UploadID:     remote.UploadID,
+			Time:         time.Unix(remote.Timestamp, 0).UTC(),
+		})
+	}
+	return j
+}
+
+func newJobEventResult(event *task.JobEvent) jobEventResult {
+	e := jobEventResult{
+		Event:          strings.ToLower(event.Type.String()),
+		PID:            event.PID,
+		CheckpointPath: event.CheckpointPath,
+		CheckpointID:   event.CheckpointID,
+		SizeBytes:      event.Size,
+		DurationMS:     event.DurationMS,
+		Error:          event.Error,
+	}
+	if event.Time > 0 {
+		e.Time = time.Unix(event.Time, 0).UTC()
+	}
+	if event.Reason != nil {
+		e.Reason = strings.ToLower(event.Reason.Reason.String())
+	}
+	return e
+}
+
+// jobState is how a job's flag reads to people, e.g. "running"
+func jobState(flag task.FlagEnum) string {
+	return strings.ToLower(strings.TrimPrefix(flag.String(), "JOB_"))
+}
+
+type runcGetResult struct {
+	ContainerName string `json:"container_name"`
+	RuncID        string `json:"runc_id"`
+	BundlePath    string `json:"bundle_path"`
+}
+
+type verifyResult struct {
+	Path      string    `json:"path"`
+	Signer    string    `json:"signer"`
+	Trusted   bool      `json:"trusted"`
+	JobID     string    `json:"job_id"`
+	Created   time.Time `json:"created"`
+	SizeBytes int64     `json:"size_bytes"`
+	Checksum  string    `json:"checksum"`
+	// empty if the checkpoint isn't encrypted
+	EncryptionKeyID string `json:"encryption_key_id"`
+}
+
+type keyResult struct {
+	PublicKey string `json:"public_key"`
+}
+
+type checkpointListResult struct {
+	// oldest first
+	Checkpoints []storedCheckpointResult `json:"checkpoints"`
+}
+
+type storedCheckpointResult struct {
+	CheckpointID string    `json:"checkpoint_id"`
+	JobID        string    `json:"job_id"`
+	Time         time.Time `json:"time"`
+	SizeBytes    int64     `json:"size_bytes"`
+	// empty if the store doesn't record one
+	Checksum string `json:"checksum"`
+}
+
+type explainResult struct {
+	Keys []explainedKey `json:"keys"`
+}
+
+type explainedKey struct {
+	Key string `json:"key"`
+	// as given to --set; credentials are redacted
+	Value string `json:"value"`
+	// "default", "system file", "user file", "server_overrides.json", "env"
+	// or "flag"
+	Layer string `json:"layer"`
+	// the file, variable or flag itself
+	Source string `json:"source"`
+}
+
+func newDumpResult(resp *task.DumpResp, err error, start time.Time) dumpResult {
+	result := dumpResult{DurationMS: since(start), Error: newErrorResult(err)}
+	if resp != nil {
+		result.PID = resp.PID
+		result.CheckpointPath = resp.CheckpointPath
+		result.CheckpointID = resp.CheckpointID
+		result.UploadID = resp.UploadID
+		result.DedupRatio = resp.DedupRatio
+		result.UploadedBytes = resp.UploadedBytes
+		result.Message = resp.Message
+	}
+	return result
+}
+
+func newRestoreResult(resp *task.RestoreResp, err error, start time.Time) restoreResult {
+	result := restoreResult{DurationMS: since(start), Error: newErrorResult(err)}
+	if resp != nil {
+		result.PID, result.Message = resp.NewPID, resp.Message
+	}
+	return result
+}
+
+func since(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cedana/cedana/api/services/task"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var psFilters []string
+var psWatch bool
+var psInterval time.Duration
+
+var psCmd = &cobra.Command{
+	Use:   "ps [job id]",
+	Short: "List managed jobs, or show one job with its checkpoint and restore history",
+	Args:  cobra.MaximumNArgs(1),
+	// watching runs until interrupted
+	PreRun:  cancelOnInterrupt,
+	Example: "cedana ps --filter state=running --filter tag=team=ml\ncedana ps -w\ncedana ps my-job",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listArgs, err := parseFilters(psFilters)
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 && len(psFilters) > 0 {
+			return fmt.Errorf("--filter is for listing jobs, not showing one")
+		}
+		if psInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		cli, err := NewCLI()
+		if err != nil {
+			return err
+		}
+		defer cli.cts.Close()
+
+		show := func(ctx context.Context) error {
+			if len(args) == 1 {
+				return showJob(ctx, cli, args[0])
+			}
+			return listJobs(ctx, cli, listArgs)
+		}
+
+		if !psWatch {
+			return show(cmd.Context())
+		}
+
+		ctx := cmd.Context()
+		for {
+			if output == outputTable {
+				// clear the screen so the table redraws in place
+				fmt.Fprint(stdout, "\033[H\033[2J")
+				fmt.Fprintf(stdout, "Every %s, Ctrl-C to stop\n\n", psInterval)
+			} else if output == outputYAML {
+				fmt.Fprintln(stdout, "---")
+			}
+			if err := show(ctx); err != nil {
+				// Ctrl-C is how watching stops, not an error
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(psInterval):
+			}
+		}
+	},
+}
+
+// parseFilters turns ps --filter flags into the jobs to ask the daemon for.
+// States can be given more than once to match any of them; every tag given
+// must match.
+func parseFilters(filters []string) (*task.ListJobsArgs, error) {
+	var args task.ListJobsArgs
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("bad filter %q, expected state=<state>, tag=<key>[=<value>] or runtime=<runtime>", filter)
+		}
+		switch key {
+		case "state":
+			args.States = append(args.States, value)
+		case "tag":
+			args.Tags = append(args.Tags, value)
+		case "runtime":
+			if args.Runtime != "" && args.Runtime != value {
+				return nil, fmt.Errorf("a job has only one runtime, got %s and %s", args.Runtime, value)
+			}
+			args.Runtime = value
+		default:
+			return nil, fmt.Errorf("unknown filter %q, expected one of state, tag, runtime", key)
+		}
+	}
+	return &args, nil
+}
+
+func listJobs(ctx context.Context, cli *CLI, args *task.ListJobsArgs) error {
+	resp, err := cli.cts.ListJobs(ctx, args)
+	if err != nil {
+		return daemonError(err)
+	}
+
+	now := time.Now()
+	result := psResult{Jobs: []jobResult{}}
+	for _, job := range resp.Jobs {
+		result.Jobs = append(result.Jobs, newJobResult(job, now))
+	}
+
+	return printResult(result, func() {
+		table := tablewriter.NewWriter(stdout)
+		table.SetHeader([]string{"Job", "PID", "State", "Uptime", "Memory", "Last Checkpoint", "Size", "Location", "Schedule"})
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+		for _, j := range result.Jobs {
+			row := []string{j.JobID, strconv.Itoa(int(j.PID)), j.State, "-", "-", "-", "-", "-", formatSchedule(j.Schedule)}
+			if j.Running {
+				row[3] = formatUptime(j.UptimeSeconds)
+				row[4] = fmt.Sprintf("%.1f%%", j.MemoryPercent)
+			}
+			if last := j.LastCheckpoint; last != nil {
+				row[5] = formatAgo(last.Time, now)
+				row[6] = formatBytes(last.SizeBytes)
+				row[7] = checkpointLocation(last)
+			}
+			table.Append(row)
+		}
+
+		table.Render()
+	})
+}
+
+func showJob(ctx context.Context, cli *CLI, jobID string) error {
+	resp, err := cli.cts.GetJob(ctx, &task.GetJobArgs{JobID: jobID})
+	if err != nil {
+		return daemonError(err)
+	}
+
+	now := time.Now()
+	result := jobDetailResult{jobResult: newJobResult(resp.Job, now), History: []jobEventResult{}}
+	for _, event := range resp.History {
+		result.History = append(result.History, newJobEventResult(event))
+	}
+
+	return printResult(result, func() {
+		j := result.jobResult
+		state := j.State
+		if j.CheckpointState == task.CheckpointState_CHECKPOINT_FAILED.String() {
+			state += fmt.Sprintf(" (last checkpoint failed: %s)", j.CheckpointFailureReason)
+		}
+		var tags []string
+		for key, value := range j.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+
+		lines := [][2]string{
+			{"Job", j.JobID},
+			{"PID", strconv.Itoa(int(j.PID))},
+			{"State", state},
+			{"Runtime", j.Runtime},
+			{"Tags", strings.Join(tags, ", ")},
+			{"Schedule", formatSchedule(j.Schedule)},
+		}
+		if j.Running {
+			lines = append(lines,
+				[2]string{"Uptime", formatUptime(j.UptimeSeconds)},
+				[2]string{"Memory", fmt.Sprintf("%.1f%%", j.MemoryPercent)},
+			)
+		} else {
+			lines = append(lines, [2]string{"Uptime", "not running"})
+		}
+		if last := j.LastCheckpoint; last != nil {
+			lines = append(lines, [2]string{"Last Checkpoint", fmt.Sprintf("%s, %s at %s",
+				formatAgo(last.Time, now), formatBytes(last.SizeBytes), checkpointLocation(last))})
+		}
+		for _, line := range lines {
+			fmt.Fprintf(stdout, "%-16s %s\n", line[0]+":", line[1])
+		}
+
+		fmt.Fprintln(stdout, "\nHistory:")
+		table := tablewriter.NewWriter(stdout)
+		table.SetHeader([]string{"Time", "Event", "PID", "Location", "Size", "Duration", "Error"})
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, e := range result.History {
+			row := []string{e.Time.Local().Format("2006-01-02 15:04:05"), e.Event, strconv.Itoa(int(e.PID)), "-", "-", "-", e.Error}
+			if e.Reason != "" {
+				row[1] += " (" + strings.ReplaceAll(e.Reason, "_", " ") + ")"
+			}
+			if e.CheckpointPath != "" || e.CheckpointID != "" {
+				row[3] = checkpointLocation(&e)
+			}
+			if e.SizeBytes > 0 {
+				row[4] = formatBytes(e.SizeBytes)
+			}
+			if e.DurationMS > 0 {
+				row[5] = (time.Duration(e.DurationMS) * time.Millisecond).String()
+			}
+			table.Append(row)
+		}
+		table.Render()
+	})
+}
+
+// checkpointLocation is where a checkpoint is: its archive on local disk, or
+// its ID in the store if it was pushed
+func checkpointLocation(e *jobEventResult) string {
+	if e.CheckpointID != "" {
+		return "store:" + e.CheckpointID
+	}
+	if e.CheckpointPath != "" {
+		return e.CheckpointPath
+	}
+	return "-"
+}
+
+func formatUptime(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", seconds)
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%ds", seconds/60, seconds%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", seconds/3600, seconds%3600/60)
+	}
+	return fmt.Sprintf("%dd%dh", seconds/86400, seconds%86400/3600)
+}
+
+func formatAgo(t, now time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return formatUptime(int64(now.Sub(t).Seconds())) + " ago"
+}
+
+// formatBytes is n bytes in binary units, e.g. 1.5 GiB
+func formatBytes(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatSchedule is "-" for a job that's only checkpointed on demand
+func formatSchedule(schedule string) string {
+	if schedule == "" {
+		return "-"
+	}
+	return schedule
+}
+
+func init() {
+	psCmd.Flags().StringArrayVar(&psFilters, "filter", nil, "only list jobs matching state=<state>, tag=<key>[=<value>] or runtime=<process|runc|containerd>; can be repeated")
+	psCmd.Flags().BoolVarP(&psWatch, "watch", "w", false, "keep refreshing until interrupted")
+	psCmd.Flags().DurationVar(&psInterval, "interval", 2*time.Second, "how often --watch refreshes")
+
+	rootCmd.AddCommand(psCmd)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"github.com/cedana/cedana/utils"
 	"github.com/spf13/cobra"
 )
 
+var configFile string
+var configSet []string
+
 var (
 	// Used for flags.
 	rootCmd = &cobra.Command{
@@ -23,10 +27,22 @@ var (
 	}
 )
 
+// Execute runs the command line; exit with ExitCode of the error it returns.
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
-	cobra.OnInitialize()
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file to use instead of ~/.cedana/client_config.json")
+	rootCmd.PersistentFlags().StringArrayVar(&configSet, "set", nil, "override a config key, e.g. --set client.compression=zstd")
+	rootCmd.PersistentFlags().VarP(&output, "output", "o", "how results are printed: table, json or yaml")
+	// usage is shown for bad flags or arguments, not for a command that
+	// failed once it ran; commands with hooks of their own still run this one
+	cobra.EnableTraverseRunHooks = true
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+	}
+	cobra.OnInitialize(func() {
+		utils.SetConfigOptions(utils.ConfigOptions{UserFile: configFile, Set: configSet})
+	})
 }
@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cedana/cedana/api/services/task"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc/status"
 )
 
 var containerName string
@@ -32,16 +32,16 @@ var runcGetRuncIdByName = &cobra.Command{
 			ContainerName: containerName,
 		}
 
-		resp, err := cli.cts.GetRuncIdByName(runcArgs)
+		resp, err := cli.cts.GetRuncIdByName(cmd.Context(), runcArgs)
+		cli.cts.Close()
 		if err != nil {
-			return err
+			return daemonError(err)
 		}
 
-		cli.logger.Info().Msgf("Response: %v", resp)
-
-		cli.cts.Close()
-
-		return nil
+		result := runcGetResult{ContainerName: containerName, RuncID: resp.RuncContainerName, BundlePath: resp.RuncBundlePath}
+		return printResult(result, func() {
+			cli.logger.Info().Msgf("Response: %v", resp)
+		})
 	},
 }
 
@@ -84,22 +84,17 @@ var runcDumpCmd = &cobra.Command{
 			Type: task.RuncDumpArgs_LOCAL,
 		}
 
-		resp, err := cli.cts.CheckpointRunc(&dumpArgs)
-
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v, %v", st.Message(), st.Code())
-			} else {
-				cli.logger.Error().Msgf("Checkpoint task failed: %v", err)
-			}
-		}
-
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.CheckpointRunc(cmd.Context(), &dumpArgs)
 		cli.cts.Close()
 
-		return nil
+		result := dumpResult{ContainerID: containerId, Dir: dir, DurationMS: since(start), Error: newErrorResult(err)}
+		if resp != nil {
+			result.CheckpointID, result.Message = resp.CheckpointId, resp.Message
+		}
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 var runcRestoreCmd = &cobra.Command{
@@ -129,21 +124,23 @@ var runcRestoreCmd = &cobra.Command{
 			CheckpointId: checkpointId,
 		}
 
-		resp, err := cli.cts.RuncRestore(restoreArgs)
-		if err != nil {
-			st, ok := status.FromError(err)
-			if ok {
-				cli.logger.Error().Msgf("Restore task failed: %v, %v", st.Message(), st.Code())
-			} else {
-				cli.logger.Error().Msgf("Restore task failed: %v", err)
-			}
-		}
-
-		cli.logger.Info().Msgf("Response: %v", resp.Message)
-
+		start := time.Now()
+		resp, err := cli.cts.RuncRestore(cmd.Context(), restoreArgs)
 		cli.cts.Close()
 
-		return nil
+		result := restoreResult{
+			ContainerID:    containerId,
+			CheckpointID:   checkpointId,
+			CheckpointPath: dir,
+			DurationMS:     since(start),
+			Error:          newErrorResult(err),
+		}
+		if resp != nil {
+			result.Message = resp.Message
+		}
+		return printResponse(result, err, func() {
+			cli.logger.Info().Msgf("Response: %v", resp.Message)
+		})
 	},
 }
 
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/cedana/cedana/cmd"
 )
 
@@ -12,5 +14,7 @@ var (
 
 func main() {
 	cmd.SetVersionInfo(version, commit, date)
-	cmd.Execute()
+	if err := cmd.Execute(); err != nil {
+		os.Exit(cmd.ExitCode(err))
+	}
 }
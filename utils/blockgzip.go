@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Block gzip is how checkpoints are gzipped: a series of independent gzip
+// members of up to gzipBlockSize input bytes each, which together are still a
+// plain gzip stream to any other reader. Every member records its compressed
+// size in a header extra field, as BGZF does, so blocks can be decompressed in
+// parallel as well as compressed in parallel. Blocks are counted from the
+// start of each frameWriter chunk, so the same chunk always gzips the same.
+const (
+	gzipBlockSize = 1 << 20
+	// header, extra length, then the 'C' 'D' subfield holding the member's
+	// size as a little endian uint32
+	gzipSizeOffset = 16
+	gzipHeaderLen  = 20
+	// no honest member comes near this, even of incompressible data
+	maxGzipMember = 2 * gzipBlockSize
+)
+
+var errCorruptBlockGzip = errors.New("corrupt gzip checkpoint archive")
+
+func isGzipBlock(head []byte) bool {
+	return len(head) >= gzipHeaderLen && head[0] == 0x1f && head[1] == 0x8b && head[3]&4 != 0 &&
+		binary.LittleEndian.Uint16(head[10:]) == 8 && head[12] == 'C' && head[13] == 'D' &&
+		binary.LittleEndian.Uint16(head[14:]) == 4
+}
+
+type gzipBlock struct {
+	data []byte
+	err  error
+}
+
+func gzipMember(data []byte, level int) ([]byte, error) {
+	var out bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&out, level)
+	if err != nil {
+		return nil, err
+	}
+	zw.Header.Extra = []byte{'C', 'D', 4, 0, 0, 0, 0, 0}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	member := out.Bytes()
+	binary.LittleEndian.PutUint32(member[gzipSizeOffset:], uint32(len(member)))
+	return member, nil
+}
+
+func gunzipMember(member []byte) gzipBlock {
+	zr, err := gzip.NewReader(bytes.NewReader(member))
+	if err != nil {
+		return gzipBlock{err: err}
+	}
+	zr.Multistream(false)
+	// the trailer says how big the block is
+	size := binary.LittleEndian.Uint32(member[len(member)-4:])
+	if size > gzipBlockSize {
+		return gzipBlock{err: errCorruptBlockGzip}
+	}
+	// and no more than that comes out, whatever the member holds
+	out := bytes.NewBuffer(make([]byte, 0, size))
+	n, err := io.Copy(out, io.LimitReader(zr, int64(size)+1))
+	if err != nil {
+		return gzipBlock{err: err}
+	}
+	if n > int64(size) {
+		return gzipBlock{err: errCorruptBlockGzip}
+	}
+	return gzipBlock{data: out.Bytes()}
+}
+
+// gzipFrame compresses a chunk as the members of its blocks; an empty chunk
+// is still one member, so an empty stream is valid gzip.
+func gzipFrame(chunk []byte, level int) ([]byte, error) {
+	var frame []byte
+	for len(chunk) > 0 || frame == nil {
+		block := chunk
+		if len(block) > gzipBlockSize {
+			block = block[:gzipBlockSize]
+		}
+		member, err := gzipMember(block, level)
+		if err != nil {
+			return nil, err
+		}
+		frame = append(frame, member...)
+		chunk = chunk[len(block):]
+	}
+	return frame, nil
+}
+
+// newGzipReader decompresses block gzip on up to threads cores, and any other
+// gzip stream as it comes.
+func newGzipReader(r io.Reader, threads int) (io.ReadCloser, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	if head, _ := br.Peek(gzipHeaderLen); !isGzipBlock(head) {
+		return gzip.NewReader(br)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(gunzipBlocks(br, pw, threads))
+	}()
+	return pr, nil
+}
+
+// gunzipBlocks decompresses the members of br into w in order, up to threads
+// at once. It stops once w is closed.
+func gunzipBlocks(br *bufio.Reader, w io.Writer, threads int) error {
+	var pending []chan gzipBlock
+	writeOldest := func() error {
+		block := <-pending[0]
+		pending = pending[1:]
+		if block.err != nil {
+			return block.err
+		}
+		_, err := w.Write(block.data)
+		return err
+	}
+
+	for {
+		head, err := br.Peek(gzipHeaderLen)
+		if err == io.EOF && len(head) == 0 {
+			break
+		}
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		size := binary.LittleEndian.Uint32(head[gzipSizeOffset:])
+		if !isGzipBlock(head) || size < gzipHeaderLen || size > maxGzipMember {
+			return errCorruptBlockGzip
+		}
+		member := make([]byte, size)
+		if _, err := io.ReadFull(br, member); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		done := make(chan gzipBlock, 1)
+		go func() { done <- gunzipMember(member) }()
+		pending = append(pending, done)
+		if len(pending) >= threads {
+			if err := writeOldest(); err != nil {
+				return err
+			}
+		}
+	}
+	for len(pending) > 0 {
+		if err := writeOldest(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"io"
+)
+
+// Content-defined chunking splits a checkpoint wherever a rolling hash of the
+// last 64 bytes hits a pattern, rather than at fixed offsets, so bytes
+// inserted or removed early in an image only change the chunks around the
+// edit and successive checkpoints of a job share most of their chunks.
+const (
+	minChunkSize = 256 << 10
+	maxChunkSize = 4 << 20
+	// cut when the low 20 bits of the hash are zero, for ~1MiB chunks on
+	// average past the minimum
+	chunkMask = 1<<20 - 1
+)
+
+// gear maps each byte to a fixed pseudo-random value for the rolling hash.
+// It is part of the chunk format: changing it changes every boundary.
+var gear = func() (table [256]uint64) {
+	// splitmix64
+	x := uint64(0x6365_6461_6e61_6364)
+	for i := range table {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// Chunker reads a stream and returns it as content-defined chunks of
+// minChunkSize to maxChunkSize bytes; only the last chunk may be shorter.
+type Chunker struct {
+	r   io.Reader
+	buf []byte
+	n   int
+	eof bool
+}
+
+func NewChunker(r io.Reader) *Chunker {
+	return &Chunker{r: r, buf: make([]byte, maxChunkSize)}
+}
+
+// Next returns the next chunk, or io.EOF after the last. The chunk is a fresh
+// slice the caller may keep.
+func (c *Chunker) Next() ([]byte, error) {
+	if !c.eof && c.n < len(c.buf) {
+		n, err := io.ReadFull(c.r, c.buf[c.n:])
+		c.n += n
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+
+	cut := chunkBoundary(c.buf[:c.n])
+	chunk := make([]byte, cut)
+	copy(chunk, c.buf[:cut])
+	c.n = copy(c.buf, c.buf[cut:c.n])
+	return chunk, nil
+}
+
+// chunkBoundary returns the length of the first chunk in data
+func chunkBoundary(data []byte) int {
+	if len(data) <= minChunkSize {
+		return len(data)
+	}
+
+	// the hash only depends on the last 64 bytes, so start just before the
+	// earliest allowed cut
+	var h uint64
+	for i := minChunkSize - 64; i < len(data); i++ {
+		h = h<<1 + gear[data[i]]
+		if i >= minChunkSize && h&chunkMask == 0 {
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// frameWriter cuts the stream written to it into the chunks a Chunker would,
+// and writes frame(chunk) for each to w in order, framing up to threads
+// chunks at once. Framing chunk by chunk keeps the output in step with the
+// input: a run of input that's unchanged since the last checkpoint comes out
+// as the same run of frames, whatever is compressed or encrypted around it,
+// so the frames dedup too. Each frame is written to w in a single Write.
+type frameWriter struct {
+	w       io.Writer
+	frame   func(chunk []byte) ([]byte, error)
+	threads int
+	// an empty stream is framed as one empty chunk
+	frameEmpty bool
+	buf        []byte
+	// chunks being framed, oldest first
+	pending []chan framed
+	wrote   bool
+	err     error
+}
+
+type framed struct {
+	data []byte
+	err  error
+}
+
+func newFrameWriter(w io.Writer, threads int, frame func(chunk []byte) ([]byte, error)) *frameWriter {
+	return &frameWriter{w: w, frame: frame, threads: threads, buf: make([]byte, 0, maxChunkSize)}
+}
+
+func (f *frameWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if f.err != nil {
+			return n, f.err
+		}
+		k := copy(f.buf[len(f.buf):cap(f.buf)], p)
+		f.buf = f.buf[:len(f.buf)+k]
+		p = p[k:]
+		n += k
+		if len(f.buf) == cap(f.buf) {
+			f.cut()
+		}
+	}
+	return n, f.err
+}
+
+// cut frames the first chunk in the buffer
+func (f *frameWriter) cut() {
+	n := chunkBoundary(f.buf)
+	chunk := make([]byte, n)
+	copy(chunk, f.buf)
+	f.buf = f.buf[:copy(f.buf, f.buf[n:])]
+	f.wrote = true
+
+	done := make(chan framed, 1)
+	go func() {
+		data, err := f.frame(chunk)
+		done <- framed{data: data, err: err}
+	}()
+	f.pending = append(f.pending, done)
+	if len(f.pending) >= f.threads {
+		f.writeOldest()
+	}
+}
+
+func (f *frameWriter) writeOldest() {
+	frame := <-f.pending[0]
+	f.pending = f.pending[1:]
+	if f.err != nil {
+		return
+	}
+	if f.err = frame.err; f.err == nil {
+		_, f.err = f.w.Write(frame.data)
+	}
+}
+
+// Close frames what's left and waits for every frame to be written. It leaves
+// w open.
+func (f *frameWriter) Close() error {
+	for len(f.buf) > 0 || (f.frameEmpty && !f.wrote) {
+		f.cut()
+	}
+	for len(f.pending) > 0 {
+		f.writeOldest()
+	}
+	return f.err
+}
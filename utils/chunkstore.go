@@ -0,0 +1,509 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	manifestFormat = "cedana-manifest/v1"
+	// manifests are never bigger than this, so a stored checkpoint that is
+	// can be told to be a whole tarball without downloading it
+	maxManifestSize = 64 << 20
+
+	chunkConcurrency = 4
+	// chunks written or reused this recently are never collected, since a
+	// push uploads its chunks well before the manifest that refers to them
+	chunkGCGrace = 24 * time.Hour
+)
+
+// Manifest describes a chunked checkpoint: the image is the concatenation of
+// its chunks, in order.
+type Manifest struct {
+	// always first, so a manifest can be told from a tarball by its prefix
+	Format  string    `json:"format"`
+	JobID   string    `json:"job_id"`
+	Created time.Time `json:"created"`
+	// size and sha256 of the whole image
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+	// set when the image is an encrypted archive
+	Encryption *ManifestEncryption `json:"encryption,omitempty"`
+	Chunks     []ChunkRef          `json:"chunks"`
+	// the producing node's signature, see Sign
+	Signature *ManifestSignature `json:"signature,omitempty"`
+}
+
+type ManifestEncryption struct {
+	Algorithm string `json:"algorithm"`
+	// the job's key, as named by the key provider
+	KeyID string `json:"key_id"`
+}
+
+type ChunkRef struct {
+	// hex sha256 of the chunk
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+	// set when the store fetches chunks by its own ID rather than the digest
+	ID string `json:"id,omitempty"`
+}
+
+var errNotManifest = errors.New("not a checkpoint manifest")
+
+// readManifest parses path as a manifest, or returns errNotManifest if it is
+// something else, e.g. a checkpoint pushed whole before chunking.
+func readManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	prefix := []byte(`{"format":"` + manifestFormat + `"`)
+	head := make([]byte, len(prefix))
+	if _, err := io.ReadFull(f, head); err != nil || !bytes.Equal(head, prefix) {
+		return nil, errNotManifest
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("corrupt checkpoint manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// ChunkStore is implemented by stores that can hold content-addressed chunks
+// natively, which is what ChunkedStore needs of the store it wraps.
+type ChunkStore interface {
+	// HasChunk reports whether the chunk with this digest is stored, and the
+	// ID to fetch it by. A chunk that is found counts as just written, so it
+	// isn't collected before the manifest reusing it is pushed.
+	HasChunk(ctx context.Context, digest string) (string, bool, error)
+	PutChunk(ctx context.Context, digest string, data []byte) (string, error)
+	GetChunk(ctx context.Context, ref ChunkRef) (io.ReadCloser, error)
+	ListChunks(ctx context.Context) ([]ChunkInfo, error)
+	DeleteChunk(ctx context.Context, digest string) error
+}
+
+type ChunkInfo struct {
+	Digest string
+	// when the chunk was last written, or found by HasChunk
+	ModTime time.Time
+}
+
+// ChunkedStore deduplicates checkpoints pushed to another store. Each image is
+// split into content-defined chunks, only chunks the store lacks are uploaded,
+// and the checkpoint itself is a small manifest listing its chunks. Restores
+// reassemble the image from the chunks into the cache.
+//
+// Deleting a checkpoint deletes its manifest, then every chunk that no
+// remaining manifest refers to, see collectChunks.
+type ChunkedStore struct {
+	store  Store
+	chunks ChunkStore
+	cache  *CheckpointCache
+	// when set, only checkpoints signed by one of these are returned
+	trusted TrustedKeys
+}
+
+// NewChunkedStore wraps store, which must be a ChunkStore too.
+func NewChunkedStore(store Store, cache *CheckpointCache) (*ChunkedStore, error) {
+	chunks, ok := store.(ChunkStore)
+	if !ok {
+		return nil, fmt.Errorf("%w: chunks", ErrNotSupported)
+	}
+	return &ChunkedStore{store: store, chunks: chunks, cache: cache}, nil
+}
+
+func (cs *ChunkedStore) PushCheckpoint(ctx context.Context, jobID, checkpointPath string) (*CheckpointMeta, error) {
+	f, err := os.Open(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return cs.push(ctx, jobID, f, func(manifest *Manifest) error {
+		adoptSignature(manifest, checkpointPath+manifestSuffix)
+		return nil
+	})
+}
+
+// PushCheckpointStream pushes the archive read from r as it's written, signing
+// its manifest with key unless key is nil. Chunks are uploaded while the rest
+// of the archive is still being produced.
+func (cs *ChunkedStore) PushCheckpointStream(ctx context.Context, jobID string, r io.Reader, key ed25519.PrivateKey) (*CheckpointMeta, error) {
+	return cs.push(ctx, jobID, r, func(manifest *Manifest) error {
+		if key == nil {
+			return nil
+		}
+		return manifest.Sign(key)
+	})
+}
+
+// push chunks and uploads the archive read from r, then pushes its manifest
+// once finish has had a chance to sign it.
+func (cs *ChunkedStore) push(ctx context.Context, jobID string, r io.Reader, finish func(*Manifest) error) (*CheckpointMeta, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	manifest := &Manifest{Format: manifestFormat, JobID: jobID, Created: time.Now().UTC()}
+	// the header is read off the front and put back, since r may not seek
+	var head bytes.Buffer
+	if header, err := ReadEncryptionHeader(io.TeeReader(r, &head)); err == nil {
+		manifest.Encryption = &ManifestEncryption{Algorithm: header.Algorithm, KeyID: header.KeyID}
+	} else if !errors.Is(err, ErrNotEncrypted) {
+		return nil, err
+	}
+	r = io.MultiReader(&head, r)
+
+	type chunk struct {
+		digest string
+		data   []byte
+	}
+	queue := make(chan chunk, chunkConcurrency)
+
+	var mu sync.Mutex
+	ids := map[string]string{}
+	var uploaded int64
+	var errOnce sync.Once
+	var pushErr error
+
+	var wg sync.WaitGroup
+	for i := 0; i < chunkConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range queue {
+				id, sent, err := cs.putChunk(ctx, c.digest, c.data)
+				if err != nil {
+					errOnce.Do(func() {
+						pushErr = fmt.Errorf("chunk %s: %w", c.digest, err)
+						cancel()
+					})
+					continue
+				}
+				mu.Lock()
+				ids[c.digest] = id
+				if sent {
+					uploaded += int64(len(c.data))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	image := sha256.New()
+	chunker := NewChunker(io.TeeReader(&ctxReader{ctx: ctx, r: r}, image))
+	// repeats within the image are only sent once
+	queued := map[string]bool{}
+	for {
+		data, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errOnce.Do(func() { pushErr = err })
+			break
+		}
+
+		sum := sha256.Sum256(data)
+		digest := hex.EncodeToString(sum[:])
+		manifest.Chunks = append(manifest.Chunks, ChunkRef{Digest: digest, Size: int64(len(data))})
+		manifest.Size += int64(len(data))
+		if queued[digest] {
+			continue
+		}
+		queued[digest] = true
+
+		select {
+		case queue <- chunk{digest: digest, data: data}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if pushErr != nil {
+		return nil, pushErr
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	for i := range manifest.Chunks {
+		manifest.Chunks[i].ID = ids[manifest.Chunks[i].Digest]
+	}
+	manifest.Checksum = "sha256:" + hex.EncodeToString(image.Sum(nil))
+	if err := finish(manifest); err != nil {
+		return nil, err
+	}
+
+	meta, err := cs.pushManifest(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	meta.Size = uint64(manifest.Size)
+	meta.Checksum = manifest.Checksum
+	meta.Chunks = len(manifest.Chunks)
+	meta.UploadedBytes = uint64(uploaded)
+	if manifest.Size > 0 {
+		meta.DedupRatio = 1 - float64(uploaded)/float64(manifest.Size)
+	}
+	return meta, nil
+}
+
+// adoptSignature carries the signature of the archive's own manifest over to
+// the chunked one, which it still covers so long as they describe the same
+// image.
+func adoptSignature(manifest *Manifest, signedPath string) {
+	signed, err := readManifest(signedPath)
+	if err != nil || signed.Signature == nil {
+		return
+	}
+	candidate := *manifest
+	candidate.Created = signed.Created
+	candidate.Signature = signed.Signature
+	if _, err := candidate.Signer(); err == nil {
+		*manifest = candidate
+	}
+}
+
+// putChunk uploads a chunk unless the store already has it
+func (cs *ChunkedStore) putChunk(ctx context.Context, digest string, data []byte) (string, bool, error) {
+	id, ok, err := cs.chunks.HasChunk(ctx, digest)
+	if err != nil {
+		return "", false, err
+	}
+	if ok {
+		return id, false, nil
+	}
+	id, err = cs.chunks.PutChunk(ctx, digest, data)
+	return id, err == nil, err
+}
+
+func (cs *ChunkedStore) pushManifest(ctx context.Context, manifest *Manifest) (*CheckpointMeta, error) {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("checkpoint has too many chunks (%d) for one manifest", len(manifest.Chunks))
+	}
+
+	tmp, err := os.CreateTemp("", "cedana-manifest-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	return cs.store.PushCheckpoint(ctx, manifest.JobID, tmp.Name())
+}
+
+// GetCheckpoint returns the reassembled image of a chunked checkpoint, or the
+// tarball itself for one pushed whole. Either way a signed manifest is left
+// beside it, for restores to verify the image against as they would a local
+// archive.
+func (cs *ChunkedStore) GetCheckpoint(ctx context.Context, cid string) (*string, func(), error) {
+	path, release, err := cs.store.GetCheckpoint(ctx, cid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest, err := readManifest(*path)
+	if errors.Is(err, errNotManifest) {
+		// pushed whole, with its signed manifest beside it if it has one
+		return path, release, nil
+	}
+	release()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cs.trusted != nil {
+		if err := manifest.Verify(cs.trusted); err != nil {
+			return nil, nil, fmt.Errorf("checkpoint %s: %w", cid, err)
+		}
+	}
+
+	image, release, err := cs.cache.Get(ctx, cid+".image", func(ctx context.Context, partial string) error {
+		if manifest.Signature != nil {
+			if err := writeManifest(partial, manifest); err != nil {
+				return err
+			}
+		}
+		return cs.assemble(ctx, manifest, partial)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &image, release, nil
+}
+
+// assemble writes the image described by manifest to path, fetching chunks
+// in parallel and checking each against its digest.
+func (cs *ChunkedStore) assemble(ctx context.Context, manifest *Manifest, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(manifest.Size); err != nil {
+		return err
+	}
+
+	type part struct {
+		ref ChunkRef
+		off int64
+	}
+	queue := make(chan part, len(manifest.Chunks))
+	var off int64
+	for _, ref := range manifest.Chunks {
+		queue <- part{ref: ref, off: off}
+		off += ref.Size
+	}
+	close(queue)
+	if off != manifest.Size {
+		return fmt.Errorf("corrupt checkpoint manifest: chunks add up to %d bytes, expected %d", off, manifest.Size)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var fetchErr error
+	for i := 0; i < chunkConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range queue {
+				err := withRetries(ctx, maxPartAttempts, func() error {
+					return cs.fetchChunk(ctx, f, p.ref, p.off)
+				})
+				if err != nil {
+					errOnce.Do(func() {
+						fetchErr = fmt.Errorf("chunk %s: %w", p.ref.Digest, err)
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetchErr != nil {
+		return fetchErr
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return verifyChecksum(ctx, path, manifest.Checksum)
+}
+
+func (cs *ChunkedStore) fetchChunk(ctx context.Context, f *os.File, ref ChunkRef, off int64) error {
+	body, err := cs.chunks.GetChunk(ctx, ref)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(&offsetWriter{f: f, off: off}, hash), io.LimitReader(body, ref.Size+1))
+	if err != nil {
+		return err
+	}
+	if n != ref.Size || hex.EncodeToString(hash.Sum(nil)) != ref.Digest {
+		return fmt.Errorf("chunk failed verification")
+	}
+	return nil
+}
+
+func (cs *ChunkedStore) ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) {
+	return cs.store.ListCheckpoints(ctx)
+}
+
+func (cs *ChunkedStore) DeleteCheckpoint(ctx context.Context, cid string) error {
+	if err := cs.store.DeleteCheckpoint(ctx, cid); err != nil {
+		return err
+	}
+	if err := cs.collectChunks(ctx); err != nil {
+		return fmt.Errorf("checkpoint %s deleted, but collecting its chunks failed: %w", cid, err)
+	}
+	return nil
+}
+
+// collectChunks deletes the chunks no manifest in the store refers to, except
+// for those written or reused within chunkGCGrace. A manifest that can't be
+// read stops collection, as its chunks can't be told apart.
+func (cs *ChunkedStore) collectChunks(ctx context.Context) error {
+	list, err := cs.store.ListCheckpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	referenced := map[string]bool{}
+	for _, meta := range *list {
+		// pushed whole, so it has no chunks
+		if meta.Size > maxManifestSize {
+			continue
+		}
+		path, release, err := cs.store.GetCheckpoint(ctx, meta.ID)
+		if err != nil {
+			return err
+		}
+		manifest, err := readManifest(*path)
+		release()
+		if errors.Is(err, errNotManifest) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("checkpoint %s: %w", meta.ID, err)
+		}
+		for _, ref := range manifest.Chunks {
+			referenced[ref.Digest] = true
+		}
+	}
+
+	chunks, err := cs.chunks.ListChunks(ctx)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-chunkGCGrace)
+	for _, chunk := range chunks {
+		if referenced[chunk.Digest] || chunk.ModTime.After(cutoff) {
+			continue
+		}
+		if err := cs.chunks.DeleteChunk(ctx, chunk.Digest); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("chunk %s: %w", chunk.Digest, err)
+		}
+	}
+	return nil
+}
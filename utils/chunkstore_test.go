@@ -0,0 +1,418 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func randomBytes(seed int64, n int) []byte {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(data)
+	return data
+}
+
+func chunkAll(t *testing.T, data []byte) [][]byte {
+	var chunks [][]byte
+	c := NewChunker(bytes.NewReader(data))
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			return chunks
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	data := randomBytes(1, 16<<20)
+	chunks := chunkAll(t, data)
+
+	if got := bytes.Join(chunks, nil); !bytes.Equal(got, data) {
+		t.Fatal("chunks don't add back up to the input")
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > maxChunkSize || (i < len(chunks)-1 && len(chunk) < minChunkSize) {
+			t.Errorf("chunk %d is %d bytes", i, len(chunk))
+		}
+	}
+
+	// an insertion near the start only disturbs the chunks around it
+	shifted := append(append(append([]byte{}, data[:1000]...), "inserted"...), data[1000:]...)
+	seen := map[string]bool{}
+	for _, chunk := range chunks {
+		seen[string(chunk)] = true
+	}
+	shared := 0
+	for _, chunk := range chunkAll(t, shifted) {
+		if seen[string(chunk)] {
+			shared++
+		}
+	}
+	if shared < len(chunks)-2 {
+		t.Errorf("only %d of %d chunks survived an insertion", shared, len(chunks))
+	}
+
+	if empty := chunkAll(t, nil); len(empty) != 0 {
+		t.Errorf("expected no chunks for empty input, got %d", len(empty))
+	}
+}
+
+func writeFile(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestChunkedStore(t *testing.T, store Store) *ChunkedStore {
+	cache, err := NewCheckpointCache(StoreConfig{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunked, err := NewChunkedStore(store, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chunked
+}
+
+func TestChunkedStore(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newTestChunkedStore(t, local)
+
+	first := randomBytes(2, 12<<20)
+	meta, err := store.PushCheckpoint(ctx, "job-a", writeFile(t, first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.DedupRatio != 0 || meta.UploadedBytes != uint64(len(first)) || meta.Size != uint64(len(first)) {
+		t.Errorf("unexpected first push %+v", meta)
+	}
+
+	// the next checkpoint differs by a few pages in the middle
+	second := append([]byte{}, first...)
+	copy(second[6<<20:], randomBytes(3, 8192))
+	meta2, err := store.PushCheckpoint(ctx, "job-a", writeFile(t, second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta2.DedupRatio < 0.5 || meta2.UploadedBytes >= uint64(len(second))/2 {
+		t.Errorf("expected most of the second checkpoint to be deduplicated, got ratio %.2f, %d bytes sent", meta2.DedupRatio, meta2.UploadedBytes)
+	}
+
+	for _, c := range []struct {
+		meta *CheckpointMeta
+		data []byte
+	}{{meta, first}, {meta2, second}} {
+		path, _, err := store.GetCheckpoint(ctx, c.meta.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(*path)
+		if err != nil || !bytes.Equal(got, c.data) {
+			t.Fatalf("reassembled checkpoint %s differs (err %v)", c.meta.ID, err)
+		}
+	}
+
+	// a damaged chunk is caught on reassembly
+	manifestPath, _, _ := local.GetCheckpoint(ctx, meta.ID)
+	manifest, err := readManifest(*manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(local.chunkPath(manifest.Chunks[0].Digest), []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(store.cache.dir, meta.ID+".image.tar"))
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); err == nil {
+		t.Error("expected a corrupt chunk to fail reassembly")
+	}
+}
+
+// compressed and deterministically encrypted archives dedup as well as plain
+// ones
+func TestChunkedStore_DedupsArchives(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	image := filepath.Join(src, "pages-1.img")
+	pages := randomBytes(4, 24<<20)
+
+	for _, tc := range []struct {
+		compression string
+		encrypted   bool
+	}{{"none", false}, {"gzip", false}, {"lz4", false}, {"zstd", false}, {"none", true}, {"zstd", true}} {
+		name := fmt.Sprintf("%s, encrypted %v", tc.compression, tc.encrypted)
+		local, err := NewLocalStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := newTestChunkedStore(t, local)
+		compression, _ := ParseCompression(tc.compression)
+		opts := []TarOption{WithCompression(compression)}
+		if tc.encrypted {
+			opts = append(opts, WithEncryption(testKey, "test-key"), WithDeterministicEncryption())
+		}
+
+		var meta *CheckpointMeta
+		for i, edit := range []int64{0, 5} {
+			edited := append([]byte{}, pages...)
+			copy(edited[12<<20:], randomBytes(edit, 8192))
+			if err := os.WriteFile(image, edited, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			archive := filepath.Join(t.TempDir(), fmt.Sprintf("checkpoint-%d.tar", i))
+			if err := TarFolder(ctx, src, archive, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if meta, err = store.PushCheckpoint(ctx, "job-a", archive); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if meta.DedupRatio < 0.5 {
+			t.Errorf("%s: expected most of the second checkpoint to be deduplicated, got ratio %.2f", name, meta.DedupRatio)
+		}
+	}
+}
+
+func TestChunkedStore_WholeCheckpoints(t *testing.T) {
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := local.PushCheckpoint(context.Background(), "job-a", writeFile(t, []byte("a plain tarball")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// checkpoints pushed before chunking still restore
+	path, _, err := newTestChunkedStore(t, local).GetCheckpoint(context.Background(), meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(*path); string(data) != "a plain tarball" {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestChunkedStore_CollectsChunks(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newTestChunkedStore(t, local)
+
+	first := randomBytes(5, 8<<20)
+	second := append([]byte{}, first...)
+	copy(second[4<<20:], randomBytes(6, 8192))
+	meta, err := store.PushCheckpoint(ctx, "job-a", writeFile(t, first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta2, err := store.PushCheckpoint(ctx, "job-a", writeFile(t, second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	whole, err := local.PushCheckpoint(ctx, "job-b", writeFile(t, []byte("a plain tarball")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := func() map[string]bool {
+		list, err := local.ListChunks(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digests := map[string]bool{}
+		for _, chunk := range list {
+			digests[chunk.Digest] = true
+		}
+		return digests
+	}
+	age := func() {
+		old := time.Now().Add(-2 * chunkGCGrace)
+		for digest := range chunks() {
+			if err := os.Chtimes(local.chunkPath(digest), old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	manifestChunks := func(cid string) []ChunkRef {
+		path, _, err := local.GetCheckpoint(ctx, cid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifest, err := readManifest(*path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return manifest.Chunks
+	}
+	firstChunks, secondChunks := manifestChunks(meta.ID), manifestChunks(meta2.ID)
+
+	// recently written chunks are left for pushes still in progress
+	if err := store.DeleteCheckpoint(ctx, meta.ID); err != nil {
+		t.Fatal(err)
+	}
+	before := chunks()
+	for _, ref := range firstChunks {
+		if !before[ref.Digest] {
+			t.Fatalf("chunk %s collected within the grace period", ref.Digest)
+		}
+	}
+
+	age()
+	// reusing a chunk counts as writing it
+	if _, ok, err := local.HasChunk(ctx, firstChunks[0].Digest); err != nil || !ok {
+		t.Fatalf("expected chunk %s to be found, got %v", firstChunks[0].Digest, err)
+	}
+	if err := store.collectChunks(ctx); err != nil {
+		t.Fatal(err)
+	}
+	after := chunks()
+	referenced := map[string]bool{firstChunks[0].Digest: true}
+	for _, ref := range secondChunks {
+		referenced[ref.Digest] = true
+		if !after[ref.Digest] {
+			t.Errorf("chunk %s of a remaining checkpoint was collected", ref.Digest)
+		}
+	}
+	for digest := range after {
+		if !referenced[digest] {
+			t.Errorf("unreferenced chunk %s was kept", digest)
+		}
+	}
+	if len(after) >= len(before) {
+		t.Errorf("expected the deleted checkpoint's own chunks to be collected, %d chunks before and %d after", len(before), len(after))
+	}
+
+	os.RemoveAll(store.cache.dir)
+	os.MkdirAll(store.cache.dir, 0o755)
+	path, _, err := store.GetCheckpoint(ctx, meta2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(*path); !bytes.Equal(got, second) {
+		t.Error("remaining checkpoint differs after collection")
+	}
+	if _, _, err := store.GetCheckpoint(ctx, whole.ID); err != nil {
+		t.Errorf("expected a whole checkpoint to be left alone, got %v", err)
+	}
+}
+
+func TestNewStore_Chunking(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	trusted := []string{PublicKeyString(pub)}
+	tests := []struct {
+		name    string
+		store   StoreConfig
+		signing Signing
+		want    string
+	}{
+		{"off by default", StoreConfig{Backend: "local"}, Signing{}, "*utils.LocalStore"},
+		{"local", StoreConfig{Backend: "local", Chunking: true}, Signing{}, "*utils.ChunkedStore"},
+		{"signed", StoreConfig{Backend: "local", Chunking: true}, Signing{TrustedKeys: trusted}, "*utils.ChunkedStore"},
+		{"cedana has no chunks", StoreConfig{Backend: "cedana", Chunking: true}, Signing{}, ""},
+		{"oci stays whole", StoreConfig{Backend: "oci", Chunking: true, OCI: OCIConfig{Repository: "registry.example.com/checkpoints"}}, Signing{}, "*utils.OCIStore"},
+		{"signed unchunked", StoreConfig{Backend: "local"}, Signing{TrustedKeys: trusted}, "*utils.LocalStore"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.store.LocalDir = t.TempDir()
+			tt.store.CacheDir = t.TempDir()
+			store, err := NewStore(&Config{Store: tt.store, Signing: tt.signing}, trace.NewNoopTracerProvider().Tracer("test"))
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("expected an error, got a %T", store)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprintf("%T", store); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkedStore_Stream(t *testing.T) {
+	ctx := context.Background()
+	key, _ := testNodeKey(t)
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newTestChunkedStore(t, local)
+
+	images := t.TempDir()
+	pages := randomBytes(6, 5<<20)
+	if err := os.WriteFile(filepath.Join(images, "pages-1.img"), pages, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(WriteTar(ctx, images, pw, WithEncryption(testKey, "test-key")))
+	}()
+	meta, err := store.PushCheckpointStream(ctx, "job-a", pr, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.trusted = trust(key)
+	path, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestPath, _, _ := local.GetCheckpoint(ctx, meta.ID)
+	manifest, _ := readManifest(*manifestPath)
+	if manifest.Encryption == nil || manifest.Encryption.KeyID != "test-key" {
+		t.Errorf("expected the manifest to record the encryption key, got %+v", manifest.Encryption)
+	}
+
+	dest := t.TempDir()
+	keys := func(string) ([]byte, error) { return testKey, nil }
+	if err := UntarFolder(ctx, *path, dest, WithDecryption(keys)); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dest, "pages-1.img")); !bytes.Equal(got, pages) {
+		t.Error("restored images differ")
+	}
+
+	// a stream that breaks off midway stores nothing
+	pr, pw = io.Pipe()
+	go func() {
+		pw.Write(pages[:1<<20])
+		pw.CloseWithError(errors.New("tar failed"))
+	}()
+	if _, err := store.PushCheckpointStream(ctx, "job-b", pr, key); err == nil {
+		t.Error("expected a broken stream to fail the push")
+	}
+	list, _ := store.ListCheckpoints(ctx)
+	for _, m := range *list {
+		if m.JobID == "job-b" {
+			t.Errorf("broken stream left checkpoint %s behind", m.ID)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Checkpoint archives are tarballs, compressed with one of these codecs before
+// they're encrypted. The codec isn't recorded anywhere the restore depends on:
+// it's recognized by the compressed stream's magic bytes.
+//
+// Every codec compresses the archive a chunk at a time, on the boundaries
+// ChunkedStore would cut it at, with each chunk a frame of its own, so
+// unchanged parts of successive checkpoints compress to the same bytes and
+// still dedup. Any reader of the codec reads the frames as one stream.
+const (
+	CodecNone = "none"
+	CodecGzip = "gzip"
+	CodecLZ4  = "lz4"
+	CodecZstd = "zstd"
+)
+
+// Compression is a codec and its level, written "codec" or "codec:level",
+// e.g. "zstd:19". Level 0 is the codec's default.
+type Compression struct {
+	Codec string
+	Level int
+}
+
+func (c Compression) String() string {
+	if c.Level == 0 {
+		return c.Codec
+	}
+	return fmt.Sprintf("%s:%d", c.Codec, c.Level)
+}
+
+type codec struct {
+	magic []byte
+	// levels the codec accepts besides 0
+	minLevel, maxLevel int
+	// framer returns a func compressing a chunk into a frame, which may be
+	// called on up to threads chunks at once
+	framer func(level, threads int) (func(chunk []byte) ([]byte, error), error)
+	// threads is how many cores the reader may use, where it can use several
+	reader func(r io.Reader, threads int) (io.ReadCloser, error)
+}
+
+var codecs = map[string]codec{
+	CodecGzip: {
+		magic:    []byte{0x1f, 0x8b},
+		minLevel: gzip.BestSpeed,
+		maxLevel: gzip.BestCompression,
+		framer: func(level, threads int) (func([]byte) ([]byte, error), error) {
+			if level == 0 {
+				level = gzip.DefaultCompression
+			}
+			return func(chunk []byte) ([]byte, error) { return gzipFrame(chunk, level) }, nil
+		},
+		reader: newGzipReader,
+	},
+	CodecLZ4: {
+		magic: []byte{0x04, 0x22, 0x4d, 0x18},
+		// levels switch to LZ4 HC, 1 to 9 as in lz4(1)
+		minLevel: 1,
+		maxLevel: 9,
+		framer: func(level, threads int) (func([]byte) ([]byte, error), error) {
+			var opts []lz4.Option
+			if level != 0 {
+				opts = append(opts, lz4.CompressionLevelOption(lz4.CompressionLevel(1<<(8+level))))
+			}
+			return func(chunk []byte) ([]byte, error) {
+				var frame bytes.Buffer
+				zw := lz4.NewWriter(&frame)
+				if err := zw.Apply(opts...); err != nil {
+					return nil, err
+				}
+				if _, err := zw.Write(chunk); err != nil {
+					return nil, err
+				}
+				if err := zw.Close(); err != nil {
+					return nil, err
+				}
+				return frame.Bytes(), nil
+			}, nil
+		},
+		reader: newLZ4Reader,
+	},
+	CodecZstd: {
+		magic:    []byte{0x28, 0xb5, 0x2f, 0xfd},
+		minLevel: 1,
+		maxLevel: 22,
+		framer: func(level, threads int) (func([]byte) ([]byte, error), error) {
+			// zero frames, so an empty stream still has the magic
+			opts := []zstd.EOption{zstd.WithEncoderConcurrency(threads), zstd.WithZeroFrames(true)}
+			if level != 0 {
+				opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+			}
+			enc, err := zstd.NewWriter(nil, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func(chunk []byte) ([]byte, error) { return enc.EncodeAll(chunk, nil), nil }, nil
+		},
+		reader: func(r io.Reader, threads int) (io.ReadCloser, error) {
+			d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(threads))
+			if err != nil {
+				return nil, err
+			}
+			return d.IOReadCloser(), nil
+		},
+	},
+}
+
+// Codecs lists the supported codec names, "none" included
+func Codecs() []string {
+	names := []string{CodecNone}
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// ParseCompression parses "codec" or "codec:level". "" is "none".
+func ParseCompression(s string) (Compression, error) {
+	name, level, hasLevel := strings.Cut(strings.TrimSpace(s), ":")
+	c := Compression{Codec: strings.ToLower(name)}
+	if c.Codec == "" {
+		c.Codec = CodecNone
+	}
+	if hasLevel {
+		l, err := strconv.Atoi(level)
+		if err != nil {
+			return Compression{}, fmt.Errorf("compression %q: level must be a number", s)
+		}
+		c.Level = l
+	}
+	return c, c.Validate()
+}
+
+// Validate checks the codec is known and takes the level
+func (c Compression) Validate() error {
+	if c.Codec == CodecNone {
+		if c.Level != 0 {
+			return fmt.Errorf("compression %q takes no level", c.Codec)
+		}
+		return nil
+	}
+	cd, ok := codecs[c.Codec]
+	if !ok {
+		return fmt.Errorf("unsupported compression %q, expected one of %s", c.Codec, strings.Join(Codecs(), ", "))
+	}
+	if c.Level != 0 && (c.Level < cd.minLevel || c.Level > cd.maxLevel) {
+		return fmt.Errorf("compression %q takes levels %d-%d, got %d", c.Codec, cd.minLevel, cd.maxLevel, c.Level)
+	}
+	return nil
+}
+
+// compressionThreads is how many cores (de)compression may use: n, or half
+// the machine's if n is 0, so a checkpoint doesn't starve the workloads on it.
+func compressionThreads(n int) int {
+	if n > 0 {
+		return n
+	}
+	if n = runtime.NumCPU() / 2; n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// compressWriter compresses writes to w with c, on up to threads cores; for
+// "none" it passes them through. Closing it flushes the codec but leaves w
+// open.
+func compressWriter(w io.Writer, c Compression, threads int) (io.WriteCloser, error) {
+	if c.Codec == "" || c.Codec == CodecNone {
+		return nopWriteCloser{w}, nil
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	threads = compressionThreads(threads)
+	frame, err := codecs[c.Codec].framer(c.Level, threads)
+	if err != nil {
+		return nil, err
+	}
+	fw := newFrameWriter(w, threads, frame)
+	fw.frameEmpty = true
+	return fw, nil
+}
+
+// lz4Reader reads a series of lz4 frames, where lz4.Reader stops after the
+// first.
+type lz4Reader struct {
+	br *bufio.Reader
+	zr *lz4.Reader
+}
+
+func newLZ4Reader(r io.Reader, threads int) (io.ReadCloser, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	zr := lz4.NewReader(br)
+	if err := zr.Apply(lz4.ConcurrencyOption(threads)); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&lz4Reader{br: br, zr: zr}), nil
+}
+
+func (r *lz4Reader) Read(p []byte) (int, error) {
+	n, err := r.zr.Read(p)
+	if err != io.EOF {
+		return n, err
+	}
+	if _, err := r.br.Peek(1); err != nil {
+		return n, err
+	}
+	r.zr.Reset(r.br)
+	return n, nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// DetectCodec names the codec r's stream was compressed with, from its magic
+// bytes, without consuming them. Anything unrecognized is taken to be a plain
+// tar.
+func DetectCodec(r *bufio.Reader) (string, error) {
+	head, err := r.Peek(4)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+	for name, cd := range codecs {
+		if bytes.HasPrefix(head, cd.magic) {
+			return name, nil
+		}
+	}
+	return CodecNone, nil
+}
+
+// decompressReader undoes whatever codec r was compressed with, see
+// DetectCodec, on up to threads cores, returning the codec's name along with
+// the plain stream.
+func decompressReader(r io.Reader, threads int) (io.ReadCloser, string, error) {
+	br := bufio.NewReader(r)
+	name, err := DetectCodec(br)
+	if err != nil {
+		return nil, "", err
+	}
+	if name == CodecNone {
+		return io.NopCloser(br), name, nil
+	}
+	dr, err := codecs[name].reader(br, compressionThreads(threads))
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s checkpoint archive: %w", name, err)
+	}
+	return dr, name, nil
+}
@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseCompression(t *testing.T) {
+	valid := map[string]Compression{
+		"":        {Codec: CodecNone},
+		"none":    {Codec: CodecNone},
+		"gzip":    {Codec: CodecGzip},
+		"LZ4:9":   {Codec: CodecLZ4, Level: 9},
+		"zstd:19": {Codec: CodecZstd, Level: 19},
+	}
+	for s, want := range valid {
+		got, err := ParseCompression(s)
+		if err != nil || got != want {
+			t.Errorf("%q: got %+v, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"bzip2", "zstd:23", "gzip:0x", "none:3", "lz4:-1", "lz4:10"} {
+		if _, err := ParseCompression(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestTarFolder_Compression(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	pages := syntheticPages(3 << 20)
+	if err := os.WriteFile(filepath.Join(src, "pages-1.img"), pages, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, spec := range []string{"none", "gzip", "gzip:1", "lz4", "lz4:4", "zstd", "zstd:3"} {
+		compression, err := ParseCompression(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, encrypted := range []bool{false, true} {
+			opts := []TarOption{WithCompression(compression)}
+			if encrypted {
+				opts = append(opts, WithEncryption(testKey, "test-key"))
+			}
+			var stats TarStats
+			archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+			if err := TarFolder(ctx, src, archive, append(opts, WithStats(&stats))...); err != nil {
+				t.Fatalf("%s: %v", spec, err)
+			}
+
+			if stats.Codec != compression.Codec {
+				t.Errorf("%s: stats report codec %q", spec, stats.Codec)
+			}
+			if compression.Codec == CodecNone && stats.Ratio() != 1 {
+				t.Errorf("%s: expected a ratio of 1, got %.2f", spec, stats.Ratio())
+			}
+			// the zero pages were already left out by sparse packing
+			if compression.Codec != CodecNone && stats.Ratio() < 1.5 {
+				t.Errorf("%s: expected the pages to compress, got a ratio of %.2f", spec, stats.Ratio())
+			}
+
+			if !encrypted {
+				f, _ := os.Open(archive)
+				codec, err := DetectCodec(bufio.NewReader(f))
+				f.Close()
+				if err != nil || codec != compression.Codec {
+					t.Errorf("%s: detected %q, %v", spec, codec, err)
+				}
+			}
+
+			dest := t.TempDir()
+			keys := func(keyID string) ([]byte, error) { return testKey, nil }
+			if err := UntarFolder(ctx, archive, dest, WithDecryption(keys)); err != nil {
+				t.Fatalf("%s (encrypted %v): %v", spec, encrypted, err)
+			}
+			if got, _ := os.ReadFile(filepath.Join(dest, "pages-1.img")); !bytes.Equal(got, pages) {
+				t.Errorf("%s (encrypted %v): round trip differs", spec, encrypted)
+			}
+		}
+	}
+
+	if err := TarFolder(ctx, src, filepath.Join(t.TempDir(), "x.tar"), WithCompression(Compression{Codec: "brotli"})); err == nil {
+		t.Error("expected an unknown codec to be an error")
+	}
+}
+
+func TestBlockGzip(t *testing.T) {
+	data := randomBytes(3, 3*gzipBlockSize+12345)
+	copy(data[gzipBlockSize:], bytes.Repeat([]byte{0}, gzipBlockSize))
+
+	var buf bytes.Buffer
+	zw, err := compressWriter(&buf, Compression{Codec: CodecGzip}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// odd write sizes, to cross block boundaries mid-write
+	for p := data; len(p) > 0; {
+		n := 300000
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := zw.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sealed := buf.Bytes()
+
+	// any gzip reader can read it
+	zr, err := gzip.NewReader(bytes.NewReader(sealed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(zr); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("stdlib gzip: round trip differs, %v", err)
+	}
+
+	read := func(sealed []byte) ([]byte, error) {
+		r, err := newGzipReader(bytes.NewReader(sealed), 4)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	if got, err := read(sealed); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("parallel: round trip differs, %v", err)
+	}
+
+	// plain gzip is read the ordinary way
+	var plain bytes.Buffer
+	pw := gzip.NewWriter(&plain)
+	pw.Write(data)
+	pw.Close()
+	if got, err := read(plain.Bytes()); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("plain gzip: round trip differs, %v", err)
+	}
+
+	if _, err := read(sealed[:len(sealed)-100]); err == nil {
+		t.Error("expected a truncated stream to fail")
+	}
+	corrupt := append([]byte{}, sealed...)
+	binary.LittleEndian.PutUint32(corrupt[gzipSizeOffset:], 1<<30)
+	if _, err := read(corrupt); err == nil {
+		t.Error("expected an implausible block size to fail")
+	}
+
+	// a trailer understating the block mustn't let the rest decompress
+	bomb, err := gzipMember(make([]byte, gzipBlockSize), gzip.BestCompression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint32(bomb[len(bomb)-4:], 10)
+	if block := gunzipMember(bomb); !errors.Is(block.err, errCorruptBlockGzip) || len(block.data) != 0 {
+		t.Errorf("expected a block larger than its trailer to be corrupt, got %d bytes, %v", len(block.data), block.err)
+	}
+
+	var empty bytes.Buffer
+	zw, _ = compressWriter(&empty, Compression{Codec: CodecGzip}, 4)
+	zw.Close()
+	if got, err := read(empty.Bytes()); err != nil || len(got) != 0 {
+		t.Errorf("expected an empty stream, got %d bytes, %v", len(got), err)
+	}
+}
+
+func compress(t *testing.T, data []byte, c Compression) []byte {
+	var buf bytes.Buffer
+	zw, err := compressWriter(&buf, c, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompression_Deterministic(t *testing.T) {
+	data := append(syntheticPages(8<<20), randomBytes(4, 8<<20)...)
+	edited := append([]byte{}, data...)
+	copy(edited[12<<20:], randomBytes(5, 8192))
+
+	for _, codec := range []string{CodecGzip, CodecLZ4, CodecZstd} {
+		c := Compression{Codec: codec}
+		compressed := compress(t, data, c)
+		if !bytes.Equal(compress(t, data, c), compressed) {
+			t.Errorf("%s: expected the same input to compress the same", codec)
+		}
+
+		r, name, err := decompressReader(bytes.NewReader(compressed), 4)
+		if err != nil || name != codec {
+			t.Fatalf("%s: detected %q, %v", codec, name, err)
+		}
+		if got, err := io.ReadAll(r); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: round trip differs, %v", codec, err)
+		}
+
+		// an edit only changes the store chunks around it
+		seen := map[string]bool{}
+		chunks := chunkAll(t, compressed)
+		for _, chunk := range chunks {
+			seen[string(chunk)] = true
+		}
+		shared := 0
+		for _, chunk := range chunkAll(t, compress(t, edited, c)) {
+			if seen[string(chunk)] {
+				shared++
+			}
+		}
+		if shared < len(chunks)-3 {
+			t.Errorf("%s: only %d of %d chunks survived an edit", codec, shared, len(chunks))
+		}
+	}
+}
+
+func TestUntarFolder_Truncated(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "pages-1.img"), randomBytes(4, 3*extractChunkSize), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(ctx, src, archive); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(archive)
+	os.WriteFile(archive, data[:len(data)/2], 0o644)
+
+	if err := UntarFolder(ctx, archive, t.TempDir(), WithThreads(4)); err == nil {
+		t.Error("expected a truncated archive to fail")
+	}
+}
+
+// syntheticPages is a page image like CRIU's: zeroed pages, pages of repeated
+// structures and pages of incompressible data.
+func syntheticPages(size int) []byte {
+	const pageSize = 4096
+	pages := make([]byte, size)
+	random := randomBytes(11, size)
+	record := []byte("\x00\x00\x00\x01heap object header\x7f\x00\x00\x00")
+	for off := 0; off < size; off += pageSize {
+		page := pages[off:]
+		if len(page) > pageSize {
+			page = page[:pageSize]
+		}
+		switch (off / pageSize) % 10 {
+		case 0, 1, 2, 3:
+		case 4, 5, 6:
+			for i := 0; i < len(page); i += copy(page[i:], record) {
+			}
+		default:
+			copy(page, random[off:])
+		}
+	}
+	return pages
+}
+
+func benchmarkThreads() []int {
+	if runtime.NumCPU() == 1 {
+		return []int{1}
+	}
+	return []int{1, runtime.NumCPU()}
+}
+
+// go test -run XXX -bench Compression ./utils
+func BenchmarkCompression(b *testing.B) {
+	pages := syntheticPages(64 << 20)
+	for _, spec := range []string{"none", "gzip", "lz4", "zstd", "zstd:19"} {
+		compression, _ := ParseCompression(spec)
+		for _, threads := range benchmarkThreads() {
+			b.Run(fmt.Sprintf("%s/threads=%d", spec, threads), func(b *testing.B) {
+				b.SetBytes(int64(len(pages)))
+				var compressed int64
+				for i := 0; i < b.N; i++ {
+					cw := &countingWriter{w: io.Discard}
+					zw, err := compressWriter(cw, compression, threads)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := zw.Write(pages); err != nil {
+						b.Fatal(err)
+					}
+					if err := zw.Close(); err != nil {
+						b.Fatal(err)
+					}
+					compressed = cw.n
+				}
+				b.ReportMetric(float64(len(pages))/float64(compressed), "ratio")
+			})
+		}
+	}
+}
+
+func BenchmarkExtraction(b *testing.B) {
+	ctx := context.Background()
+	src := b.TempDir()
+	pages := syntheticPages(64 << 20)
+	if err := os.WriteFile(filepath.Join(src, "pages-1.img"), pages, 0o644); err != nil {
+		b.Fatal(err)
+	}
+	for _, spec := range []string{"none", "gzip", "lz4", "zstd"} {
+		compression, _ := ParseCompression(spec)
+		archive := filepath.Join(b.TempDir(), "checkpoint.tar")
+		if err := TarFolder(ctx, src, archive, WithCompression(compression)); err != nil {
+			b.Fatal(err)
+		}
+		for _, threads := range benchmarkThreads() {
+			b.Run(fmt.Sprintf("%s/threads=%d", spec, threads), func(b *testing.B) {
+				b.SetBytes(int64(len(pages)))
+				for i := 0; i < b.N; i++ {
+					if err := UntarFolder(ctx, archive, b.TempDir(), WithThreads(threads)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestTarFolder_Cancelled(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "pages-1.img"), syntheticPages(1<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(context.Background(), src, archive); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	partial := filepath.Join(t.TempDir(), "cancelled.tar")
+	if err := TarFolder(ctx, src, partial, WithCompression(Compression{Codec: CodecZstd})); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled tar to stop with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Errorf("expected the partial tarball to be removed, got %v", err)
+	}
+
+	dest := t.TempDir()
+	if err := UntarFolder(ctx, archive, dest, WithThreads(4)); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled extraction to stop with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "pages-1.img")); err == nil {
+		t.Error("expected a cancelled extraction not to write the image")
+	}
+}
@@ -2,179 +2,238 @@ package utils
 
 import (
 	"archive/tar"
-	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-
-	"github.com/pierrec/lz4"
+	"syscall"
 )
 
-func TarFolder(srcFolder, destTar string) error {
-	file, err := os.Create(destTar)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	tw := tar.NewWriter(file)
-	defer tw.Close()
-
-	err = filepath.Walk(srcFolder, func(file string, fi os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// ctxReader fails reads once its context is done, so a long io.Copy
+// of a multi-GB image can be abandoned midway.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
 
-		header, err := tar.FileInfoHeader(fi, file)
-		if err != nil {
-			return err
-		}
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
 
-		// Adjust the file's path to exclude the base directory
-		relPath, err := filepath.Rel(srcFolder, file)
-		if err != nil {
-			return err
-		}
-		header.Name = relPath
+// TarOption configures TarFolder and UntarFolder
+type TarOption func(*tarOptions)
+
+type tarOptions struct {
+	key           []byte
+	keyID         string
+	deterministic bool
+	keys          func(keyID string) ([]byte, error)
+	compression   Compression
+	threads       int
+	stats         *TarStats
+	limits        ExtractLimits
+	trusted       TrustedKeys
+	// see WithImageStream and WithImageStreamer
+	imageStream      io.Reader
+	imageStreamReady func() error
+	imageStreamer    string
+}
 
-		if err := tw.WriteHeader(header); err != nil {
-			return err
-		}
+// TarStats is what WriteTar wrote: the codec, and the size of the tar stream
+// before and after compression.
+type TarStats struct {
+	Codec          string
+	Size           int64
+	CompressedSize int64
+}
 
-		if !fi.Mode().IsRegular() {
-			return nil
-		}
+// Ratio is how many times smaller compression made the tarball
+func (s *TarStats) Ratio() float64 {
+	if s.CompressedSize == 0 {
+		return 0
+	}
+	return float64(s.Size) / float64(s.CompressedSize)
+}
 
-		srcFile, err := os.Open(file)
-		if err != nil {
-			return err
-		}
-		defer srcFile.Close()
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
 
-		_, err = io.Copy(tw, srcFile)
-		return err
-	})
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
 
-	return err
+// WithCompression has TarFolder compress the tarball with c. Compression
+// happens before encryption, which would leave nothing to compress.
+func WithCompression(c Compression) TarOption {
+	return func(o *tarOptions) {
+		o.compression = c
+	}
 }
 
-func UntarFolder(srcTar, destFolder string) error {
-	file, err := os.Open(srcTar)
-	if err != nil {
-		return err
+// WithThreads caps the cores TarFolder and UntarFolder use for compression
+// and extraction; 0, the default, is half of them.
+func WithThreads(n int) TarOption {
+	return func(o *tarOptions) {
+		o.threads = n
 	}
-	defer file.Close()
+}
 
-	tr := tar.NewReader(file)
+// WithLimits overrides DefaultExtractLimits for UntarFolder; zero fields keep
+// their default.
+func WithLimits(l ExtractLimits) TarOption {
+	return func(o *tarOptions) {
+		o.limits = l
+	}
+}
 
-	// Iterate through the files in the tarball
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break // End of tarball
-		}
-		if err != nil {
-			return err
-		}
+// WithStats has TarFolder fill in stats once the tarball is written
+func WithStats(stats *TarStats) TarOption {
+	return func(o *tarOptions) {
+		o.stats = stats
+	}
+}
 
-		// Construct the full path for the file
-		target := filepath.Join(destFolder, header.Name)
+// WithEncryption has TarFolder encrypt the tarball under key, recording keyID
+// in it; see NewEncryptWriter.
+func WithEncryption(key []byte, keyID string) TarOption {
+	return func(o *tarOptions) {
+		o.key = key
+		o.keyID = keyID
+	}
+}
 
-		// Check the type of the file
-		switch header.Typeflag {
-		case tar.TypeDir:
-			// Create directory
-			if err := os.MkdirAll(target, 0755); err != nil {
-				return err
-			}
-		case tar.TypeReg:
-			// Create file and write data into it
-			outFile, err := os.Create(target)
-			if err != nil {
-				return err
-			}
+// WithDeterministicEncryption has WithEncryption seal the same plaintext the
+// same, so encrypted checkpoints still deduplicate, at the cost of showing
+// which parts of them repeat; see NewEncryptWriter.
+func WithDeterministicEncryption() TarOption {
+	return func(o *tarOptions) {
+		o.deterministic = true
+	}
+}
 
-			if _, err := io.Copy(outFile, tr); err != nil {
-				outFile.Close()
-				return err
-			}
-			outFile.Close()
-		}
+// WithDecryption has UntarFolder decrypt an encrypted tarball, looking its key
+// up by ID. Plain tarballs are read as they are.
+func WithDecryption(keys func(keyID string) ([]byte, error)) TarOption {
+	return func(o *tarOptions) {
+		o.keys = keys
 	}
+}
 
-	return nil
+// WithTrustedKeys has UntarFolder check the tarball against its signed
+// manifest, which must be signed by one of trusted, before extracting it. The
+// file checked is the one extracted, so it can't be swapped in between.
+func WithTrustedKeys(trusted TrustedKeys) TarOption {
+	return func(o *tarOptions) {
+		o.trusted = trusted
+	}
 }
 
-func TarLZ4Folder(srcFolder, destTar string) error {
-	file, err := os.Create(destTar)
+// TarFolder writes srcFolder into a tarball at destTar, readable only by its
+// owner. It's uncompressed unless WithCompression says otherwise. If ctx is
+// cancelled the partially written tarball is removed and ctx.Err() returned.
+func TarFolder(ctx context.Context, srcFolder, destTar string, opts ...TarOption) (err error) {
+	file, err := os.OpenFile(destTar, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(destTar)
+		}
+	}()
 
-	zw := lz4.NewWriter(file)
-	defer zw.Close()
+	return WriteTar(ctx, srcFolder, file, opts...)
+}
 
-	tw := tar.NewWriter(zw)
-	defer tw.Close()
+// WriteTar writes srcFolder as a tarball to w, which is left open. It is
+// TarFolder for destinations other than a file, e.g. a pipe to the store.
+func WriteTar(ctx context.Context, srcFolder string, w io.Writer, opts ...TarOption) (err error) {
+	var o tarOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-	err = filepath.Walk(srcFolder, func(file string, fi os.FileInfo, err error) error {
-		if err != nil {
+	if o.compression.Codec != "" {
+		if err := o.compression.Validate(); err != nil {
 			return err
 		}
+	}
 
-		header, err := tar.FileInfoHeader(fi, file)
-		if err != nil {
+	var enc io.WriteCloser
+	if o.key != nil {
+		if enc, err = NewEncryptWriter(w, o.key, o.keyID, o.deterministic); err != nil {
 			return err
 		}
+		w = enc
+	}
 
-		// Adjust the file's path to exclude the base directory
-		relPath, err := filepath.Rel(srcFolder, file)
-		if err != nil {
-			return err
-		}
-		header.Name = relPath
+	compressed := &countingWriter{w: w}
+	zw, err := compressWriter(compressed, o.compression, o.threads)
+	if err != nil {
+		return err
+	}
+	uncompressed := &countingWriter{w: zw}
 
-		if err := tw.WriteHeader(header); err != nil {
-			return err
+	tw := tar.NewWriter(uncompressed)
+	defer func() {
+		if cerr := tw.Close(); err == nil {
+			err = cerr
 		}
-
-		if !fi.Mode().IsRegular() {
-			return nil
+		if cerr := zw.Close(); err == nil {
+			err = cerr
+		}
+		if enc != nil {
+			if cerr := enc.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err == nil && o.stats != nil {
+			*o.stats = TarStats{Codec: o.compression.Codec, Size: uncompressed.n, CompressedSize: compressed.n}
+			if o.stats.Codec == "" {
+				o.stats.Codec = CodecNone
+			}
 		}
+	}()
 
-		srcFile, err := os.Open(file)
-		if err != nil {
+	if o.imageStream != nil {
+		if err := writeImageStream(ctx, tw, o.imageStream); err != nil {
+			return err
+		}
+	}
+	if o.imageStreamReady != nil {
+		if err := o.imageStreamReady(); err != nil {
 			return err
 		}
-		defer srcFile.Close()
-
-		_, err = io.Copy(tw, srcFile)
-		return err
-	})
-
-	return err
-}
-
-func TarGzFolder(srcFolder, destTar string) error {
-	file, err := os.Create(destTar)
-	if err != nil {
-		return err
 	}
-	defer file.Close()
-
-	gw := gzip.NewWriter(file)
-	defer gw.Close()
 
-	tw := tar.NewWriter(gw)
-	defer tw.Close()
+	// files with several names are archived once, then as hardlinks
+	inodes := map[[2]uint64]string{}
 
 	err = filepath.Walk(srcFolder, func(file string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-		header, err := tar.FileInfoHeader(fi, file)
+		var link string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(fi, link)
 		if err != nil {
 			return err
 		}
@@ -186,11 +245,28 @@ func TarGzFolder(srcFolder, destTar string) error {
 		}
 		header.Name = relPath
 
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok && fi.Mode().IsRegular() && st.Nlink > 1 {
+			inode := [2]uint64{uint64(st.Dev), uint64(st.Ino)}
+			if first, ok := inodes[inode]; ok {
+				header.Typeflag = tar.TypeLink
+				header.Linkname = first
+				header.Size = 0
+			} else {
+				inodes[inode] = relPath
+			}
+		}
+		if header.PAXRecords, err = readXattrs(file); err != nil {
+			return err
+		}
+
+		if header.Typeflag == tar.TypeReg && isPagesImage(relPath) {
+			return writeSparse(ctx, tw, header, file)
+		}
 		if err := tw.WriteHeader(header); err != nil {
 			return err
 		}
 
-		if !fi.Mode().IsRegular() {
+		if header.Typeflag != tar.TypeReg {
 			return nil
 		}
 
@@ -200,65 +276,118 @@ func TarGzFolder(srcFolder, destTar string) error {
 		}
 		defer srcFile.Close()
 
-		_, err = io.Copy(tw, srcFile)
+		_, err = io.Copy(tw, &ctxReader{ctx: ctx, r: srcFile})
 		return err
 	})
 
 	return err
 }
 
-func UntarGzFolder(srcTarGz, destFolder string) error {
-	// Open the gzip file
-	file, err := os.Open(srcTarGz)
+// UntarFolder extracts the tarball at srcTar into destFolder, decrypting and
+// decompressing it as needed, see extractor for what it will and won't
+// extract.
+func UntarFolder(ctx context.Context, srcTar, destFolder string, opts ...TarOption) (err error) {
+	var o tarOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	file, err := os.Open(srcTar)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	// Create a gzip reader on top of the file reader
-	gr, err := gzip.NewReader(file)
+	if o.trusted != nil {
+		if _, err := verifyArchiveFile(ctx, file, srcTar, o.trusted); err != nil {
+			return err
+		}
+	}
+
+	var r io.Reader = file
+	header, err := ReadEncryptionHeader(file)
+	switch {
+	case errors.Is(err, ErrNotEncrypted):
+	case err != nil:
+		return err
+	case o.keys == nil:
+		return fmt.Errorf("%s is encrypted with key %s, but no encryption key is configured", srcTar, header.KeyID)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if header != nil {
+		if r, err = NewDecryptReader(file, o.keys); err != nil {
+			return err
+		}
+	}
+
+	// compressed archives are recognized by their magic bytes
+	dr, _, err := decompressReader(r, o.threads)
 	if err != nil {
 		return err
 	}
-	defer gr.Close()
+	defer dr.Close()
 
-	// Create a tar reader on top of the gzip reader
-	tr := tar.NewReader(gr)
+	tr := tar.NewReader(&ctxReader{ctx: ctx, r: dr})
+	ex := newExtractor(destFolder, compressionThreads(o.threads), o.limits)
+	defer func() {
+		if ferr := ex.finish(); err == nil {
+			err = ferr
+		}
+	}()
+
+	// streamed images come first, and are written out by the streamer before
+	// anything else is extracted, so nothing in the archive can redirect them
+	var images *imageExtraction
+	defer func() {
+		if images != nil {
+			images.cmd.Process.Kill()
+			images.finish()
+		}
+	}()
+	extracted := false
 
-	// Iterate through the files in the tarball
 	for {
 		header, err := tr.Next()
 		if err == io.EOF {
-			break // End of tarball
+			break
 		}
 		if err != nil {
 			return err
 		}
-
-		// Construct the full path for the file
-		target := filepath.Join(destFolder, header.Name)
-
-		// Check the type of the file
-		switch header.Typeflag {
-		case tar.TypeDir:
-			// Create directory
-			if err := os.MkdirAll(target, 0755); err != nil {
-				return err
+		if isImageStreamPiece(header.Name) {
+			if extracted {
+				return fmt.Errorf("image stream piece %q follows the archive's files", header.Name)
 			}
-		case tar.TypeReg:
-			// Create file and write data into it
-			outFile, err := os.Create(target)
-			if err != nil {
+			// the images come out about the size of the stream
+			if ex.size += header.Size; ex.size > ex.limits.MaxSize {
+				return unsafeArchive("archive is over the %d byte limit", ex.limits.MaxSize)
+			}
+			if images == nil {
+				if images, err = startImageExtraction(ctx, o.imageStreamer, destFolder); err != nil {
+					return err
+				}
+			}
+			if err := images.piece(header, tr); err != nil {
 				return err
 			}
-
-			if _, err := io.Copy(outFile, tr); err != nil {
-				outFile.Close()
+			continue
+		}
+		if images != nil {
+			err, images = images.finish(), nil
+			if err != nil {
 				return err
 			}
-			outFile.Close()
+		}
+		extracted = true
+		if err := ex.entry(header, tr); err != nil {
+			return fmt.Errorf("extracting %q: %w", header.Name, err)
 		}
 	}
-
+	if images != nil {
+		err, images = images.finish(), nil
+		return err
+	}
 	return nil
 }
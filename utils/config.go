@@ -1,18 +1,42 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 
-	"github.com/spf13/viper"
+	"github.com/rs/zerolog"
 )
 
+// Config is read in layers, each overriding the ones before it: the defaults,
+// SystemConfigPath, the user's config file, CEDANA_* environment variables,
+// then --set flags. See LoadConfig.
 type Config struct {
-	Client        Client        `json:"client" mapstructure:"client"`
-	Connection    Connection    `json:"connection" mapstructure:"connection"`
-	SharedStorage SharedStorage `json:"shared_storage" mapstructure:"shared_storage"`
+	Client        Client           `json:"client" mapstructure:"client"`
+	Connection    Connection       `json:"connection" mapstructure:"connection"`
+	SharedStorage SharedStorage    `json:"shared_storage" mapstructure:"shared_storage"`
+	Preemption    Preemption       `json:"preemption" mapstructure:"preemption"`
+	Store         StoreConfig      `json:"store" mapstructure:"store"`
+	Encryption    Encryption       `json:"encryption" mapstructure:"encryption"`
+	Signing       Signing          `json:"signing" mapstructure:"signing"`
+	GPU           GPUConfig        `json:"gpu" mapstructure:"gpu"`
+	Otel          OtelConfig       `json:"otel" mapstructure:"otel"`
+	Kubernetes    KubernetesConfig `json:"kubernetes" mapstructure:"kubernetes"`
+
+	// where each key's value came from, see Source
+	sources map[string]string
+	files   []string
+	// the layer each file in files is, see Layer
+	fileLayers map[string]string
 }
 
 type Client struct {
@@ -20,6 +44,19 @@ type Client struct {
 	Task         string `json:"task" mapstructure:"task"`
 	LeaveRunning bool   `json:"leave_running" mapstructure:"leave_running"`
 	ForwardLogs  bool   `json:"forward_logs" mapstructure:"forward_logs"`
+	// seconds between full job state snapshots on ClientStateStreaming; 0 uses the default
+	StateStreamInterval int `json:"state_stream_interval" mapstructure:"state_stream_interval"`
+	// codec for checkpoint archives when a dump doesn't pick one, e.g. "lz4"
+	// or "zstd:3"; see ParseCompression. Defaults to none.
+	Compression string `json:"compression" mapstructure:"compression"`
+	// cores checkpoint compression and extraction may use; 0 is half of them
+	CompressionThreads int `json:"compression_threads" mapstructure:"compression_threads"`
+	// checkpoint jobs to the store, and restore them from it, rather than
+	// local disk
+	Remote bool `json:"remote" mapstructure:"remote"`
+	// "trace", "debug", "info", "warn" or "error"; empty leaves it to the
+	// LOG_LEVEL variable, which defaults to debug
+	LogLevel string `json:"log_level" mapstructure:"log_level"`
 }
 
 type Connection struct {
@@ -32,80 +69,875 @@ type SharedStorage struct {
 	DumpStorageDir string `json:"dump_storage_dir" mapstructure:"dump_storage_dir"`
 }
 
+// StoreConfig picks where REMOTE checkpoints are pushed to and pulled from.
+type StoreConfig struct {
+	// "cedana" (the default) for the managed endpoint, "local", "s3" or "oci"
+	Backend string `json:"backend" mapstructure:"backend"`
+	// root of the local store; may be an NFS mount shared between nodes
+	LocalDir string    `json:"local_dir" mapstructure:"local_dir"`
+	S3       S3Config  `json:"s3" mapstructure:"s3"`
+	OCI      OCIConfig `json:"oci" mapstructure:"oci"`
+	// where remote checkpoints are downloaded to for restore, defaults to
+	// /var/cache/cedana/checkpoints
+	CacheDir string `json:"cache_dir" mapstructure:"cache_dir"`
+	// bytes the cache may hold before least recently used checkpoints are
+	// evicted, defaults to 20GiB
+	CacheMaxSize int64 `json:"cache_max_size" mapstructure:"cache_max_size"`
+	// push checkpoints as deduplicated chunks instead of whole, for the local
+	// and s3 stores, which hold chunks natively
+	Chunking bool `json:"chunking" mapstructure:"chunking"`
+	// chunked checkpoints are streamed to the store while CRIU dumps, with
+	// nothing but small metadata left on local disk; spill keeps a copy of
+	// the archive next to the image dir as well, which restores locally
+	Spill bool `json:"spill" mapstructure:"spill"`
+	// criu-image-streamer binary CRIU streams its images through, looked up
+	// in PATH, defaults to criu-image-streamer. Without it, or set to "",
+	// CRIU writes the images to the image dir and they're streamed from there
+	// once it's done. Restoring a checkpoint streamed this way needs it too.
+	ImageStreamer string `json:"image_streamer" mapstructure:"image_streamer"`
+}
+
+// S3Config points the s3 store at any S3-compatible service. Credentials left
+// empty fall back to the AWS environment variables, shared credentials file
+// or instance role.
+type S3Config struct {
+	// host[:port], e.g. s3.us-east-1.amazonaws.com or localhost:9000 for MinIO
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	Region   string `json:"region" mapstructure:"region"`
+	Bucket   string `json:"bucket" mapstructure:"bucket"`
+	// prepended to every key, checkpoints go under <prefix>/<job id>/
+	Prefix          string `json:"prefix" mapstructure:"prefix"`
+	AccessKeyID     string `json:"access_key_id" mapstructure:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key" mapstructure:"secret_access_key"`
+	SessionToken    string `json:"session_token" mapstructure:"session_token"`
+	// plain http, e.g. for a local MinIO
+	Insecure bool `json:"insecure" mapstructure:"insecure"`
+	// bytes per multipart part and ranged GET, defaults to 64MiB
+	PartSize int64 `json:"part_size" mapstructure:"part_size"`
+	// parts in flight at once, defaults to 4
+	Concurrency int `json:"concurrency" mapstructure:"concurrency"`
+}
+
+// OCIConfig points the oci store at a repository in an OCI registry.
+// Checkpoints are pushed to it as artifacts and tagged after their job.
+type OCIConfig struct {
+	// <registry host>/<name>, e.g. localhost:5000/cedana/checkpoints
+	Repository string `json:"repository" mapstructure:"repository"`
+	// left empty for anonymous access
+	Username string `json:"username" mapstructure:"username"`
+	Password string `json:"password" mapstructure:"password"`
+	// plain http, e.g. for a local registry:2
+	Insecure bool `json:"insecure" mapstructure:"insecure"`
+}
+
+// Encryption configures encryption of checkpoint archives. Encrypted
+// archives look random to the chunker, so successive checkpoints of a job no
+// longer deduplicate against each other unless deterministic is set.
+type Encryption struct {
+	// encrypt new checkpoints; encrypted ones are decrypted on restore
+	// whenever a key is configured, regardless of this
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// a provider registered with RegisterKeyProvider; empty means "file" if
+	// key_file is set and "env" otherwise
+	Provider string `json:"provider" mapstructure:"provider"`
+	// file holding a 32-byte master key, raw or hex/base64 encoded
+	KeyFile string `json:"key_file" mapstructure:"key_file"`
+	// variable holding the master key, hex or base64 encoded; defaults to
+	// CEDANA_CHECKPOINT_KEY
+	KeyEnv string `json:"key_env" mapstructure:"key_env"`
+	// seal the same plaintext the same under a job's key, so its encrypted
+	// checkpoints still deduplicate. Anyone who can read the store can then
+	// tell which parts of a job's memory didn't change between checkpoints.
+	Deterministic bool `json:"deterministic" mapstructure:"deterministic"`
+}
+
+// Signing configures checkpoint provenance. Checkpoints are signed with the
+// node's key once signing is enabled, and restores only check signatures once
+// trusted_keys is set.
+type Signing struct {
+	// sign checkpoints, so nodes that set trusted_keys can restore them.
+	// Setting trusted_keys turns it on too, as this node's own checkpoints
+	// must be signed to be restored here.
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// the node's ed25519 key, generated on first use; defaults to
+	// /etc/cedana/node_key
+	KeyFile string `json:"key_file" mapstructure:"key_file"`
+	// base64 public keys, as printed by `cedana checkpoint key`, of the nodes
+	// whose checkpoints may be restored here. This node's own key is trusted
+	// too.
+	TrustedKeys []string `json:"trusted_keys" mapstructure:"trusted_keys"`
+}
+
+// Signs reports whether checkpoints made with this config are signed
+func (s Signing) Signs() bool {
+	return s.Enabled || len(s.TrustedKeys) > 0
+}
+
+// GPUConfig turns on checkpointing of GPU state, through the GPU controller.
+type GPUConfig struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+}
+
+// OtelConfig turns on tracing. Traces are exported over OTLP, configured by
+// the standard OTEL_EXPORTER_OTLP_* variables.
+type OtelConfig struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+}
+
+// KubernetesConfig is for a daemon running in the cedana helper pod, which
+// joins the network namespace of its pause container on startup.
+type KubernetesConfig struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+}
+
+// Preemption configures the daemon's own watch for spot interruption or
+// rebalance notices. Leaving both MetadataURL and NoticeFile empty disables it.
+type Preemption struct {
+	// IMDS-style endpoint returning {"action": ..., "time": ...} when the
+	// instance is about to go, e.g.
+	// http://169.254.169.254/latest/meta-data/spot/instance-action
+	MetadataURL string `json:"metadata_url" mapstructure:"metadata_url"`
+	// local file in the same JSON format, for providers that deliver notices
+	// some other way
+	NoticeFile string `json:"notice_file" mapstructure:"notice_file"`
+	// seconds between checks, defaults to 5
+	PollInterval int `json:"poll_interval" mapstructure:"poll_interval"`
+	// jobs checkpointed first, in this order, ahead of all others
+	PriorityJobs []string `json:"priority_jobs" mapstructure:"priority_jobs"`
+	// jobs checkpointed at once, defaults to 2
+	MaxParallel int `json:"max_parallel" mapstructure:"max_parallel"`
+	// where the emergency checkpoint report is written
+	ReportPath string `json:"report_path" mapstructure:"report_path"`
+}
+
+const (
+	// SystemConfigPath is the config shared by every user of the machine
+	SystemConfigPath = "/etc/cedana/config.json"
+
+	configSourceDefault = "default"
+	configSourceFlag    = "flag --set"
+)
+
+// secretConfigKeys are left out of what's printed for anyone to see, see
+// SecretConfigKey
+var secretConfigKeys = map[string]bool{
+	"connection.cedana_auth_token": true,
+	"store.s3.secret_access_key":   true,
+	"store.s3.session_token":       true,
+	"store.oci.password":           true,
+}
+
+// legacyConfigEnv are variables that predate the CEDANA_<KEY> scheme. The
+// CEDANA_<KEY> variable wins when both are set.
+var legacyConfigEnv = map[string]string{
+	"CEDANA_REMOTE": "client.remote",
+	"IS_K8S":        "kubernetes.enabled",
+}
+
+// legacyConfigKeys were written by old sample configs, and are ignored
+var legacyConfigKeys = map[string]bool{
+	"client.process_name":    true,
+	"connection.cedana_user": true,
+}
+
+// DefaultConfig is the config before anything overrides it
+func DefaultConfig() Config {
+	return Config{
+		Client:        Client{Compression: CodecNone},
+		SharedStorage: SharedStorage{DumpStorageDir: "/tmp"},
+		Store: StoreConfig{
+			Backend:       "cedana",
+			ImageStreamer: "criu-image-streamer",
+			CacheDir:      defaultCacheDir,
+			CacheMaxSize:  defaultCacheMaxSize,
+			S3:            S3Config{PartSize: defaultS3PartSize, Concurrency: defaultS3Concurrency},
+		},
+		Encryption: Encryption{KeyEnv: defaultKeyEnv},
+		Signing:    Signing{KeyFile: defaultNodeKeyFile},
+	}
+}
+
+// ConfigOptions are where LoadConfig reads the config from, besides the
+// defaults.
+type ConfigOptions struct {
+	// defaults to SystemConfigPath
+	SystemFile string
+	// defaults to ~/.cedana/client_config.json, of the user behind sudo if
+	// cedana was run with it. Overrides set up for the instance are read from
+	// server_overrides.json next to it.
+	UserFile string
+	// KEY=value pairs, defaults to os.Environ()
+	Env []string
+	// key=value pairs, from --set
+	Set []string
+}
+
+var configOptions ConfigOptions
+
+// SetConfigOptions sets the options InitConfig loads the config with, for
+// the rest of the process.
+func SetConfigOptions(opts ConfigOptions) {
+	configOptions = opts
+}
+
+// InitConfig loads the config with the options set by SetConfigOptions.
 func InitConfig() (*Config, error) {
-	var username string
-	// have to run cedana as root, but it overrides os.UserHomeDir w/ /root
-	username = os.Getenv("SUDO_USER")
-	if username == "" {
-		username = os.Getenv("USER")
+	return LoadConfig(configOptions)
+}
+
+// LoadConfig reads the config's layers and validates the result. Missing
+// files are skipped; anything invalid, in any layer, is an error naming the
+// key and where its value came from.
+func LoadConfig(opts ConfigOptions) (*Config, error) {
+	return loadConfig(opts, readConfigFile)
+}
+
+// loadConfig is LoadConfig, reading config files with read
+func loadConfig(opts ConfigOptions, read func(path string) (map[string]interface{}, error)) (*Config, error) {
+	config := newConfig()
+	var errs []error
+	set := func(key, source string, value interface{}) {
+		if err := config.setFrom(key, source, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.SystemFile == "" {
+		opts.SystemFile = SystemConfigPath
+	}
+	files := []string{opts.SystemFile}
+	layers := []string{"system file"}
+	if opts.UserFile == "" {
+		if dir := userConfigDir(); dir != "" {
+			opts.UserFile = filepath.Join(dir, "client_config.json")
+		}
+	}
+	if opts.UserFile != "" {
+		files = append(files, opts.UserFile, filepath.Join(filepath.Dir(opts.UserFile), "server_overrides.json"))
+		layers = append(layers, "user file", "server_overrides.json")
+	}
+	for i, file := range files {
+		values, err := read(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		config.files = append(config.files, file)
+		config.fileLayers[file] = layers[i]
+		for _, key := range sortedKeys(values) {
+			set(key, file, values[key])
+		}
+	}
+
+	env := opts.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	vars := map[string]string{}
+	for _, kv := range env {
+		if name, value, ok := strings.Cut(kv, "="); ok && value != "" {
+			vars[name] = value
+		}
+	}
+	for name, key := range legacyConfigEnv {
+		if value, ok := vars[name]; ok {
+			set(key, "env "+name, value)
+		}
+	}
+	for _, field := range configFields {
+		if value, ok := vars[field.env]; ok {
+			set(field.key, "env "+field.env, value)
+		}
+	}
+
+	for _, kv := range opts.Set {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			errs = append(errs, &ConfigError{Key: kv, Source: configSourceFlag, Err: errors.New("expected key=value")})
+			continue
+		}
+		set(strings.ToLower(strings.TrimSpace(key)), configSourceFlag, value)
+	}
+
+	if len(errs) > 0 {
+		return nil, configErrors(errs)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// newConfig is the default config, with every key's source set to match
+func newConfig() *Config {
+	config := DefaultConfig()
+	config.sources = map[string]string{}
+	config.fileLayers = map[string]string{}
+	for _, field := range configFields {
+		config.sources[field.key] = configSourceDefault
+	}
+	return &config
+}
+
+// ValidateConfigFile checks the config file at path on its own, over the
+// defaults and without any other layer.
+func ValidateConfigFile(path string) error {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	config := newConfig()
+	var errs []error
+	for _, key := range sortedKeys(values) {
+		if err := config.setFrom(key, path, values[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return configErrors(errs)
+	}
+	return config.Validate()
+}
+
+// ConfigError is a problem with the value of one key
+type ConfigError struct {
+	Key string
+	// where the value came from, see Config.Source
+	Source string
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s (from %s): %v", e.Key, e.Source, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configErrors are all of a config's problems, one per line
+type configErrors []error
+
+func (e configErrors) Error() string {
+	if len(e) == 1 {
+		return "invalid config: " + e[0].Error()
+	}
+	lines := []string{"invalid config:"}
+	for _, err := range e {
+		lines = append(lines, "  "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e configErrors) Unwrap() []error {
+	return e
+}
+
+// Source is where the value of key came from: "default", the config file it
+// was read from, "env NAME" or "flag --set".
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return configSourceDefault
+}
+
+// Layer is which of the config's layers the value of key came from:
+// "default", "system file", "user file", "server_overrides.json", "env" or
+// "flag".
+func (c *Config) Layer(key string) string {
+	source := c.Source(key)
+	switch {
+	case source == configSourceDefault:
+		return configSourceDefault
+	case source == configSourceFlag:
+		return "flag"
+	case strings.HasPrefix(source, "env "):
+		return "env"
+	}
+	return c.fileLayers[source]
+}
+
+// Files are the config files that were read, lowest precedence first
+func (c *Config) Files() []string {
+	return c.files
+}
+
+// Get is the value of key, written as it would be given to --set: lists are
+// comma separated.
+func (c *Config) Get(key string) (string, error) {
+	field, ok := configFieldsByKey[strings.ToLower(key)]
+	if !ok {
+		return "", unknownConfigKey(key)
+	}
+	v := reflect.ValueOf(c).Elem().FieldByIndex(field.index)
+	if strs, ok := v.Interface().([]string); ok {
+		return strings.Join(strs, ","), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// ConfigKeys are all the config's keys, in the order of the config file
+func ConfigKeys() []string {
+	keys := make([]string, len(configFields))
+	for i, field := range configFields {
+		keys[i] = field.key
+	}
+	return keys
+}
+
+// SecretConfigKey is whether the value of key is a credential, not to be
+// printed where others may see it.
+func SecretConfigKey(key string) bool {
+	return secretConfigKeys[key]
+}
+
+// Validate checks the values make sense together, beyond being of the right
+// type.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(key string, err error) {
+		if err != nil {
+			errs = append(errs, &ConfigError{Key: key, Source: c.Source(key), Err: err})
+		}
+	}
+	nonNegative := func(key string, n int64) {
+		if n < 0 {
+			check(key, fmt.Errorf("must not be negative, got %d", n))
+		}
+	}
+
+	_, err := ParseCompression(c.Client.Compression)
+	check("client.compression", err)
+	nonNegative("client.compression_threads", int64(c.Client.CompressionThreads))
+	nonNegative("client.state_stream_interval", int64(c.Client.StateStreamInterval))
+	if c.Client.LogLevel != "" {
+		if _, err := zerolog.ParseLevel(c.Client.LogLevel); err != nil {
+			check("client.log_level", fmt.Errorf("unknown log level %q", c.Client.LogLevel))
+		}
+	}
+
+	switch c.Store.Backend {
+	case "", "cedana":
+	case "local":
+		if c.Store.LocalDir == "" {
+			check("store.local_dir", errors.New("must be set for the local store"))
+		}
+	case "s3":
+		if c.Store.S3.Endpoint == "" {
+			check("store.s3.endpoint", errors.New("must be set for the s3 store"))
+		}
+		if c.Store.S3.Bucket == "" {
+			check("store.s3.bucket", errors.New("must be set for the s3 store"))
+		}
+	case "oci":
+		if c.Store.OCI.Repository == "" {
+			check("store.oci.repository", errors.New("must be set for the oci store"))
+		}
+	default:
+		check("store.backend", fmt.Errorf("unknown store backend %q, expected cedana, local, s3 or oci", c.Store.Backend))
+	}
+	nonNegative("store.cache_max_size", c.Store.CacheMaxSize)
+	nonNegative("store.s3.part_size", c.Store.S3.PartSize)
+	nonNegative("store.s3.concurrency", int64(c.Store.S3.Concurrency))
+
+	for _, key := range c.Signing.TrustedKeys {
+		_, err := parsePublicKey(key)
+		check("signing.trusted_keys", err)
+	}
+
+	nonNegative("preemption.poll_interval", int64(c.Preemption.PollInterval))
+	nonNegative("preemption.max_parallel", int64(c.Preemption.MaxParallel))
+
+	if len(errs) > 0 {
+		return configErrors(errs)
+	}
+	return nil
+}
+
+// ChangedKeys lists the keys whose values differ between a and b
+func ChangedKeys(a, b *Config) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var keys []string
+	for _, field := range configFields {
+		fa, fb := va.FieldByIndex(field.index), vb.FieldByIndex(field.index)
+		// a missing list is the same as an empty one
+		if field.typ.Kind() == reflect.Slice && fa.Len() == 0 && fb.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			keys = append(keys, field.key)
+		}
+	}
+	return keys
+}
+
+// configField is a key of the config, e.g. "store.s3.bucket", and the field
+// it's read into.
+type configField struct {
+	key string
+	// CEDANA_<KEY>, e.g. CEDANA_STORE_S3_BUCKET
+	env   string
+	index []int
+	typ   reflect.Type
+}
+
+var configFields, configSections = configSchema(reflect.TypeOf(Config{}), "", nil)
+
+var configFieldsByKey = func() map[string]configField {
+	fields := map[string]configField{}
+	for _, field := range configFields {
+		fields[field.key] = field
+	}
+	return fields
+}()
+
+// configSchema lists the keys of the struct typ, and the sections they're in,
+// after their json names.
+func configSchema(typ reflect.Type, prefix string, index []int) ([]configField, map[string]bool) {
+	var fields []configField
+	sections := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		idx := append(append([]int{}, index...), i)
+		if f.Type.Kind() == reflect.Struct {
+			sections[key] = true
+			sub, subSections := configSchema(f.Type, key+".", idx)
+			fields = append(fields, sub...)
+			for section := range subSections {
+				sections[section] = true
+			}
+			continue
+		}
+		env := "CEDANA_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		fields = append(fields, configField{key: key, env: env, index: idx, typ: f.Type})
 	}
+	return fields, sections
+}
 
-	u, err := user.Lookup(username)
+// readConfigFile reads a JSON config file into its keys' values
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var root map[string]interface{}
+	if err := d.Decode(&root); err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	values := map[string]interface{}{}
+	flattenConfig("", root, values)
+	return values, nil
+}
 
-	homedir := u.HomeDir
-	viper.AddConfigPath(filepath.Join(homedir, ".cedana/"))
-	viper.SetConfigType("json")
-	viper.SetConfigName("client_config")
+// flattenConfig collects the values in m under their dotted keys; what isn't
+// a section is left for set to check.
+func flattenConfig(prefix string, m map[string]interface{}, values map[string]interface{}) {
+	for name, value := range m {
+		key := prefix + strings.ToLower(name)
+		if sub, ok := value.(map[string]interface{}); ok && configSections[key] {
+			flattenConfig(key+".", sub, values)
+			continue
+		}
+		values[key] = value
+	}
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unknownConfigKey(key string) error {
+	if configSections[strings.ToLower(key)] {
+		return fmt.Errorf("%s is a section of the config, not a key", key)
+	}
+	return fmt.Errorf("unknown config key %q", key)
+}
+
+// setFrom sets key to value, recording source as where it came from
+func (c *Config) setFrom(key, source string, value interface{}) error {
+	if err := c.set(key, value); err != nil {
+		return &ConfigError{Key: key, Source: source, Err: err}
+	}
+	c.sources[key] = source
+	return nil
+}
+
+// set sets key to value, as decoded from JSON or as a string from the
+// environment or a flag.
+func (c *Config) set(key string, value interface{}) error {
+	field, ok := configFieldsByKey[key]
+	if !ok {
+		switch {
+		case legacyConfigKeys[key]:
+			return nil
+		case configSections[key]:
+			return errors.New("is a section, expected an object")
+		}
+		return errors.New("unknown key")
+	}
+	v := reflect.ValueOf(c).Elem().FieldByIndex(field.index)
 
-	configFilePath := filepath.Join(homedir, ".cedana", "client_config.json")
+	if s, ok := value.(string); ok && field.typ.Kind() != reflect.String {
+		return setConfigString(v, s)
+	}
+	switch field.typ.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %s", jsonType(value))
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected a boolean, got %s", jsonType(value))
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected an integer, got %s", jsonType(value))
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil || v.OverflowInt(i) {
+			return fmt.Errorf("expected an integer, got %s", n)
+		}
+		v.SetInt(i)
+	case reflect.Slice:
+		// as json.Marshal writes empty lists
+		if value == nil {
+			v.Set(reflect.Zero(field.typ))
+			return nil
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected a list of strings, got %s", jsonType(value))
+		}
+		strs := make([]string, len(list))
+		for i, item := range list {
+			if strs[i], ok = item.(string); !ok {
+				return fmt.Errorf("expected a list of strings, got %s in it", jsonType(item))
+			}
+		}
+		v.Set(reflect.ValueOf(strs))
+	}
+	return nil
+}
 
-	_, err = os.Stat(configFilePath)
-	if os.IsNotExist(err) {
-		_, err = os.Stat(filepath.Join(homedir, ".cedana"))
-		if os.IsNotExist(err) {
-			err = os.MkdirAll(filepath.Join(homedir, ".cedana"), 0755)
-			if err != nil {
-				panic(fmt.Errorf("error creating .cedana folder: %v", err))
+// setConfigString parses s for a key that isn't a string: booleans as
+// strconv.ParseBool does, lists as comma separated values.
+func setConfigString(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("expected a boolean, got %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v.OverflowInt(i) {
+			return fmt.Errorf("expected an integer, got %q", s)
+		}
+		v.SetInt(i)
+	case reflect.Slice:
+		var strs []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				strs = append(strs, item)
 			}
 		}
+		v.Set(reflect.ValueOf(strs))
+	}
+	return nil
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case []interface{}:
+		return "a list"
+	}
+	return "an object"
+}
+
+// userConfigDir is ~/.cedana of whoever is running cedana. That's the user
+// behind sudo if it's run with it, as the daemon has to run as root. It's ""
+// if there's no home directory to be found.
+func userConfigDir() string {
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		if u, err := user.Lookup(name); err == nil {
+			return filepath.Join(u.HomeDir, ".cedana")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cedana")
+}
+
+// UserConfigPath is where the user's config file is read from, unless
+// ConfigOptions say otherwise.
+func UserConfigPath() string {
+	if configOptions.UserFile != "" {
+		return configOptions.UserFile
+	}
+	if dir := userConfigDir(); dir != "" {
+		return filepath.Join(dir, "client_config.json")
+	}
+	return ""
+}
+
+// SetUserConfig sets key to value, given as it would be to --set, in the
+// user's config file, creating it if need be, and returns the file's path.
+// The file is only written if the config it makes, with every other layer,
+// is valid.
+func SetUserConfig(key, value string) (string, error) {
+	path := UserConfigPath()
+	if path == "" {
+		return "", errors.New("no user config file, as there's no home directory")
+	}
+	field, ok := configFieldsByKey[strings.ToLower(key)]
+	if !ok {
+		return path, unknownConfigKey(key)
+	}
+	var parsed Config
+	if err := parsed.set(field.key, value); err != nil {
+		return path, &ConfigError{Key: field.key, Source: "the command line", Err: err}
+	}
+	// as it would be decoded from the file
+	data, err := json.Marshal(reflect.ValueOf(&parsed).Elem().FieldByIndex(field.index).Interface())
+	if err != nil {
+		return path, err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var typed interface{}
+	if err := d.Decode(&typed); err != nil {
+		return path, err
+	}
 
-		err = os.WriteFile(configFilePath, []byte(GenSampleConfig()), 0664)
+	root := map[string]interface{}{}
+	info, err := os.Stat(path)
+	if err == nil {
+		data, err := os.ReadFile(path)
 		if err != nil {
-			panic(fmt.Errorf("error writing sample to config file: %v", err))
+			return path, err
+		}
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		if err := d.Decode(&root); err != nil {
+			return path, fmt.Errorf("reading config %s: %w", path, err)
 		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return path, err
 	}
 
-	viper.SetConfigFile(configFilePath)
-	viper.AutomaticEnv()
+	// keep the rest of the file as it is, including how its keys are cased
+	m := root
+	parts := strings.Split(field.key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		name := configFileName(m, part)
+		sub, ok := m[name].(map[string]interface{})
+		if !ok {
+			sub = map[string]interface{}{}
+			m[name] = sub
+		}
+		m = sub
+	}
+	m[configFileName(m, parts[len(parts)-1])] = typed
 
-	var config Config
-	err = viper.ReadInConfig()
+	opts := configOptions
+	opts.UserFile = path
+	_, err = loadConfig(opts, func(file string) (map[string]interface{}, error) {
+		if file != path {
+			return readConfigFile(file)
+		}
+		values := map[string]interface{}{}
+		flattenConfig("", root, values)
+		return values, nil
+	})
 	if err != nil {
-		panic(fmt.Errorf("error: %v, have you run bootstrap?, ", err))
+		return path, err
 	}
 
-	if err := viper.Unmarshal(&config); err != nil {
-		fmt.Println(err)
-		return nil, err
+	data, err = json.MarshalIndent(root, "", "\t")
+	if err != nil {
+		return path, err
 	}
-	so, err := LoadOverrides(filepath.Join(homedir, ".cedana"))
+	return path, writeConfigFile(path, append(data, '\n'), info)
+}
 
-	// override setting is ugly, need to abstract this away somehow
-	if err == nil && so != nil {
-		viper.Set("shared_storage.dump_storage_dir", so.SharedStorage.DumpStorageDir)
-		viper.Set("client.task", so.Client.Task)
+// configFileName is the name name goes by in m, which may be cased
+// differently as keys are read case insensitively.
+func configFileName(m map[string]interface{}, name string) string {
+	for existing := range m {
+		if strings.EqualFold(existing, name) {
+			return existing
+		}
 	}
+	return name
+}
 
-	viper.WriteConfig()
-	return &config, nil
+// writeConfigFile replaces the config file at path with data, keeping the
+// mode and owner of the one it replaces, if any, as described by info.
+func writeConfigFile(path string, data []byte, info os.FileInfo) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".config-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	mode := os.FileMode(0o664)
+	if info != nil {
+		mode = info.Mode().Perm()
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			// best effort, it's the file of the user behind sudo
+			f.Chown(int(stat.Uid), int(stat.Gid))
+		}
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
 }
 
+// GenSampleConfig is a config file with every key, set to its default
 func GenSampleConfig() string {
-	return `{
-	"client": {
-		"process_name": "",
-		"leave_running": false 
-	},
-	"shared_storage": {
-		"dump_storage_dir": "/tmp"
-	},
-	"connection": {
-		"cedana_url": "0.0.0.0",
-		"cedana_user": "random-user",
-		"cedana_auth_token": "random-token"
-	}
-}`
+	data, _ := json.MarshalIndent(DefaultConfig(), "", "\t")
+	return string(data)
 }
@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig_Layers(t *testing.T) {
+	system := writeConfig(t, `{
+		"client": {"compression": "lz4", "compression_threads": 2},
+		"store": {"backend": "local", "local_dir": "/mnt/system"}
+	}`)
+	user := writeConfig(t, `{
+		"client": {"compression": "zstd:3"},
+		"store": {"local_dir": "/mnt/user"},
+		"signing": {"trusted_keys": []}
+	}`)
+	overrides := filepath.Join(filepath.Dir(user), "server_overrides.json")
+	os.WriteFile(overrides, []byte(`{"shared_storage": {"dump_storage_dir": "/mnt/dumps"}}`), 0o644)
+
+	cfg, err := LoadConfig(ConfigOptions{
+		SystemFile: system,
+		UserFile:   user,
+		Env: []string{
+			"CEDANA_STORE_LOCAL_DIR=/mnt/env",
+			"CEDANA_GPU_ENABLED=true",
+			"CEDANA_REMOTE=true",
+			"CEDANA_CLIENT_REMOTE=false",
+			"IS_K8S=1",
+			"CEDANA_PREEMPTION_PRIORITY_JOBS=a, b",
+			"CEDANA_CHECKPOINT_KEY=not a config key",
+			"CEDANA_OTEL_ENABLED=",
+		},
+		Set: []string{"client.compression_threads=4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{
+		"client.compression":              user,
+		"client.compression_threads":      "flag --set",
+		"store.backend":                   system,
+		"store.local_dir":                 "env CEDANA_STORE_LOCAL_DIR",
+		"shared_storage.dump_storage_dir": overrides,
+		"gpu.enabled":                     "env CEDANA_GPU_ENABLED",
+		"client.remote":                   "env CEDANA_CLIENT_REMOTE",
+		"kubernetes.enabled":              "env IS_K8S",
+		"otel.enabled":                    "default",
+		"store.cache_dir":                 "default",
+	} {
+		if got := cfg.Source(key); got != want {
+			t.Errorf("%s: expected it to come from %s, got %s", key, want, got)
+		}
+	}
+	if cfg.Client.Compression != "zstd:3" || cfg.Client.CompressionThreads != 4 || cfg.Store.Backend != "local" ||
+		cfg.Store.LocalDir != "/mnt/env" || cfg.SharedStorage.DumpStorageDir != "/mnt/dumps" {
+		t.Errorf("layers not applied in order: %+v", cfg)
+	}
+	if !cfg.GPU.Enabled || cfg.Client.Remote || !cfg.Kubernetes.Enabled || cfg.Otel.Enabled {
+		t.Errorf("env toggles not applied: %+v %+v %+v %+v", cfg.GPU, cfg.Client, cfg.Kubernetes, cfg.Otel)
+	}
+	if strings.Join(cfg.Preemption.PriorityJobs, "|") != "a|b" {
+		t.Errorf("expected a list from the env, got %q", cfg.Preemption.PriorityJobs)
+	}
+	if cfg.Store.CacheMaxSize != defaultCacheMaxSize {
+		t.Errorf("expected the default cache size, got %d", cfg.Store.CacheMaxSize)
+	}
+	if len(cfg.Files()) != 3 {
+		t.Errorf("expected all 3 files to be read, got %v", cfg.Files())
+	}
+
+	for key, want := range map[string]string{
+		"store.backend":                   "system file",
+		"client.compression":              "user file",
+		"shared_storage.dump_storage_dir": "server_overrides.json",
+		"store.local_dir":                 "env",
+		"client.compression_threads":      "flag",
+		"store.cache_dir":                 "default",
+	} {
+		if got := cfg.Layer(key); got != want {
+			t.Errorf("%s: expected it to come from the %s layer, got %s", key, want, got)
+		}
+	}
+	for key, want := range map[string]string{
+		"client.compression":         "zstd:3",
+		"CLIENT.COMPRESSION_THREADS": "4",
+		"gpu.enabled":                "true",
+		"preemption.priority_jobs":   "a,b",
+	} {
+		if got, err := cfg.Get(key); err != nil || got != want {
+			t.Errorf("%s: expected %q, got %q (%v)", key, want, got, err)
+		}
+	}
+	if _, err := cfg.Get("store"); err == nil {
+		t.Error("expected a section not to have a value")
+	}
+}
+
+func TestLoadConfig_Missing(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadConfig(ConfigOptions{
+		SystemFile: filepath.Join(dir, "system.json"),
+		UserFile:   filepath.Join(dir, "user.json"),
+		Env:        []string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Store.Backend != "cedana" || len(cfg.Files()) != 0 {
+		t.Errorf("expected the defaults, got %+v", cfg)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	cases := []struct {
+		file string
+		env  []string
+		set  []string
+		// in the error, along with the source
+		key, source string
+	}{
+		{file: `{"client": {"compresion": "lz4"}}`, key: "client.compresion", source: "file"},
+		{file: `{"client": {"leave_running": 1}}`, key: "client.leave_running", source: "file"},
+		{file: `{"store": "s3"}`, key: "store", source: "file"},
+		{file: `{"store": {"cache_max_size": 1.5}}`, key: "store.cache_max_size", source: "file"},
+		{file: `{"signing": {"trusted_keys": [1]}}`, key: "signing.trusted_keys", source: "file"},
+		{file: `{"signing": {"trusted_keys": ["not a key"]}}`, key: "signing.trusted_keys", source: "file"},
+		{file: `{"client": {"compression": "brotli"}}`, key: "client.compression", source: "file"},
+		{file: `{"store": {"backend": "ftp"}}`, key: "store.backend", source: "file"},
+		{file: `{"store": {"backend": "s3"}}`, key: "store.s3.endpoint", source: "default"},
+		{env: []string{"CEDANA_GPU_ENABLED=yes please"}, key: "gpu.enabled", source: "env CEDANA_GPU_ENABLED"},
+		{env: []string{"CEDANA_CLIENT_COMPRESSION_THREADS=-1"}, key: "client.compression_threads", source: "env CEDANA_CLIENT_COMPRESSION_THREADS"},
+		{set: []string{"client.nope=1"}, key: "client.nope", source: "flag --set"},
+		{set: []string{"client.compression"}, key: "client.compression", source: "flag --set"},
+	}
+	for _, c := range cases {
+		opts := ConfigOptions{SystemFile: filepath.Join(t.TempDir(), "none"), Env: c.env, Set: c.set}
+		if opts.Env == nil {
+			opts.Env = []string{}
+		}
+		if c.file != "" {
+			opts.UserFile = writeConfig(t, c.file)
+		} else {
+			opts.UserFile = filepath.Join(t.TempDir(), "none")
+		}
+
+		_, err := LoadConfig(opts)
+		var cerr *ConfigError
+		if !errors.As(err, &cerr) {
+			t.Errorf("%s: expected a ConfigError, got %v", c.key, err)
+			continue
+		}
+		source := c.source
+		if source == "file" {
+			source = opts.UserFile
+		}
+		if cerr.Key != c.key || cerr.Source != source {
+			t.Errorf("%s: expected an error about it from %s, got %v", c.key, source, err)
+		}
+	}
+
+	if _, err := LoadConfig(ConfigOptions{UserFile: writeConfig(t, `{"client": `), Env: []string{}}); err == nil {
+		t.Error("expected malformed JSON to be an error")
+	}
+}
+
+func TestGenSampleConfig(t *testing.T) {
+	cfg, err := LoadConfig(ConfigOptions{
+		SystemFile: filepath.Join(t.TempDir(), "none"),
+		UserFile:   writeConfig(t, GenSampleConfig()),
+		Env:        []string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Source("store.backend") != cfg.Files()[0] {
+		t.Errorf("expected every key to be in the sample")
+	}
+
+	// what old versions wrote is still read
+	legacy := `{"client": {"process_name": "", "leave_running": false}, "connection": {"cedana_user": "random-user"}}`
+	if _, err := LoadConfig(ConfigOptions{UserFile: writeConfig(t, legacy), Env: []string{}}); err != nil {
+		t.Errorf("expected an old sample config to load, got %v", err)
+	}
+}
+
+func TestSetUserConfig(t *testing.T) {
+	user := writeConfig(t, `{"Client": {"Compression": "lz4"}, "connection": {"cedana_user": "legacy"}}`)
+	SetConfigOptions(ConfigOptions{SystemFile: filepath.Join(t.TempDir(), "none"), UserFile: user, Env: []string{}})
+	t.Cleanup(func() { SetConfigOptions(ConfigOptions{}) })
+
+	for _, kv := range [][2]string{
+		{"client.compression", "zstd"},
+		{"store.local_dir", "/mnt/store"},
+		{"store.backend", "local"},
+		{"preemption.priority_jobs", "a, b"},
+		{"client.compression_threads", "2"},
+	} {
+		if path, err := SetUserConfig(kv[0], kv[1]); err != nil || path != user {
+			t.Fatalf("%s: %s %v", kv[0], path, err)
+		}
+	}
+	cfg, err := InitConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Client.Compression != "zstd" || cfg.Store.Backend != "local" || cfg.Client.CompressionThreads != 2 ||
+		strings.Join(cfg.Preemption.PriorityJobs, "|") != "a|b" {
+		t.Errorf("expected the values set, got %+v", cfg)
+	}
+	data, _ := os.ReadFile(user)
+	if !strings.Contains(string(data), `"Compression": "zstd"`) || !strings.Contains(string(data), "legacy") {
+		t.Errorf("expected the rest of the file to be kept as it was, got %s", data)
+	}
+
+	for _, kv := range [][2]string{
+		{"client.compression_threads", "many"},
+		{"store.backend", "s3"},
+		{"client.nope", "1"},
+		{"store", "local"},
+	} {
+		if _, err := SetUserConfig(kv[0], kv[1]); err == nil {
+			t.Errorf("%s: expected %q to be refused", kv[0], kv[1])
+		}
+	}
+	if after, _ := os.ReadFile(user); string(after) != string(data) {
+		t.Errorf("expected a refused value to leave the file alone, got %s", after)
+	}
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	if err := ValidateConfigFile(writeConfig(t, `{"store": {"backend": "local", "local_dir": "/mnt"}}`)); err != nil {
+		t.Error(err)
+	}
+	path := writeConfig(t, `{"store": {"backend": "local"}, "client": {"compression": 1}}`)
+	var cerr *ConfigError
+	if err := ValidateConfigFile(path); !errors.As(err, &cerr) || cerr.Key != "client.compression" || cerr.Source != path {
+		t.Errorf("expected an error about client.compression, got %v", err)
+	}
+	if err := ValidateConfigFile(filepath.Join(t.TempDir(), "none")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing file to be an error, got %v", err)
+	}
+}
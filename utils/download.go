@@ -0,0 +1,450 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheDir     = "/var/cache/cedana/checkpoints"
+	defaultCacheMaxSize = 20 << 30
+
+	defaultDownloadPartSize    = 64 << 20
+	defaultDownloadConcurrency = 4
+)
+
+// CheckpointCache keeps downloaded checkpoints as <dir>/<id>.tar, so restoring
+// the same checkpoint again doesn't download it again. Once the cache grows
+// past its size limit the least recently used checkpoints are evicted.
+type CheckpointCache struct {
+	dir     string
+	maxSize int64
+}
+
+func NewCheckpointCache(cfg StoreConfig) (*CheckpointCache, error) {
+	cache := &CheckpointCache{dir: cfg.CacheDir, maxSize: cfg.CacheMaxSize}
+	if cache.dir == "" {
+		cache.dir = defaultCacheDir
+	}
+	if cache.maxSize <= 0 {
+		cache.maxSize = defaultCacheMaxSize
+	}
+	if err := os.MkdirAll(cache.dir, 0o755); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// stores are created per request, so downloads of the same checkpoint are
+// serialized here rather than per CheckpointCache
+var cacheLocks = struct {
+	sync.Mutex
+	m map[string]chan struct{}
+}{m: map[string]chan struct{}{}}
+
+func lockCachePath(ctx context.Context, path string) (func(), error) {
+	cacheLocks.Lock()
+	lock, ok := cacheLocks.m[path]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		cacheLocks.m[path] = lock
+	}
+	cacheLocks.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pins counts the callers still using each cached checkpoint, by path.
+// Pinned checkpoints, and the partial downloads of them, are never evicted.
+var cachePins = struct {
+	sync.Mutex
+	m map[string]int
+}{m: map[string]int{}}
+
+// pinCachePath pins path until the returned func is called, which may be
+// more than once.
+func pinCachePath(path string) func() {
+	cachePins.Lock()
+	cachePins.m[path]++
+	cachePins.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cachePins.Lock()
+			defer cachePins.Unlock()
+			if cachePins.m[path]--; cachePins.m[path] <= 0 {
+				delete(cachePins.m, path)
+			}
+		})
+	}
+}
+
+func cachePathPinned(path string) bool {
+	cachePins.Lock()
+	defer cachePins.Unlock()
+	return cachePins.m[path] > 0
+}
+
+// tryLockCachePath is lockCachePath without waiting, for eviction to skip a
+// checkpoint that is being fetched
+func tryLockCachePath(path string) (func(), bool) {
+	cacheLocks.Lock()
+	lock, ok := cacheLocks.m[path]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		cacheLocks.m[path] = lock
+	}
+	cacheLocks.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, true
+	default:
+		return nil, false
+	}
+}
+
+// Get returns the path of cached checkpoint cid. If it isn't cached, download
+// is called to fetch it into partial first; a failed download leaves partial
+// behind for the next Get to resume from. A signed manifest download puts at
+// <partial>.manifest ends up next to the checkpoint, at <path>.manifest.
+//
+// The checkpoint stays pinned in the cache, safe from eviction, until release
+// is called.
+func (c *CheckpointCache) Get(ctx context.Context, cid string, download func(ctx context.Context, partial string) error) (_ string, release func(), err error) {
+	if cid == "" || cid == "." || cid == ".." || strings.ContainsAny(cid, `/\`) {
+		return "", nil, fmt.Errorf("invalid checkpoint id %q", cid)
+	}
+
+	path := filepath.Join(c.dir, cid+".tar")
+	unlock, err := lockCachePath(ctx, path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+
+	unpin := pinCachePath(path)
+	defer func() {
+		if err != nil {
+			unpin()
+		}
+	}()
+
+	if _, err := os.Stat(path); err == nil {
+		// mtime is what eviction goes by
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return path, unpin, nil
+	}
+
+	partial := path + ".partial"
+	if err := download(ctx, partial); err != nil {
+		return "", nil, err
+	}
+	// the manifest goes first, so a checkpoint is never cached without it
+	if err := os.Rename(partial+manifestSuffix, path+manifestSuffix); os.IsNotExist(err) {
+		os.Remove(path + manifestSuffix)
+	} else if err != nil {
+		return "", nil, err
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return "", nil, err
+	}
+
+	c.evict()
+	return path, unpin, nil
+}
+
+// evict removes least recently used checkpoints until the cache fits in its
+// size limit. Partial downloads count towards the limit and are evicted like
+// any other checkpoint, unless they're pinned or being fetched.
+func (c *CheckpointCache) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	var cached []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".tar") && !strings.HasSuffix(entry.Name(), ".tar.partial") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		cached = append(cached, info)
+		total += info.Size()
+	}
+
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].ModTime().Before(cached[j].ModTime())
+	})
+	for _, info := range cached {
+		if total <= c.maxSize {
+			break
+		}
+		path := filepath.Join(c.dir, info.Name())
+		if c.evictPath(strings.TrimSuffix(path, ".partial"), path) {
+			total -= info.Size()
+		}
+	}
+	return nil
+}
+
+// evictPath removes file, the checkpoint at path or its partial download,
+// unless the checkpoint is pinned or being fetched
+func (c *CheckpointCache) evictPath(path, file string) bool {
+	unlock, ok := tryLockCachePath(path)
+	if !ok {
+		return false
+	}
+	defer unlock()
+
+	if cachePathPinned(path) {
+		return false
+	}
+	if err := os.Remove(file); err != nil {
+		return false
+	}
+	os.Remove(file + manifestSuffix)
+	if file != path {
+		os.Remove(file + ".json")
+	}
+	return true
+}
+
+// offsetWriter writes sequentially into f from off onwards
+type offsetWriter struct {
+	f   *os.File
+	off int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.f.WriteAt(p, w.off)
+	w.off += int64(n)
+	return n, err
+}
+
+// byteRanges splits size bytes into inclusive [start, end] ranges of at most
+// partSize bytes.
+func byteRanges(size, partSize int64) [][2]int64 {
+	var ranges [][2]int64
+	for start := int64(0); start < size; start += partSize {
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		ranges = append(ranges, [2]int64{start, end})
+	}
+	return ranges
+}
+
+// downloadState records which ranges of a partial download are on disk, so
+// an interrupted download resumes instead of starting over.
+type downloadState struct {
+	Size int64 `json:"size"`
+	// identifies the exact object being fetched, e.g. its ETag; ranges of a
+	// different version are never stitched together
+	Version  string `json:"version"`
+	PartSize int64  `json:"part_size"`
+	Done     []int  `json:"done"`
+
+	path string
+	mu   sync.Mutex
+	done map[int]bool
+}
+
+func loadDownloadState(partial string, size int64, version string, partSize int64) *downloadState {
+	state := &downloadState{
+		Size:     size,
+		Version:  version,
+		PartSize: partSize,
+		path:     partial + ".json",
+		done:     map[int]bool{},
+	}
+
+	data, err := os.ReadFile(state.path)
+	if err != nil {
+		return state
+	}
+	var saved downloadState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return state
+	}
+	if saved.Size != size || saved.Version != version || saved.PartSize != partSize {
+		return state
+	}
+	for _, i := range saved.Done {
+		state.done[i] = true
+	}
+	return state
+}
+
+func (d *downloadState) isDone(i int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.done[i]
+}
+
+func (d *downloadState) markDone(i int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.done[i] = true
+
+	d.Done = d.Done[:0]
+	for i := range d.done {
+		d.Done = append(d.Done, i)
+	}
+	sort.Ints(d.Done)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(d.path, data)
+}
+
+// rangeFetcher returns the bytes start through end, inclusive, of an object
+type rangeFetcher func(ctx context.Context, start, end int64) (io.ReadCloser, error)
+
+// rangedDownload fills partial with size bytes of the object identified by
+// version, fetching concurrency ranges of partSize at a time and retrying
+// each with backoff. Ranges finished by an earlier, interrupted call are
+// skipped.
+func rangedDownload(ctx context.Context, partial string, size int64, version string, partSize int64, concurrency int, fetch rangeFetcher) error {
+	state := loadDownloadState(partial, size, version, partSize)
+
+	f, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if len(state.done) == 0 {
+		// nothing usable from before, don't trust whatever is in the file
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+	}
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+
+	ranges := byteRanges(size, partSize)
+	queue := make(chan int, len(ranges))
+	for i := range ranges {
+		if !state.isDone(i) {
+			queue <- i
+		}
+	}
+	close(queue)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var fetchErr error
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				r := ranges[i]
+				err := withRetries(ctx, maxPartAttempts, func() error {
+					return fetchRange(ctx, f, r, fetch)
+				})
+				if err == nil {
+					// losing the state only costs a re-download of this range
+					state.markDone(i)
+					continue
+				}
+				errOnce.Do(func() {
+					fetchErr = fmt.Errorf("bytes %d-%d: %w", r[0], r[1], err)
+					cancel()
+				})
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetchErr != nil {
+		return fetchErr
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	os.Remove(state.path)
+	return nil
+}
+
+func fetchRange(ctx context.Context, f *os.File, r [2]int64, fetch rangeFetcher) error {
+	body, err := fetch(ctx, r[0], r[1])
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	want := r[1] - r[0] + 1
+	n, err := io.Copy(&offsetWriter{f: f, off: r[0]}, io.LimitReader(body, want))
+	if err != nil {
+		return err
+	}
+	if n != want {
+		return fmt.Errorf("short read: got %d of %d bytes", n, want)
+	}
+	return nil
+}
+
+func fileSHA256(ctx context.Context, filepath string) (string, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, &ctxReader{ctx: ctx, r: f}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifyChecksum checks the file at path against an expected hex sha256,
+// optionally prefixed "sha256:". On a mismatch the file is removed, since
+// resuming a corrupt download would only reproduce it.
+func verifyChecksum(ctx context.Context, path, expected string) error {
+	expected = strings.TrimPrefix(expected, "sha256:")
+	got, err := fileSHA256(ctx, path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(got, expected) {
+		os.Remove(path)
+		os.Remove(path + ".json")
+		return fmt.Errorf("checkpoint failed verification: sha256 %s, expected %s", got, expected)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestByteRanges(t *testing.T) {
+	tests := []struct {
+		size, partSize int64
+		want           [][2]int64
+	}{
+		{0, 10, nil},
+		{5, 10, [][2]int64{{0, 4}}},
+		{10, 10, [][2]int64{{0, 9}}},
+		{25, 10, [][2]int64{{0, 9}, {10, 19}, {20, 24}}},
+	}
+	for _, tt := range tests {
+		if got := byteRanges(tt.size, tt.partSize); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("byteRanges(%d, %d) = %v, want %v", tt.size, tt.partSize, got, tt.want)
+		}
+	}
+}
+
+func TestCheckpointCache(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCheckpointCache(StoreConfig{CacheDir: t.TempDir(), CacheMaxSize: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	downloads := 0
+	get := func(cid string) string {
+		path, release, err := cache.Get(ctx, cid, func(ctx context.Context, partial string) error {
+			downloads++
+			os.WriteFile(partial+manifestSuffix, []byte("{}"), 0o644)
+			return os.WriteFile(partial, bytes.Repeat([]byte{'x'}, 10), 0o644)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+		return path
+	}
+
+	a := get("a")
+	if _, err := os.Stat(a + manifestSuffix); err != nil {
+		t.Errorf("expected the manifest to be cached with the checkpoint: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(a, old, old)
+	b := get("b")
+	os.Chtimes(b, old.Add(time.Minute), old.Add(time.Minute))
+
+	// a hit is served from disk and counts as a use
+	if get("a"); downloads != 2 {
+		t.Fatalf("expected a cached checkpoint not to be downloaded again, got %d downloads", downloads)
+	}
+
+	// 30 bytes is over the limit, so the least recently used goes
+	get("c")
+	if _, err := os.Stat(b); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be evicted", b)
+	}
+	if _, err := os.Stat(b + manifestSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected the manifest of %s to go with it", b)
+	}
+	if _, err := os.Stat(a); err != nil {
+		t.Errorf("expected recently used %s to be kept: %v", a, err)
+	}
+
+	// a checkpoint still in use isn't evicted, however long ago it was fetched
+	pinned, release, err := cache.Get(ctx, "a", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(pinned, old, old)
+	c := filepath.Join(cache.dir, "c.tar")
+	get("d")
+	if _, err := os.Stat(pinned); err != nil {
+		t.Errorf("expected pinned %s to be kept: %v", pinned, err)
+	}
+	if _, err := os.Stat(c); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be evicted in place of the pinned checkpoint", c)
+	}
+	release()
+
+	// partial downloads take up space too, and are evicted once abandoned
+	partial := filepath.Join(cache.dir, "e.tar.partial")
+	if err := os.WriteFile(partial, bytes.Repeat([]byte{'x'}, 20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(partial+".json", []byte("{}"), 0o644)
+	os.Chtimes(partial, old.Add(-time.Hour), old.Add(-time.Hour))
+	get("f")
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Errorf("expected abandoned %s to be evicted", partial)
+	}
+	if _, err := os.Stat(partial + ".json"); !os.IsNotExist(err) {
+		t.Errorf("expected the download state of %s to go with it", partial)
+	}
+
+	if _, _, err := cache.Get(ctx, "../escape", nil); err == nil {
+		t.Error("expected a path as checkpoint id to be rejected")
+	}
+}
+
+func TestRangedDownload_Resumes(t *testing.T) {
+	backoff := partRetryBackoff
+	partRetryBackoff = time.Millisecond
+	defer func() { partRetryBackoff = backoff }()
+
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	partial := filepath.Join(t.TempDir(), "checkpoint.tar.partial")
+
+	var mu sync.Mutex
+	fetched := map[int64]int{}
+	broken := true
+	fetch := func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetched[start]++
+		if start == 50 && broken {
+			return nil, &httpStatusError{Code: 403, Status: "403 Forbidden"}
+		}
+		return io.NopCloser(bytes.NewReader(data[start : end+1])), nil
+	}
+
+	err := rangedDownload(context.Background(), partial, int64(len(data)), "v1", 10, 1, fetch)
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected the download to fail on the 403, got %v", err)
+	}
+
+	mu.Lock()
+	before := map[int64]int{}
+	for start, n := range fetched {
+		before[start] = n
+	}
+	broken = false
+	mu.Unlock()
+
+	if err := rangedDownload(context.Background(), partial, int64(len(data)), "v1", 10, 3, fetch); err != nil {
+		t.Fatal(err)
+	}
+	for start := int64(0); start < 50; start += 10 {
+		if fetched[start] != before[start] {
+			t.Errorf("range at %d was fetched again after it had completed", start)
+		}
+	}
+	got, _ := os.ReadFile(partial)
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+	if _, err := os.Stat(partial + ".json"); !os.IsNotExist(err) {
+		t.Error("expected download state to be removed once complete")
+	}
+
+	// a different version starts from scratch
+	if err := rangedDownload(context.Background(), partial, int64(len(data)), "v2", 10, 3, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if fetched[0] != before[0]+1 {
+		t.Errorf("expected a new version to be fetched in full")
+	}
+}
+
+func TestCheckpointCache_Cancelled(t *testing.T) {
+	cache, err := NewCheckpointCache(StoreConfig{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := cache.Get(ctx, "a", func(ctx context.Context, partial string) error {
+			if err := os.WriteFile(partial, []byte("half"), 0o644); err != nil {
+				return err
+			}
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		done <- err
+	}()
+
+	<-started
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a cancelled download to stop with context.Canceled, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("download didn't stop when its context was cancelled")
+	}
+
+	if _, err := os.Stat(filepath.Join(cache.dir, "a.tar")); !os.IsNotExist(err) {
+		t.Errorf("expected a cancelled download not to be cached, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cache.dir, "a.tar.partial")); err != nil {
+		t.Errorf("expected the partial download to be kept to resume from: %v", err)
+	}
+
+	// a Get that's cancelled while waiting its turn gives up too
+	unlock, err := lockCachePath(context.Background(), filepath.Join(cache.dir, "a.tar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	waiting, cancelWaiting := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelWaiting()
+	if _, _, err := cache.Get(waiting, "a", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a Get waiting on the lock to give up, got %v", err)
+	}
+}
@@ -0,0 +1,439 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Encrypted archives are AES-256-GCM, sealed in records cut on the
+// boundaries ChunkedStore would cut the plaintext at. Each record's nonce is
+// random, unless the archive is deterministic, see NewEncryptWriter. A
+// trailer MACs the records' nonces and tags in order, so they can't be
+// reordered, dropped or truncated without decryption failing. Layout:
+//
+//	magic "CEDANAENC" | version | flags | key id length (uint16) | key id |
+//	records: plaintext length (uint32) | nonce (12 bytes) | sealed plaintext |
+//	trailer: zero length (uint32) | HMAC-SHA256 of the nonces and tags
+//
+// The whole header is authenticated as additional data of every record, and
+// MACed by the trailer.
+const (
+	EncryptionAlgorithm = "AES-256-GCM"
+	// deterministic archives, whose nonces are a MAC of the plaintext
+	EncryptionAlgorithmDeterministic = "AES-256-GCM-SYNTHETIC-NONCE"
+
+	encryptionMagic   = "CEDANAENC"
+	encryptionVersion = 1
+	encDeterministic  = 1 << 0
+	encNonceLen       = 12
+	encTagLen         = 16
+	encTrailerLen     = sha256.Size
+
+	defaultKeyEnv = "CEDANA_CHECKPOINT_KEY"
+)
+
+var ErrNotEncrypted = errors.New("not an encrypted checkpoint")
+
+// KeyProvider supplies checkpoint encryption keys. Keys are per job; the key
+// ID recorded with a checkpoint must be enough to get its key back.
+type KeyProvider interface {
+	// JobKey returns the key to encrypt jobID's checkpoints with, and its ID
+	JobKey(ctx context.Context, jobID string) (keyID string, key []byte, err error)
+	// Key returns the key with the given ID, for decryption
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+var keyProviders = struct {
+	sync.Mutex
+	m map[string]func(cfg Encryption) (KeyProvider, error)
+}{m: map[string]func(cfg Encryption) (KeyProvider, error){
+	"file": func(cfg Encryption) (KeyProvider, error) {
+		data, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading encryption key: %w", err)
+		}
+		return newMasterKeyProvider(data)
+	},
+	"env": func(cfg Encryption) (KeyProvider, error) {
+		env := cfg.KeyEnv
+		if env == "" {
+			env = defaultKeyEnv
+		}
+		value := os.Getenv(env)
+		if value == "" {
+			return nil, fmt.Errorf("encryption key variable %s is not set", env)
+		}
+		return newMasterKeyProvider([]byte(value))
+	},
+}}
+
+// RegisterKeyProvider makes a key provider available as encryption.provider
+// = name, e.g. one backed by a KMS.
+func RegisterKeyProvider(name string, factory func(cfg Encryption) (KeyProvider, error)) {
+	keyProviders.Lock()
+	defer keyProviders.Unlock()
+	keyProviders.m[name] = factory
+}
+
+// NewKeyProvider returns the provider cfg names. With no provider named, the
+// key comes from key_file if set and the key_env variable otherwise.
+func NewKeyProvider(cfg Encryption) (KeyProvider, error) {
+	name := cfg.Provider
+	if name == "" {
+		name = "env"
+		if cfg.KeyFile != "" {
+			name = "file"
+		}
+	}
+
+	keyProviders.Lock()
+	factory, ok := keyProviders.m[name]
+	keyProviders.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown key provider %q", name)
+	}
+	return factory(cfg)
+}
+
+// masterKeyProvider derives each job's key from one master key with HKDF.
+// Key IDs are <master key fingerprint>/<job id>.
+type masterKeyProvider struct {
+	master      []byte
+	fingerprint string
+}
+
+// newMasterKeyProvider accepts a 32-byte key raw, or hex or base64 encoded
+func newMasterKeyProvider(data []byte) (*masterKeyProvider, error) {
+	key := data
+	if len(key) != 32 {
+		text := strings.TrimSpace(string(data))
+		if decoded, err := hex.DecodeString(text); err == nil && len(decoded) == 32 {
+			key = decoded
+		} else if decoded, err := base64.StdEncoding.DecodeString(text); err == nil && len(decoded) == 32 {
+			key = decoded
+		} else {
+			return nil, fmt.Errorf("encryption key must be 32 bytes, raw or hex/base64 encoded")
+		}
+	}
+
+	sum := sha256.Sum256(key)
+	return &masterKeyProvider{master: key, fingerprint: hex.EncodeToString(sum[:8])}, nil
+}
+
+func (p *masterKeyProvider) derive(jobID string) ([]byte, error) {
+	key := make([]byte, 32)
+	_, err := io.ReadFull(hkdf.New(sha256.New, p.master, nil, []byte("cedana checkpoint key\x00"+jobID)), key)
+	return key, err
+}
+
+func (p *masterKeyProvider) JobKey(ctx context.Context, jobID string) (string, []byte, error) {
+	key, err := p.derive(jobID)
+	return p.fingerprint + "/" + jobID, key, err
+}
+
+func (p *masterKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	fingerprint, jobID, ok := strings.Cut(keyID, "/")
+	if !ok || fingerprint != p.fingerprint {
+		return nil, fmt.Errorf("checkpoint was encrypted with key %s, which isn't configured", keyID)
+	}
+	return p.derive(jobID)
+}
+
+// recordKeys derives the keys records are sealed with, their nonces derived
+// with and the trailer MACed with from an archive's key
+func recordKeys(key []byte) (aead cipher.AEAD, nonceKey, trailerKey []byte, err error) {
+	derive := func(info string) ([]byte, error) {
+		k := make([]byte, 32)
+		_, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte(info)), k)
+		return k, err
+	}
+	sealKey, err := derive("cedana checkpoint record key")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if nonceKey, err = derive("cedana checkpoint record nonce"); err != nil {
+		return nil, nil, nil, err
+	}
+	if trailerKey, err = derive("cedana checkpoint trailer"); err != nil {
+		return nil, nil, nil, err
+	}
+	aead, err = newGCM(sealKey)
+	return aead, nonceKey, trailerKey, err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("AES-256 needs a 32-byte key, got %d bytes", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type encryptWriter struct {
+	w       io.Writer
+	records *frameWriter
+	trailer hash.Hash
+	closed  bool
+}
+
+// NewEncryptWriter returns a writer encrypting to w under key. keyID is
+// stored in the clear so the key can be found again; Close must be called to
+// write the last records and the trailer.
+//
+// If deterministic, each record's nonce is a MAC of its plaintext, so the
+// same plaintext under the same key always seals the same, and unchanged
+// parts of successive checkpoints of a job still deduplicate once encrypted.
+// That gives away which records repeat, across every archive sealed under
+// the key: anyone who can read the store sees which parts of a process's
+// memory didn't change between its checkpoints.
+func NewEncryptWriter(w io.Writer, key []byte, keyID string, deterministic bool) (io.WriteCloser, error) {
+	aead, nonceKey, trailerKey, err := recordKeys(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyID) > 0xffff {
+		return nil, fmt.Errorf("key id too long")
+	}
+
+	var flags byte
+	if deterministic {
+		flags |= encDeterministic
+	}
+	var header bytes.Buffer
+	header.WriteString(encryptionMagic)
+	header.WriteByte(encryptionVersion)
+	header.WriteByte(flags)
+	binary.Write(&header, binary.BigEndian, uint16(len(keyID)))
+	header.WriteString(keyID)
+
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+	e := &encryptWriter{w: w, trailer: hmac.New(sha256.New, trailerKey)}
+	e.trailer.Write(header.Bytes())
+	e.records = newFrameWriter(recordWriter{e}, compressionThreads(0), func(chunk []byte) ([]byte, error) {
+		nonce := make([]byte, encNonceLen)
+		if deterministic {
+			mac := hmac.New(sha256.New, nonceKey)
+			mac.Write(chunk)
+			copy(nonce, mac.Sum(nil))
+		} else if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+
+		record := make([]byte, 4, 4+encNonceLen+len(chunk)+aead.Overhead())
+		binary.BigEndian.PutUint32(record, uint32(len(chunk)))
+		record = append(record, nonce...)
+		return aead.Seal(record, nonce, chunk, header.Bytes()), nil
+	})
+	return e, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encryptWriter")
+	}
+	return e.records.Write(p)
+}
+
+// recordWriter takes the records sealed by an encryptWriter, in order and one
+// per Write
+type recordWriter struct{ e *encryptWriter }
+
+func (r recordWriter) Write(record []byte) (int, error) {
+	e := r.e
+	e.trailer.Write(record[4 : 4+encNonceLen])
+	e.trailer.Write(record[len(record)-encTagLen:])
+	return e.w.Write(record)
+}
+
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if err := e.records.Close(); err != nil {
+		return err
+	}
+	_, err := e.w.Write(e.trailer.Sum(make([]byte, 4)))
+	return err
+}
+
+// EncryptionHeader is the unencrypted header of an encrypted archive
+type EncryptionHeader struct {
+	KeyID string
+	// what the archive is encrypted with, see EncryptionAlgorithm
+	Algorithm string
+
+	raw []byte
+}
+
+// ReadEncryptionHeader reads the header at the start of r, or returns
+// ErrNotEncrypted if r doesn't start with one.
+func ReadEncryptionHeader(r io.Reader) (*EncryptionHeader, error) {
+	fixed := make([]byte, len(encryptionMagic)+4)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNotEncrypted
+		}
+		return nil, err
+	}
+	if string(fixed[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, ErrNotEncrypted
+	}
+	if version := fixed[len(encryptionMagic)]; version != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encrypted checkpoint version %d", version)
+	}
+	flags := fixed[len(encryptionMagic)+1]
+	if flags&^encDeterministic != 0 {
+		return nil, fmt.Errorf("unsupported encrypted checkpoint flags %#x", flags)
+	}
+	keyID := make([]byte, binary.BigEndian.Uint16(fixed[len(encryptionMagic)+2:]))
+	if _, err := io.ReadFull(r, keyID); err != nil {
+		return nil, fmt.Errorf("truncated encryption header: %w", err)
+	}
+
+	algorithm := EncryptionAlgorithm
+	if flags&encDeterministic != 0 {
+		algorithm = EncryptionAlgorithmDeterministic
+	}
+	return &EncryptionHeader{
+		KeyID:     string(keyID),
+		Algorithm: algorithm,
+		raw:       append(fixed, keyID...),
+	}, nil
+}
+
+// ReadEncryptionHeaderFile is ReadEncryptionHeader for the file at path
+func ReadEncryptionHeaderFile(path string) (*EncryptionHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadEncryptionHeader(f)
+}
+
+var errDecryption = errors.New("checkpoint failed decryption, it is corrupt, truncated or was tampered with")
+
+// NewDecryptReader decrypts an archive written by NewEncryptWriter, looking
+// up its key by the ID in the header. Reads fail if the archive has been
+// tampered with or cut short.
+func NewDecryptReader(r io.Reader, key func(keyID string) ([]byte, error)) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := ReadEncryptionHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	k, err := key(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, _, trailerKey, err := recordKeys(k)
+	if err != nil {
+		return nil, err
+	}
+	d := &decryptReader{r: br, aead: aead, header: header, trailer: hmac.New(sha256.New, trailerKey)}
+	d.trailer.Write(header.raw)
+	return d, nil
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  *EncryptionHeader
+	trailer hash.Hash
+	record  []byte
+	plain   []byte
+	done    bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errDecryption
+		}
+		return err
+	}
+
+	size := int(binary.BigEndian.Uint32(length[:]))
+	if size == 0 {
+		mac := make([]byte, encTrailerLen)
+		if _, err := io.ReadFull(d.r, mac); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return errDecryption
+			}
+			return err
+		}
+		if !hmac.Equal(mac, d.trailer.Sum(nil)) {
+			return errDecryption
+		}
+		// nothing may follow the trailer
+		if _, err := d.r.Peek(1); err != io.EOF {
+			return errDecryption
+		}
+		d.done = true
+		return nil
+	}
+	if size > maxChunkSize {
+		return errDecryption
+	}
+
+	sealed := encNonceLen + size + d.aead.Overhead()
+	if cap(d.record) < sealed {
+		d.record = make([]byte, sealed)
+	}
+	record := d.record[:sealed]
+	if _, err := io.ReadFull(d.r, record); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errDecryption
+		}
+		return err
+	}
+	nonce := record[:encNonceLen]
+	plain, err := d.aead.Open(record[encNonceLen:encNonceLen], nonce, record[encNonceLen:], d.header.raw)
+	if err != nil {
+		return errDecryption
+	}
+	d.trailer.Write(nonce)
+	d.trailer.Write(record[len(record)-encTagLen:])
+	d.plain = plain
+	return nil
+}
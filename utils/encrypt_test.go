@@ -0,0 +1,270 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testKey = bytes.Repeat([]byte{0x42}, 32)
+
+func encrypt(t *testing.T, plain []byte, deterministic bool) []byte {
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, testKey, "test-key", deterministic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// odd write sizes, to cross record boundaries mid-write
+	for len(plain) > 0 {
+		n := 1000
+		if n > len(plain) {
+			n = len(plain)
+		}
+		if _, err := w.Write(plain[:n]); err != nil {
+			t.Fatal(err)
+		}
+		plain = plain[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decrypt(sealed []byte, key []byte) ([]byte, error) {
+	r, err := NewDecryptReader(bytes.NewReader(sealed), func(keyID string) ([]byte, error) {
+		if keyID != "test-key" {
+			return nil, errors.New("unexpected key id " + keyID)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEncryption_RoundTrip(t *testing.T) {
+	for _, deterministic := range []bool{false, true} {
+		for _, size := range []int{0, 1, minChunkSize, maxChunkSize - 1, maxChunkSize, maxChunkSize + 1, 3*maxChunkSize + 17} {
+			plain := randomBytes(int64(size), size)
+			sealed := encrypt(t, plain, deterministic)
+			// short plaintexts turn up in random ciphertext by chance
+			if size >= 64 && bytes.Contains(sealed, plain[:64]) {
+				t.Errorf("%d bytes, deterministic %v: plaintext visible in output", size, deterministic)
+			}
+			got, err := decrypt(sealed, testKey)
+			if err != nil {
+				t.Fatalf("%d bytes, deterministic %v: %v", size, deterministic, err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Errorf("%d bytes, deterministic %v: round trip differs", size, deterministic)
+			}
+		}
+	}
+}
+
+// records splits an encrypted archive into its header, records and trailer
+func records(t *testing.T, sealed []byte) (header []byte, recs [][]byte, trailer []byte) {
+	h, err := ReadEncryptionHeader(bytes.NewReader(sealed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest := sealed[len(h.raw):]
+	for {
+		size := int(binary.BigEndian.Uint32(rest))
+		if size == 0 {
+			return h.raw, recs, rest
+		}
+		n := 4 + encNonceLen + size + encTagLen
+		recs = append(recs, rest[:n])
+		rest = rest[n:]
+	}
+}
+
+func TestEncryption_RejectsTampering(t *testing.T) {
+	plain := randomBytes(1, 3*maxChunkSize)
+	sealed := encrypt(t, plain, false)
+	header, err := ReadEncryptionHeader(bytes.NewReader(sealed))
+	if err != nil || header.KeyID != "test-key" || header.Algorithm != EncryptionAlgorithm {
+		t.Fatalf("bad header %+v, %v", header, err)
+	}
+	raw, recs, trailer := records(t, sealed)
+	if len(recs) < 3 {
+		t.Fatalf("expected several records, got %d", len(recs))
+	}
+	join := func(recs ...[]byte) []byte {
+		return append(append(append([]byte{}, raw...), bytes.Join(recs, nil)...), trailer...)
+	}
+
+	flipped := append([]byte{}, sealed...)
+	flipped[len(flipped)/2] ^= 1
+	keyID := append([]byte{}, sealed...)
+	keyID[len(encryptionMagic)+4] = 'T'
+	flags := append([]byte{}, sealed...)
+	flags[len(encryptionMagic)+1] = encDeterministic
+
+	cases := map[string][]byte{
+		"flipped bit": flipped,
+		// every remaining record is intact
+		"no trailer":      sealed[:len(sealed)-len(trailer)],
+		"dropped record":  join(recs[:len(recs)-1]...),
+		"swapped records": join(append([][]byte{recs[1], recs[0]}, recs[2:]...)...),
+		"trailing data":   append(append([]byte{}, sealed...), 0),
+	}
+	for name, data := range cases {
+		if _, err := decrypt(data, testKey); err == nil {
+			t.Errorf("%s: expected decryption to fail", name)
+		}
+	}
+
+	if _, err := decrypt(sealed, bytes.Repeat([]byte{1}, 32)); err == nil {
+		t.Error("expected the wrong key to fail")
+	}
+	if _, err := decrypt(keyID, testKey); err == nil {
+		t.Error("expected a modified header to fail")
+	}
+	if _, err := decrypt(flags, testKey); err == nil {
+		t.Error("expected modified flags to fail")
+	}
+	if _, err := ReadEncryptionHeader(strings.NewReader("plain tar data")); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("expected ErrNotEncrypted, got %v", err)
+	}
+}
+
+// archives are only deterministic when asked to be, as that shows which
+// parts of them repeat
+func TestEncryption_Deterministic(t *testing.T) {
+	plain := randomBytes(2, 12<<20)
+	if bytes.Equal(encrypt(t, plain, false), encrypt(t, plain, false)) {
+		t.Fatal("expected random nonces by default")
+	}
+
+	sealed := encrypt(t, plain, true)
+	if !bytes.Equal(encrypt(t, plain, true), sealed) {
+		t.Fatal("expected the same plaintext to seal the same")
+	}
+	header, err := ReadEncryptionHeader(bytes.NewReader(sealed))
+	if err != nil || header.Algorithm != EncryptionAlgorithmDeterministic {
+		t.Fatalf("bad header %+v, %v", header, err)
+	}
+
+	// a few pages changed in the middle only change the records around them
+	edited := append([]byte{}, plain...)
+	copy(edited[6<<20:], randomBytes(3, 8192))
+	_, before, _ := records(t, sealed)
+	_, after, _ := records(t, encrypt(t, edited, true))
+	seen := map[string]bool{}
+	for _, rec := range before {
+		seen[string(rec)] = true
+	}
+	shared := 0
+	for _, rec := range after {
+		if seen[string(rec)] {
+			shared++
+		}
+	}
+	if shared < len(before)-2 {
+		t.Errorf("only %d of %d records survived an edit", shared, len(before))
+	}
+
+	// the key still matters
+	var other bytes.Buffer
+	w, _ := NewEncryptWriter(&other, bytes.Repeat([]byte{1}, 32), "test-key", true)
+	w.Write(plain)
+	w.Close()
+	if _, recs, _ := records(t, other.Bytes()); bytes.Equal(recs[0], before[0]) {
+		t.Error("expected a different key to seal differently")
+	}
+}
+
+func TestKeyProviders(t *testing.T) {
+	ctx := context.Background()
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(testKey)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := NewKeyProvider(Encryption{KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_CEDANA_KEY", hex.EncodeToString(testKey))
+	fromEnv, err := NewKeyProvider(Encryption{KeyEnv: "TEST_CEDANA_KEY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idA, keyA, err := fromFile.JobKey(ctx, "job-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idB, keyB, _ := fromFile.JobKey(ctx, "job-b")
+	if idA == idB || bytes.Equal(keyA, keyB) {
+		t.Error("expected jobs to get their own keys")
+	}
+
+	// the same master key, however it's supplied, finds the same job key
+	again, err := fromEnv.Key(ctx, idA)
+	if err != nil || !bytes.Equal(again, keyA) {
+		t.Errorf("expected key %s to be recoverable, got %v", idA, err)
+	}
+
+	other, _ := newMasterKeyProvider(bytes.Repeat([]byte{7}, 32))
+	if _, err := other.Key(ctx, idA); err == nil {
+		t.Error("expected a different master key to refuse the key id")
+	}
+
+	if _, err := NewKeyProvider(Encryption{KeyEnv: "TEST_CEDANA_UNSET"}); err == nil {
+		t.Error("expected an unset key variable to be an error")
+	}
+	if _, err := NewKeyProvider(Encryption{Provider: "vault"}); err == nil {
+		t.Error("expected an unknown provider to be an error")
+	}
+
+	RegisterKeyProvider("test", func(cfg Encryption) (KeyProvider, error) { return other, nil })
+	if p, err := NewKeyProvider(Encryption{Provider: "test"}); err != nil || p != other {
+		t.Errorf("expected the registered provider, got %v, %v", p, err)
+	}
+}
+
+func TestTarFolder_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "pages-1.img"), []byte("secret process memory"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(ctx, src, archive, WithEncryption(testKey, "test-key")); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(archive)
+	if bytes.Contains(data, []byte("secret process memory")) {
+		t.Fatal("archive isn't encrypted")
+	}
+	if info, _ := os.Stat(archive); info.Mode().Perm() != 0o600 {
+		t.Errorf("expected archive to be 0600, got %v", info.Mode().Perm())
+	}
+
+	if err := UntarFolder(ctx, archive, t.TempDir()); err == nil || !strings.Contains(err.Error(), "no encryption key") {
+		t.Errorf("expected restoring without a key to fail clearly, got %v", err)
+	}
+
+	dest := t.TempDir()
+	keys := func(keyID string) ([]byte, error) { return testKey, nil }
+	if err := UntarFolder(ctx, archive, dest, WithDecryption(keys)); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dest, "pages-1.img")); string(got) != "secret process memory" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,432 @@
+package utils
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrUnsafeArchive is wrapped by extraction errors for archives that reach
+// outside the destination, by name, hardlink or symlink, or that exceed their
+// ExtractLimits.
+var ErrUnsafeArchive = errors.New("unsafe checkpoint archive")
+
+// ExtractLimits bound what an archive may extract, so a corrupt or hostile
+// one can't fill the disk.
+type ExtractLimits struct {
+	// bytes written in all files together, and the size of any one of them,
+	// holes included
+	MaxSize     int64
+	MaxFileSize int64
+	MaxEntries  int
+}
+
+var DefaultExtractLimits = ExtractLimits{
+	MaxSize:     1 << 40,
+	MaxFileSize: 1 << 40,
+	MaxEntries:  1 << 20,
+}
+
+const (
+	extractChunkSize = 1 << 20
+	// longer names are refused outright, rather than failing in the kernel
+	maxEntryName = 4096
+	// how tar carries extended attributes, as GNU tar and bsdtar do
+	xattrPAXPrefix = "SCHILY.xattr."
+)
+
+func unsafeArchive(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrUnsafeArchive, fmt.Sprintf(format, args...))
+}
+
+// extractor writes a tarball's entries under dest. Entries that would land
+// outside it, whether by name, by hardlink or by writing through a symlink,
+// are refused. Modes, mtimes, xattrs, symlinks and hardlinks are restored,
+// and ownership too when running as root.
+//
+// File data is written out in the background with up to threads chunks in
+// flight, so writing one file overlaps with decompressing the next.
+type extractor struct {
+	dest   string
+	limits ExtractLimits
+	root   bool
+
+	entries int
+	size    int64
+	// directories get their metadata once nothing more is created in them
+	dirs []extractedDir
+
+	// free chunk buffers, which also bound the writes in flight
+	bufs chan []byte
+	wg   sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+type extractedDir struct {
+	path   string
+	header *tar.Header
+}
+
+func newExtractor(dest string, threads int, limits ExtractLimits) *extractor {
+	if limits.MaxSize == 0 {
+		limits.MaxSize = DefaultExtractLimits.MaxSize
+	}
+	if limits.MaxFileSize == 0 {
+		limits.MaxFileSize = DefaultExtractLimits.MaxFileSize
+	}
+	if limits.MaxEntries == 0 {
+		limits.MaxEntries = DefaultExtractLimits.MaxEntries
+	}
+	e := &extractor{dest: dest, limits: limits, root: os.Geteuid() == 0, bufs: make(chan []byte, threads)}
+	for i := 0; i < threads; i++ {
+		e.bufs <- make([]byte, extractChunkSize)
+	}
+	return e
+}
+
+func (e *extractor) fail(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *extractor) failed() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// path resolves an entry's name to where it goes under dest. Names that are
+// absolute or climb out of dest are refused, as are names under anything but
+// a directory, which the kernel would resolve through.
+func (e *extractor) path(name string) (string, error) {
+	if name == "" || len(name) > maxEntryName || strings.IndexByte(name, 0) >= 0 {
+		return "", unsafeArchive("malformed name %q", name)
+	}
+	clean := filepath.Clean(name)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", unsafeArchive("%q is outside the destination", name)
+	}
+
+	if dir := filepath.Dir(clean); dir != "." {
+		parent := e.dest
+		for _, part := range strings.Split(dir, "/") {
+			parent = filepath.Join(parent, part)
+			fi, err := os.Lstat(parent)
+			if errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			if !fi.IsDir() {
+				return "", unsafeArchive("%q is under %s, which isn't a directory", name, part)
+			}
+		}
+	}
+	return filepath.Join(e.dest, clean), nil
+}
+
+// entry extracts one entry, reading a regular file's data from r
+func (e *extractor) entry(header *tar.Header, r io.Reader) error {
+	if err := e.failed(); err != nil {
+		return err
+	}
+	if header.Typeflag == tar.TypeXGlobalHeader {
+		return nil
+	}
+	if e.entries++; e.entries > e.limits.MaxEntries {
+		return unsafeArchive("more than %d entries", e.limits.MaxEntries)
+	}
+
+	target, err := e.path(header.Name)
+	if err != nil {
+		return err
+	}
+	// the root is the caller's, and keeps its own metadata
+	if target == e.dest {
+		return nil
+	}
+	// parents the archive doesn't list
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if err := os.Mkdir(target, 0o700); err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		if fi, err := os.Lstat(target); err != nil || !fi.IsDir() {
+			return unsafeArchive("%q is already there and isn't a directory", header.Name)
+		}
+		e.dirs = append(e.dirs, extractedDir{path: target, header: header})
+		return nil
+
+	case tar.TypeReg, typeSparse:
+		size, runs := header.Size, []sparseRun{{off: 0, size: header.Size}}
+		if header.Typeflag == typeSparse {
+			var err error
+			if size, runs, err = readSparseMap(r, header.Size, e.limits.MaxFileSize); err != nil {
+				return err
+			}
+		}
+		if size > e.limits.MaxFileSize {
+			return unsafeArchive("%d bytes is over the %d byte limit for a file", size, e.limits.MaxFileSize)
+		}
+		// holes take no space, so don't count
+		if e.size += header.Size; e.size > e.limits.MaxSize {
+			return unsafeArchive("archive is over the %d byte limit", e.limits.MaxSize)
+		}
+		if err := e.replace(target, header); err != nil {
+			return err
+		}
+		return e.file(target, header, r, size, runs)
+
+	case tar.TypeSymlink:
+		// where it points doesn't matter, nothing is extracted through it
+		if err := e.replace(target, header); err != nil {
+			return err
+		}
+		if err := os.Symlink(header.Linkname, target); err != nil {
+			return err
+		}
+		if e.root {
+			if err := os.Lchown(target, header.Uid, header.Gid); err != nil {
+				return err
+			}
+		}
+		return setTimes(target, header)
+
+	case tar.TypeLink:
+		src, err := e.path(header.Linkname)
+		if err != nil {
+			return err
+		}
+		if fi, err := os.Lstat(src); err != nil || !fi.Mode().IsRegular() {
+			return unsafeArchive("hardlink to %q, which isn't a file in the archive", header.Linkname)
+		}
+		if err := e.replace(target, header); err != nil {
+			return err
+		}
+		return os.Link(src, target)
+	}
+	return unsafeArchive("unsupported entry type %q", header.Typeflag)
+}
+
+// replace clears the way for an entry at path. A directory is never replaced:
+// its metadata is set once everything else is extracted, and by then it could
+// be a symlink to anywhere.
+func (e *extractor) replace(path string, header *tar.Header) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return unsafeArchive("%q would replace a directory", header.Name)
+	}
+	return os.Remove(path)
+}
+
+// file writes the runs of a size byte file at path, reading them from r in
+// order; the rest is left a hole. It returns once they're read; the writes
+// may still be in flight, see finish.
+func (e *extractor) file(path string, header *tar.Header, r io.Reader, size int64, runs []sparseRun) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL|syscall.O_NOFOLLOW, 0o600)
+	if err != nil {
+		return err
+	}
+
+	var writes sync.WaitGroup
+	for _, run := range runs {
+		for off, end := run.off, run.off+run.size; off < end; {
+			err := e.failed()
+			buf := <-e.bufs
+			if int64(len(buf)) > end-off {
+				buf = buf[:end-off]
+			}
+			if err == nil {
+				_, err = io.ReadFull(r, buf)
+			}
+			if err != nil {
+				e.bufs <- buf[:cap(buf)]
+				writes.Wait()
+				f.Close()
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			}
+
+			writes.Add(1)
+			go func(buf []byte, off int64) {
+				defer writes.Done()
+				if _, err := f.WriteAt(buf, off); err != nil {
+					e.fail(err)
+				}
+				e.bufs <- buf[:cap(buf)]
+			}(buf, off)
+			off += int64(len(buf))
+		}
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		writes.Wait()
+		// for a trailing hole
+		err := f.Truncate(size)
+		if err == nil {
+			err = e.fileMetadata(f, header)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = setTimes(path, header)
+		}
+		if err != nil {
+			e.fail(err)
+		}
+	}()
+	return nil
+}
+
+// mode is the entry's permissions, plus setuid, setgid and sticky bits when
+// its owner is restored too
+func (e *extractor) mode(header *tar.Header) os.FileMode {
+	mode := header.FileInfo().Mode()
+	if e.root {
+		return mode & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	}
+	return mode & os.ModePerm
+}
+
+func (e *extractor) fileMetadata(f *os.File, header *tar.Header) error {
+	// before the chmod, as a chown clears setuid bits
+	if e.root {
+		if err := f.Chown(header.Uid, header.Gid); err != nil {
+			return err
+		}
+	}
+	fd := int(f.Fd())
+	if err := setXattrs(header, func(name string, value []byte) error {
+		return unix.Fsetxattr(fd, name, value, 0)
+	}); err != nil {
+		return err
+	}
+	return f.Chmod(e.mode(header))
+}
+
+// finish waits for every file to be written, then gives the directories their
+// metadata.
+func (e *extractor) finish() error {
+	e.wg.Wait()
+	if err := e.failed(); err != nil {
+		return err
+	}
+	for i := len(e.dirs) - 1; i >= 0; i-- {
+		if err := e.dirMetadata(e.dirs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dirMetadata gives a directory its metadata through a descriptor opened
+// without following symlinks, so it can't land on whatever the path has
+// become since the directory was made.
+func (e *extractor) dirMetadata(dir extractedDir) error {
+	f, err := os.OpenFile(dir.path, os.O_RDONLY|syscall.O_DIRECTORY|syscall.O_NOFOLLOW, 0)
+	if errors.Is(err, syscall.ENOTDIR) || errors.Is(err, syscall.ELOOP) {
+		return unsafeArchive("%q is no longer a directory", dir.header.Name)
+	}
+	if err != nil {
+		return err
+	}
+	err = e.fileMetadata(f, dir.header)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return setTimes(dir.path, dir.header)
+}
+
+// setXattrs restores the entry's extended attributes with set. Ones the
+// filesystem doesn't support, or that only root may set, are skipped.
+func setXattrs(header *tar.Header, set func(name string, value []byte) error) error {
+	for key, value := range header.PAXRecords {
+		if !strings.HasPrefix(key, xattrPAXPrefix) {
+			continue
+		}
+		err := set(strings.TrimPrefix(key, xattrPAXPrefix), []byte(value))
+		if err != nil && !errors.Is(err, unix.ENOTSUP) && !errors.Is(err, unix.EPERM) {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTimes restores the entry's mtime, and atime if it has one, without
+// following symlinks
+func setTimes(path string, header *tar.Header) error {
+	if header.ModTime.IsZero() {
+		return nil
+	}
+	atime := header.AccessTime
+	if atime.IsZero() {
+		atime = header.ModTime
+	}
+	ts := []unix.Timespec{unix.NsecToTimespec(atime.UnixNano()), unix.NsecToTimespec(header.ModTime.UnixNano())}
+	return unix.UtimesNanoAt(unix.AT_FDCWD, path, ts, unix.AT_SYMLINK_NOFOLLOW)
+}
+
+// readXattrs returns path's extended attributes as tar PAX records, see
+// xattrPAXPrefix.
+func readXattrs(path string) (map[string]string, error) {
+	size, err := unix.Llistxattr(path, nil)
+	if errors.Is(err, unix.ENOTSUP) || size == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Llistxattr(path, buf); err != nil {
+		return nil, err
+	}
+
+	records := map[string]string{}
+	for _, name := range strings.Split(strings.TrimRight(string(buf[:size]), "\x00"), "\x00") {
+		size, err := unix.Lgetxattr(path, name, nil)
+		if errors.Is(err, unix.ENODATA) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, size)
+		if size, err = unix.Lgetxattr(path, name, value); err != nil {
+			return nil, err
+		}
+		records[xattrPAXPrefix+name] = string(value[:size])
+	}
+	return records, nil
+}
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// rawTar builds a tarball of headers exactly as given, without the checks
+// TarFolder would make. A file's Linkname is taken as its contents.
+func rawTar(t testing.TB, headers ...*tar.Header) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, h := range headers {
+		body := h.Linkname
+		data := h.Typeflag == tar.TypeReg || h.Typeflag == typeSparse
+		if data {
+			h.Linkname = ""
+			h.Size = int64(len(body))
+		}
+		if h.Mode == 0 {
+			h.Mode = 0o644
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if data {
+			tw.Write([]byte(body))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func reg(name, data string) *tar.Header {
+	return &tar.Header{Typeflag: tar.TypeReg, Name: name, Linkname: data}
+}
+
+func TestUntarFolder_Unsafe(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string][]*tar.Header{
+		"parent":        {reg("../evil", "x")},
+		"nested parent": {reg("a/../../evil", "x")},
+		"absolute":      {reg("/tmp/evil", "x")},
+		"through symlink": {
+			{Typeflag: tar.TypeSymlink, Name: "link", Linkname: ".."},
+			reg("link/evil", "x"),
+		},
+		"dir through symlink": {
+			{Typeflag: tar.TypeSymlink, Name: "link", Linkname: ".."},
+			{Typeflag: tar.TypeDir, Name: "link/evil"},
+		},
+		"hardlink out": {{Typeflag: tar.TypeLink, Name: "passwd", Linkname: "../outside"}},
+		"hardlink to symlink": {
+			{Typeflag: tar.TypeSymlink, Name: "link", Linkname: "../outside"},
+			{Typeflag: tar.TypeLink, Name: "passwd", Linkname: "link"},
+		},
+		// the directory's mode would be set through the symlink once done
+		"symlink replacing dir": {
+			{Typeflag: tar.TypeDir, Name: "d/", Mode: 0o6777},
+			{Typeflag: tar.TypeSymlink, Name: "d", Linkname: "../outside"},
+		},
+		"file replacing dir": {
+			{Typeflag: tar.TypeDir, Name: "d/", Mode: 0o777},
+			reg("d", "x"),
+		},
+		"device":  {{Typeflag: tar.TypeChar, Name: "null", Devmajor: 1, Devminor: 3}},
+		"too big": {reg("pages-1.img", string(make([]byte, 2000)))},
+	}
+	for name, headers := range cases {
+		root := t.TempDir()
+		dest := filepath.Join(root, "dest")
+		os.Mkdir(dest, 0o755)
+		os.WriteFile(filepath.Join(root, "outside"), []byte("secret"), 0o600)
+		archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+		os.WriteFile(archive, rawTar(t, headers...), 0o644)
+
+		err := UntarFolder(ctx, archive, dest, WithLimits(ExtractLimits{MaxSize: 1000}))
+		if !errors.Is(err, ErrUnsafeArchive) {
+			t.Errorf("%s: expected ErrUnsafeArchive, got %v", name, err)
+		}
+		if entries, _ := os.ReadDir(root); len(entries) != 2 {
+			t.Errorf("%s: something was written outside the destination", name)
+		}
+		if fi, err := os.Stat(filepath.Join(root, "outside")); err != nil || fi.Mode() != 0o600 {
+			t.Errorf("%s: expected the file outside to be left alone, got %v, %v", name, fi.Mode(), err)
+		}
+	}
+}
+
+func TestTarFolder_Metadata(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	mtime := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	os.Mkdir(filepath.Join(src, "fds"), 0o750)
+	os.WriteFile(filepath.Join(src, "fds", "exe"), []byte("#!/bin/sh\n"), 0o755)
+	os.Link(filepath.Join(src, "fds", "exe"), filepath.Join(src, "exe-link"))
+	os.Symlink("fds/exe", filepath.Join(src, "exe-sym"))
+	os.Chtimes(filepath.Join(src, "fds", "exe"), mtime, mtime)
+	os.Chtimes(filepath.Join(src, "fds"), mtime, mtime)
+	xattrs := unix.Setxattr(filepath.Join(src, "fds", "exe"), "user.cedana", []byte("kept"), 0) == nil
+	if os.Geteuid() == 0 {
+		os.Chown(filepath.Join(src, "fds", "exe"), 1234, 5678)
+	}
+
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(ctx, src, archive); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := UntarFolder(ctx, archive, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	exe, err := os.Stat(filepath.Join(dest, "fds", "exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exe.Mode().Perm() != 0o755 || !exe.ModTime().Equal(mtime) {
+		t.Errorf("file metadata not kept: %v %v", exe.Mode(), exe.ModTime())
+	}
+	if dir, _ := os.Stat(filepath.Join(dest, "fds")); dir.Mode().Perm() != 0o750 || !dir.ModTime().Equal(mtime) {
+		t.Errorf("directory metadata not kept: %v %v", dir.Mode(), dir.ModTime())
+	}
+	if link, _ := os.Stat(filepath.Join(dest, "exe-link")); !os.SameFile(exe, link) {
+		t.Error("expected the hardlink to be kept")
+	}
+	if target, err := os.Readlink(filepath.Join(dest, "exe-sym")); err != nil || target != "fds/exe" {
+		t.Errorf("expected the symlink to be kept, got %q, %v", target, err)
+	}
+	if st := exe.Sys().(*syscall.Stat_t); os.Geteuid() == 0 && (st.Uid != 1234 || st.Gid != 5678) {
+		t.Errorf("expected ownership to be kept, got %d:%d", st.Uid, st.Gid)
+	}
+	if xattrs {
+		value := make([]byte, 16)
+		n, err := unix.Getxattr(filepath.Join(dest, "fds", "exe"), "user.cedana", value)
+		if err != nil || string(value[:n]) != "kept" {
+			t.Errorf("expected the xattr to be kept, got %q, %v", value[:n], err)
+		}
+	}
+}
+
+// go test -fuzz FuzzUntarFolder ./utils
+func FuzzUntarFolder(f *testing.F) {
+	f.Add(rawTar(f, &tar.Header{Typeflag: tar.TypeDir, Name: "fds"}, reg("fds/exe", "data"),
+		&tar.Header{Typeflag: tar.TypeLink, Name: "link", Linkname: "fds/exe"},
+		&tar.Header{Typeflag: tar.TypeSymlink, Name: "sym", Linkname: "../.."}))
+	f.Add(rawTar(f, reg("../evil", "x")))
+	f.Add(rawTar(f, &tar.Header{Typeflag: tar.TypeSymlink, Name: "a", Linkname: ".."}, reg("a/evil", "x")))
+	f.Add(rawTar(f, &tar.Header{Typeflag: tar.TypeLink, Name: "a", Linkname: "../x"}))
+	f.Add(rawTar(f, &tar.Header{Typeflag: tar.TypeDir, Name: "a/", Mode: 0o777},
+		&tar.Header{Typeflag: tar.TypeSymlink, Name: "a", Linkname: "../outside"}))
+
+	pages := make([]byte, 8*sparseBlockSize)
+	copy(pages[2*sparseBlockSize:], "page data")
+	sparse := append(sparseMap(int64(len(pages)), []sparseRun{{2 * sparseBlockSize, sparseBlockSize}}), pages[2*sparseBlockSize:3*sparseBlockSize]...)
+	f.Add(rawTar(f, &tar.Header{Typeflag: typeSparse, Name: "pages-1.img", Linkname: string(sparse)}))
+
+	var gz bytes.Buffer
+	zw, _ := compressWriter(&gz, Compression{Codec: CodecGzip, Level: 1}, 2)
+	zw.Write(rawTar(f, reg("pages-1.img", "compressed pages")))
+	zw.Close()
+	f.Add(gz.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+		if err := os.WriteFile(archive, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		root := t.TempDir()
+		dest := filepath.Join(root, "dest")
+		os.Mkdir(dest, 0o755)
+		outside := filepath.Join(root, "outside")
+		os.WriteFile(outside, []byte("secret"), 0o600)
+
+		UntarFolder(context.Background(), archive, dest, WithThreads(2),
+			WithLimits(ExtractLimits{MaxSize: 1 << 20, MaxEntries: 100}))
+
+		if entries, _ := os.ReadDir(root); len(entries) != 2 {
+			t.Fatalf("archive wrote outside the destination: %v", entries)
+		}
+		if fi, err := os.Stat(outside); err != nil || fi.Mode() != 0o600 {
+			t.Fatalf("archive changed a file outside the destination: %v, %v", fi.Mode(), err)
+		}
+	})
+}
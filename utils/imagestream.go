@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// With criu-image-streamer, CRIU sends its images through a socket in the
+// images dir instead of writing them there, and the streamer writes them out
+// as one stream. The stream goes into the archive as a series of pieces,
+// images.stream/00000000 and on, ahead of the files left in the images dir,
+// and is piped back into the streamer on extraction to recreate the images.
+const (
+	imageStreamDir = "images.stream"
+	// a piece is held in memory until it's archived, as tar needs its size
+	// up front
+	imageStreamPieceSize = 16 << 20
+	// the socket criu-image-streamer capture listens on for CRIU
+	imageCaptureSocket = "streamer-capture.sock"
+)
+
+// ImageCapture is criu-image-streamer capturing the images of one dump
+type ImageCapture struct {
+	cmd    *exec.Cmd
+	stream io.ReadCloser
+	stderr bytes.Buffer
+	dir    string
+	config string
+
+	waited sync.Once
+	err    error
+}
+
+// StartImageCapture starts binary capturing the images CRIU dumps into dir,
+// and returns once it's ready for CRIU. The dump must be run with
+// ConfigFile(), which turns on CRIU's --stream, and the images read from
+// Stream() as it runs.
+func StartImageCapture(ctx context.Context, binary, dir string) (_ *ImageCapture, err error) {
+	progress, progressW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer progress.Close()
+
+	c := &ImageCapture{dir: dir}
+	// #nosec G204
+	c.cmd = exec.CommandContext(ctx, binary, "--images-dir", dir, "--progress-fd", "3", "capture")
+	c.cmd.ExtraFiles = []*os.File{progressW}
+	c.cmd.Stderr = &c.stderr
+	if c.stream, err = c.cmd.StdoutPipe(); err != nil {
+		progressW.Close()
+		return nil, err
+	}
+	err = c.cmd.Start()
+	progressW.Close()
+	if err != nil {
+		return nil, fmt.Errorf("starting %s: %w", binary, err)
+	}
+	defer func() {
+		if err != nil {
+			c.Kill()
+		}
+	}()
+
+	line, err := bufio.NewReader(progress).ReadString('\n')
+	if strings.TrimSpace(line) != "socket-init" {
+		c.wait()
+		return nil, fmt.Errorf("%s didn't start: %v %s", binary, err, strings.TrimSpace(c.stderr.String()))
+	}
+
+	config, err := os.CreateTemp("", "criu-stream-*.conf")
+	if err != nil {
+		return nil, err
+	}
+	c.config = config.Name()
+	if _, err := config.WriteString("stream\n"); err != nil {
+		config.Close()
+		return nil, err
+	}
+	if err := config.Close(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ConfigFile is the CRIU config file to dump with
+func (c *ImageCapture) ConfigFile() string {
+	return c.config
+}
+
+// Stream is the captured images, which end once CRIU is done
+func (c *ImageCapture) Stream() io.Reader {
+	return c.stream
+}
+
+// Wait waits for the capture to finish, once the stream has been read to the
+// end, and cleans up after it.
+func (c *ImageCapture) Wait() error {
+	if err := c.wait(); err != nil {
+		return fmt.Errorf("capturing images: %w: %s", err, strings.TrimSpace(c.stderr.String()))
+	}
+	return nil
+}
+
+// Kill stops the capture, for a dump that failed or whose archive did. The
+// stream ends, and CRIU's dump fails if it's still running.
+func (c *ImageCapture) Kill() {
+	c.cmd.Process.Kill()
+	c.wait()
+}
+
+func (c *ImageCapture) wait() error {
+	c.waited.Do(func() {
+		c.err = c.cmd.Wait()
+		if c.config != "" {
+			os.Remove(c.config)
+		}
+		// it can't be archived, and isn't needed past the dump
+		os.Remove(filepath.Join(c.dir, imageCaptureSocket))
+	})
+	return c.err
+}
+
+// WithImageStream has WriteTar archive the images read from r, as captured
+// by an ImageCapture, ahead of srcFolder. As files may still be written to
+// srcFolder while the images stream, it's only walked once r is drained and
+// ready returns; an error from ready fails the archive. r may be nil, to only
+// wait for ready.
+func WithImageStream(r io.Reader, ready func() error) TarOption {
+	return func(o *tarOptions) {
+		o.imageStream = r
+		o.imageStreamReady = ready
+	}
+}
+
+// WithImageStreamer has UntarFolder pipe images streamed into the archive,
+// see WithImageStream, into binary to be extracted.
+func WithImageStreamer(binary string) TarOption {
+	return func(o *tarOptions) {
+		o.imageStreamer = binary
+	}
+}
+
+// writeImageStream archives r as pieces, see imageStreamDir
+func writeImageStream(ctx context.Context, tw *tar.Writer, r io.Reader) error {
+	buf := make([]byte, imageStreamPieceSize)
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(&ctxReader{ctx: ctx, r: r}, buf)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		// no modification time, so unchanged pieces archive the same
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     imageStreamPiece(i),
+			Size:     int64(n),
+			Mode:     0o600,
+			ModTime:  time.Unix(0, 0),
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(buf[:n]); err != nil {
+			return err
+		}
+		if n < len(buf) {
+			return nil
+		}
+	}
+}
+
+func imageStreamPiece(i int) string {
+	return fmt.Sprintf("%s/%08d", imageStreamDir, i)
+}
+
+// imageExtraction pipes the pieces of an image stream, in order, into
+// criu-image-streamer extract.
+type imageExtraction struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+	next   int
+}
+
+func startImageExtraction(ctx context.Context, binary, dir string) (*imageExtraction, error) {
+	if binary == "" {
+		return nil, fmt.Errorf("the checkpoint's images were streamed with criu-image-streamer, which store.image_streamer must name to restore it")
+	}
+	e := &imageExtraction{}
+	// #nosec G204
+	e.cmd = exec.CommandContext(ctx, binary, "--images-dir", dir, "extract")
+	e.cmd.Stderr = &e.stderr
+	var err error
+	if e.stdin, err = e.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if err := e.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", binary, err)
+	}
+	return e, nil
+}
+
+// piece extracts the next piece of the stream, which must be named so
+func (e *imageExtraction) piece(header *tar.Header, r io.Reader) error {
+	if header.Typeflag != tar.TypeReg || header.Name != imageStreamPiece(e.next) {
+		return fmt.Errorf("expected image stream piece %s", imageStreamPiece(e.next))
+	}
+	e.next++
+	if _, err := io.Copy(e.stdin, r); err != nil {
+		return fmt.Errorf("extracting images: %w", err)
+	}
+	return nil
+}
+
+// finish ends the stream and waits for the images to be written out
+func (e *imageExtraction) finish() error {
+	e.stdin.Close()
+	if err := e.cmd.Wait(); err != nil {
+		return fmt.Errorf("extracting images: %w: %s", err, strings.TrimSpace(e.stderr.String()))
+	}
+	return nil
+}
+
+func isImageStreamPiece(name string) bool {
+	return strings.HasPrefix(filepath.Clean(name), imageStreamDir+"/")
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeStreamer writes a stand-in for criu-image-streamer. capture signals
+// it's ready and streams the file named by $FAKE_IMAGES, extract writes its
+// stdin to images.img in the images dir.
+func fakeStreamer(t *testing.T) string {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "criu-image-streamer")
+	body := `#!/bin/sh
+dir=$2
+for cmd; do :; done
+case $cmd in
+capture)
+	touch "$dir/streamer-capture.sock"
+	echo socket-init >&3
+	cat "$FAKE_IMAGES"
+	;;
+extract)
+	cat > "$dir/images.img"
+	;;
+esac
+`
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestImageStream_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	streamer := fakeStreamer(t)
+
+	// pieces are whole and partial
+	images := make([]byte, 2*imageStreamPieceSize+12345)
+	rand.Read(images)
+	imagesFile := filepath.Join(t.TempDir(), "images")
+	if err := os.WriteFile(imagesFile, images, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKE_IMAGES", imagesFile)
+
+	src := t.TempDir()
+	capture, err := StartImageCapture(ctx, streamer, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := os.ReadFile(capture.ConfigFile())
+	if err != nil || strings.TrimSpace(string(config)) != "stream" {
+		t.Fatalf("config file: %q, %v", config, err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "dump.tar")
+	ready := func() error {
+		// written once CRIU's done, so only archived after the stream
+		if err := os.WriteFile(filepath.Join(src, "state.json"), []byte("{}"), 0o600); err != nil {
+			return err
+		}
+		return capture.Wait()
+	}
+	err = TarFolder(ctx, src, archive, WithImageStream(capture.Stream(), ready), WithCompression(Compression{Codec: "zstd"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(capture.ConfigFile()); !os.IsNotExist(err) {
+		t.Errorf("config file left behind: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(src, imageCaptureSocket)); !os.IsNotExist(err) {
+		t.Errorf("capture socket left behind: %v", err)
+	}
+
+	dest := t.TempDir()
+	if err := UntarFolder(ctx, archive, dest, WithImageStreamer(streamer)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dest, "images.img"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, images) {
+		t.Errorf("extracted %d bytes of images, want the %d streamed", len(got), len(images))
+	}
+	if _, err := os.Stat(filepath.Join(dest, "state.json")); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, imageStreamDir)); !os.IsNotExist(err) {
+		t.Errorf("stream pieces extracted as files: %v", err)
+	}
+
+	if err := UntarFolder(ctx, archive, t.TempDir()); err == nil {
+		t.Error("extracted streamed images without a streamer")
+	}
+}
+
+func TestImageStream_ReadyFails(t *testing.T) {
+	errNotReady := errors.New("dump failed")
+	ready := func() error { return errNotReady }
+	err := WriteTar(context.Background(), t.TempDir(), &bytes.Buffer{}, WithImageStream(strings.NewReader("images"), ready))
+	if !errors.Is(err, errNotReady) {
+		t.Errorf("got %v, want %v", err, errNotReady)
+	}
+}
+
+func TestImageStream_PieceOrder(t *testing.T) {
+	ctx := context.Background()
+	streamer := fakeStreamer(t)
+	cases := map[string][]*tar.Header{
+		"out of order":      {reg(imageStreamPiece(1), "x")},
+		"after files":       {reg("state.json", "{}"), reg(imageStreamPiece(0), "x")},
+		"not a file":        {{Typeflag: tar.TypeSymlink, Name: imageStreamPiece(0), Linkname: "/etc/passwd"}},
+		"skipped":           {reg(imageStreamPiece(0), "x"), reg(imageStreamPiece(2), "x")},
+		"after other files": {reg(imageStreamPiece(0), "x"), reg("state.json", "{}"), reg(imageStreamPiece(1), "x")},
+	}
+	for name, headers := range cases {
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "dump.tar")
+			if err := os.WriteFile(archive, rawTar(t, headers...), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := UntarFolder(ctx, archive, t.TempDir(), WithImageStreamer(streamer)); err == nil {
+				t.Error("extracted out of order stream pieces")
+			}
+		})
+	}
+}
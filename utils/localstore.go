@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps checkpoints in a directory, which may be an NFS mount
+// shared between nodes. It is content-addressed: a checkpoint's ID is the
+// sha256 of its tarball, so pushing the same checkpoint twice stores it once.
+//
+// Layout is <dir>/<id[:2]>/<id>.tar, with <id>.json beside it holding the
+// checkpoint's CheckpointMeta, and <id>.tar.manifest its signed manifest if it
+// has one.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+func validCheckpointID(cid string) bool {
+	if len(cid) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(cid)
+	return err == nil
+}
+
+func (ls *LocalStore) tarPath(cid string) string {
+	return filepath.Join(ls.dir, cid[:2], cid+".tar")
+}
+
+func (ls *LocalStore) metaPath(cid string) string {
+	return filepath.Join(ls.dir, cid[:2], cid+".json")
+}
+
+// writeFileAtomic writes data next to path and renames it into place, so
+// readers on other nodes never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (ls *LocalStore) PushCheckpoint(ctx context.Context, jobID, checkpointPath string) (_ *CheckpointMeta, err error) {
+	src, err := os.Open(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	// hash while copying in, since the ID (and so the final path) isn't known
+	// until the whole tarball has been read
+	tmp, err := os.CreateTemp(ls.dir, ".tmp-push-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), &ctxReader{ctx: ctx, r: src})
+	if err != nil {
+		return nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		return nil, err
+	}
+
+	cid := hex.EncodeToString(hash.Sum(nil))
+	if err := os.MkdirAll(filepath.Dir(ls.tarPath(cid)), 0o755); err != nil {
+		return nil, err
+	}
+
+	if signed, err := os.ReadFile(checkpointPath + manifestSuffix); err == nil {
+		if err := writeFileAtomic(ls.tarPath(cid)+manifestSuffix, signed); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if _, err := os.Stat(ls.tarPath(cid)); os.IsNotExist(err) {
+		if err := os.Rename(tmp.Name(), ls.tarPath(cid)); err != nil {
+			return nil, err
+		}
+	}
+
+	meta := &CheckpointMeta{
+		ID:       cid,
+		JobID:    jobID,
+		Name:     filepath.Base(checkpointPath),
+		Bucket:   ls.dir,
+		ModTime:  time.Now(),
+		Size:     uint64(size),
+		Checksum: "sha256:" + cid,
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(ls.metaPath(cid), data); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// GetCheckpoint returns the stored tarball itself, its signed manifest already
+// beside it; nothing is copied, so there's nothing to release.
+func (ls *LocalStore) GetCheckpoint(ctx context.Context, cid string) (*string, func(), error) {
+	if !validCheckpointID(cid) {
+		return nil, nil, fmt.Errorf("invalid checkpoint id %q", cid)
+	}
+
+	path := ls.tarPath(cid)
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, fmt.Errorf("checkpoint %s: %w", cid, err)
+	}
+	return &path, func() {}, nil
+}
+
+func (ls *LocalStore) ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) {
+	checkpoints := []CheckpointMeta{}
+
+	err := filepath.WalkDir(ls.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var meta CheckpointMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return fmt.Errorf("corrupt checkpoint metadata %s: %w", path, err)
+		}
+		checkpoints = append(checkpoints, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoints, nil
+}
+
+func (ls *LocalStore) chunkPath(digest string) string {
+	return filepath.Join(ls.dir, "chunks", digest[:2], digest)
+}
+
+func (ls *LocalStore) HasChunk(ctx context.Context, digest string) (string, bool, error) {
+	if !validCheckpointID(digest) {
+		return "", false, fmt.Errorf("invalid chunk digest %q", digest)
+	}
+	now := time.Now()
+	err := os.Chtimes(ls.chunkPath(digest), now, now)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	return "", err == nil, err
+}
+
+func (ls *LocalStore) PutChunk(ctx context.Context, digest string, data []byte) (string, error) {
+	if !validCheckpointID(digest) {
+		return "", fmt.Errorf("invalid chunk digest %q", digest)
+	}
+	if err := os.MkdirAll(filepath.Dir(ls.chunkPath(digest)), 0o755); err != nil {
+		return "", err
+	}
+	return "", writeFileAtomic(ls.chunkPath(digest), data)
+}
+
+func (ls *LocalStore) GetChunk(ctx context.Context, ref ChunkRef) (io.ReadCloser, error) {
+	if !validCheckpointID(ref.Digest) {
+		return nil, fmt.Errorf("invalid chunk digest %q", ref.Digest)
+	}
+	return os.Open(ls.chunkPath(ref.Digest))
+}
+
+func (ls *LocalStore) ListChunks(ctx context.Context) ([]ChunkInfo, error) {
+	chunks := []ChunkInfo{}
+	err := filepath.WalkDir(filepath.Join(ls.dir, "chunks"), func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() || !validCheckpointID(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, ChunkInfo{Digest: d.Name(), ModTime: info.ModTime()})
+		return nil
+	})
+	return chunks, err
+}
+
+func (ls *LocalStore) DeleteChunk(ctx context.Context, digest string) error {
+	if !validCheckpointID(digest) {
+		return fmt.Errorf("invalid chunk digest %q", digest)
+	}
+	return os.Remove(ls.chunkPath(digest))
+}
+
+func (ls *LocalStore) DeleteCheckpoint(ctx context.Context, cid string) error {
+	if !validCheckpointID(cid) {
+		return fmt.Errorf("invalid checkpoint id %q", cid)
+	}
+
+	if err := os.Remove(ls.tarPath(cid)); err != nil {
+		return fmt.Errorf("checkpoint %s: %w", cid, err)
+	}
+	for _, path := range []string{ls.metaPath(cid), ls.tarPath(cid) + manifestSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(filepath.Join(t.TempDir(), "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := os.WriteFile(src, []byte("checkpoint contents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := store.PushCheckpoint(ctx, "job-a", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !validCheckpointID(meta.ID) || meta.JobID != "job-a" || meta.Size != uint64(len("checkpoint contents")) {
+		t.Fatalf("unexpected meta %+v", meta)
+	}
+
+	// same content, same ID
+	again, err := store.PushCheckpoint(ctx, "job-a", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != meta.ID {
+		t.Errorf("expected identical checkpoints to share an ID, got %s and %s", meta.ID, again.ID)
+	}
+
+	path, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(*path)
+	if err != nil || string(data) != "checkpoint contents" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+
+	list, err := store.ListCheckpoints(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*list) != 1 || (*list)[0].ID != meta.ID {
+		t.Fatalf("expected one checkpoint, got %+v", *list)
+	}
+
+	if err := store.DeleteCheckpoint(ctx, meta.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected deleted checkpoint to be gone, got %v", err)
+	}
+	if list, _ := store.ListCheckpoints(ctx); len(*list) != 0 {
+		t.Errorf("expected empty store, got %+v", *list)
+	}
+}
+
+func TestLocalStore_RejectsBadIDs(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cid := range []string{"", "../../etc/passwd", "abc"} {
+		if _, _, err := store.GetCheckpoint(context.Background(), cid); err == nil {
+			t.Errorf("expected %q to be rejected", cid)
+		}
+	}
+}
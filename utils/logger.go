@@ -24,6 +24,8 @@ func GetLogger() zerolog.Logger {
 		if err != nil {
 			logLevel = int(zerolog.DebugLevel)
 		}
+		// the global level, so SetLogLevel can change it later
+		zerolog.SetGlobalLevel(zerolog.Level(logLevel))
 
 		var output io.Writer = zerolog.ConsoleWriter{
 			Out:        os.Stdout,
@@ -31,7 +33,6 @@ func GetLogger() zerolog.Logger {
 		}
 
 		log = zerolog.New(output).
-			Level(zerolog.Level(logLevel)).
 			With().
 			Timestamp().
 			Logger().
@@ -40,3 +41,18 @@ func GetLogger() zerolog.Logger {
 
 	return log
 }
+
+// SetLogLevel sets the level logs are written at, e.g. "info"; "" leaves it
+// as LOG_LEVEL set it.
+func SetLogLevel(level string) error {
+	if level == "" {
+		return nil
+	}
+	l, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	GetLogger()
+	zerolog.SetGlobalLevel(l)
+	return nil
+}
@@ -0,0 +1,672 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Checkpoints in a registry are OCI artifacts of type OCIArtifactType. The
+// archive is split into layers at tar entry boundaries, by what the entries
+// hold, so the layers concatenated in order are the archive again. Encrypted
+// and compressed archives can't be split and are pushed as a single layer, of
+// OCIArchiveMediaType suffixed with "+encrypted" or the codec.
+const (
+	OCIArtifactType       = "application/vnd.cedana.checkpoint.v1"
+	OCIConfigMediaType    = "application/vnd.cedana.checkpoint.config.v1+json"
+	OCIImagesMediaType    = "application/vnd.cedana.checkpoint.images.v1.tar"
+	OCIStateMediaType     = "application/vnd.cedana.checkpoint.state.v1.tar"
+	OCIGPUMediaType       = "application/vnd.cedana.checkpoint.gpu.v1.tar"
+	OCIArchiveMediaType   = "application/vnd.cedana.checkpoint.archive.v1.tar"
+	OCIEncryptedMediaType = OCIArchiveMediaType + "+encrypted"
+	// the archive's signed manifest, see SignArchive; not part of the archive
+	OCISignatureMediaType = "application/vnd.cedana.checkpoint.manifest.v1+json"
+
+	OCIJobIDAnnotation       = "io.cedana.checkpoint.job-id"
+	OCIPIDAnnotation         = "io.cedana.checkpoint.pid"
+	OCICRIUVersionAnnotation = "io.cedana.checkpoint.criu-version"
+	OCIGPUAnnotation         = "io.cedana.checkpoint.gpu"
+
+	maxOCIManifestSize = 4 << 20
+)
+
+var ociTagInvalid = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// OCIStore pushes checkpoints to an OCI registry, e.g. a registry:2 container
+// or any hosted registry. Registries deduplicate layers by digest themselves,
+// so checkpoints aren't chunked on top.
+type OCIStore struct {
+	cfg  OCIConfig
+	base *url.URL
+	http *http.Client
+	auth docker.Authorizer
+	// when set, only checkpoints signed by one of these are returned
+	trusted TrustedKeys
+
+	cache  *CheckpointCache
+	tracer trace.Tracer
+}
+
+func NewOCIStore(cfg OCIConfig, cache *CheckpointCache, tracer trace.Tracer) (*OCIStore, error) {
+	host, name, ok := strings.Cut(cfg.Repository, "/")
+	if !ok || host == "" || name == "" {
+		return nil, fmt.Errorf("store.oci.repository must be <registry host>/<name>, got %q", cfg.Repository)
+	}
+	scheme := "https"
+	if cfg.Insecure {
+		scheme = "http"
+	}
+
+	client := &http.Client{}
+	auth := docker.NewDockerAuthorizer(
+		docker.WithAuthClient(client),
+		docker.WithAuthCreds(func(string) (string, string, error) {
+			return cfg.Username, cfg.Password, nil
+		}),
+	)
+	return &OCIStore{
+		cfg:    cfg,
+		base:   &url.URL{Scheme: scheme, Host: host, Path: "/v2/" + name + "/"},
+		http:   client,
+		auth:   auth,
+		cache:  cache,
+		tracer: tracer,
+	}, nil
+}
+
+func (o *OCIStore) url(path string) string {
+	return o.base.ResolveReference(&url.URL{Path: path}).String()
+}
+
+// do sends a request, answering the registry's auth challenge if it makes
+// one. body is rewound for the retry.
+func (o *OCIStore) do(ctx context.Context, method, url string, header http.Header, body io.ReadSeeker, size int64) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil && size > 0 {
+			if _, err := body.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(body)
+			req.ContentLength = size
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if err := o.auth.Authorize(ctx, req); err != nil {
+			return nil, err
+		}
+
+		resp, err := o.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if err := o.auth.AddResponses(ctx, []*http.Response{resp}); err != nil {
+			return nil, fmt.Errorf("registry authentication: %w", err)
+		}
+	}
+}
+
+// checkRegistryStatus is checkStatus with 404 as os.ErrNotExist
+func checkRegistryStatus(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	return checkStatus(resp)
+}
+
+type ociLayer struct {
+	mediaType string
+	off, size int64
+	digest    digest.Digest
+}
+
+// ociSummary is the artifact's config blob, and the source of its annotations
+type ociSummary struct {
+	JobID       string    `json:"job_id"`
+	PID         int32     `json:"pid,omitempty"`
+	CRIUVersion int32     `json:"criu_version,omitempty"`
+	GPU         bool      `json:"gpu"`
+	Encrypted   bool      `json:"encrypted,omitempty"`
+	Compression string    `json:"compression,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ociLayerKind sorts an archive entry into images, state or GPU data. Until
+// a GPU checkpoint is known of, anything that isn't state is taken to be
+// CRIU's.
+func ociLayerKind(name string, gpu bool) string {
+	base := filepath.Base(name)
+	switch {
+	case name == "checkpoint_state.json":
+		return OCIStateMediaType
+	case !gpu, strings.HasSuffix(base, ".img"), strings.HasSuffix(base, ".log"),
+		strings.HasPrefix(base, "stats-"), base == "irmap-cache":
+		return OCIImagesMediaType
+	}
+	return OCIGPUMediaType
+}
+
+// ociLayers works out how to split the archive at path into layers, and
+// summarizes it from its checkpoint_state.json.
+func ociLayers(ctx context.Context, path string) ([]ociLayer, *ociSummary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := &ociSummary{}
+	var layers []ociLayer
+	if _, err := ReadEncryptionHeader(f); err == nil {
+		summary.Encrypted = true
+		layers = []ociLayer{{mediaType: OCIEncryptedMediaType, size: info.Size()}}
+	} else if !errors.Is(err, ErrNotEncrypted) {
+		return nil, nil, err
+	} else {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+		dr, codec, err := decompressReader(f, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if codec == CodecNone {
+			layers, err = splitArchive(ctx, dr, info.Size(), summary)
+		} else {
+			// the tar is still read through, for the summary
+			summary.Compression = codec
+			layers = []ociLayer{{mediaType: OCIArchiveMediaType + "+" + codec, size: info.Size()}}
+			_, err = splitArchive(ctx, dr, 0, summary)
+		}
+		dr.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i := range layers {
+		h := sha256.New()
+		if _, err := io.Copy(h, &ctxReader{ctx: ctx, r: io.NewSectionReader(f, layers[i].off, layers[i].size)}); err != nil {
+			return nil, nil, err
+		}
+		layers[i].digest = digest.NewDigest(digest.SHA256, h)
+	}
+	return layers, summary, nil
+}
+
+func splitArchive(ctx context.Context, f io.Reader, size int64, summary *ociSummary) ([]ociLayer, error) {
+	type entry struct {
+		name  string
+		dir   bool
+		start int64
+	}
+	var entries []entry
+
+	cr := &countingReader{r: &ctxReader{ctx: ctx, r: f}}
+	tr := tar.NewReader(cr)
+	end := int64(0)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading checkpoint archive: %w", err)
+		}
+		entries = append(entries, entry{name: header.Name, dir: header.Typeflag == tar.TypeDir, start: end})
+		// the entry runs up to the end of its data, padded to a block
+		end = cr.n + (header.Size+511)&^511
+
+		if header.Name == "checkpoint_state.json" {
+			var state struct {
+				PID             int32
+				GPUCheckpointed bool
+				CRIUVersion     int32
+			}
+			if err := json.NewDecoder(io.LimitReader(tr, 1<<20)).Decode(&state); err == nil {
+				summary.PID = state.PID
+				summary.GPU = state.GPUCheckpointed
+				summary.CRIUVersion = state.CRIUVersion
+			}
+		}
+	}
+
+	var layers []ociLayer
+	for _, e := range entries {
+		// directories go with the run they fall in, leading ones with the
+		// first
+		if e.dir {
+			continue
+		}
+		kind := ociLayerKind(e.name, summary.GPU)
+		if len(layers) == 0 {
+			layers = append(layers, ociLayer{mediaType: kind})
+			continue
+		}
+		last := &layers[len(layers)-1]
+		if kind == last.mediaType {
+			continue
+		}
+		last.size = e.start - last.off
+		layers = append(layers, ociLayer{mediaType: kind, off: e.start})
+	}
+	if len(layers) == 0 {
+		layers = []ociLayer{{mediaType: OCIImagesMediaType}}
+	}
+	// the last layer takes the end-of-archive blocks
+	layers[len(layers)-1].size = size - layers[len(layers)-1].off
+	return layers, nil
+}
+
+// pushBlob uploads a blob unless the registry already has it
+func (o *OCIStore) pushBlob(ctx context.Context, d digest.Digest, r io.ReadSeeker, size int64) error {
+	return withRetries(ctx, maxPartAttempts, func() error {
+		resp, err := o.do(ctx, http.MethodHead, o.url("blobs/"+d.String()), nil, nil, 0)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return nil
+		}
+
+		resp, err = o.do(ctx, http.MethodPost, o.url("blobs/uploads/"), nil, nil, 0)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if err := checkStatus(resp); err != nil {
+			return err
+		}
+		location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+		if err != nil {
+			return fmt.Errorf("bad upload location: %w", err)
+		}
+		query := location.Query()
+		query.Set("digest", d.String())
+		location.RawQuery = query.Encode()
+
+		header := http.Header{"Content-Type": {"application/octet-stream"}}
+		resp, err = o.do(ctx, http.MethodPut, location.String(), header, r, size)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return checkStatus(resp)
+	})
+}
+
+func (o *OCIStore) pushBytes(ctx context.Context, mediaType string, data []byte) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	return desc, o.pushBlob(ctx, desc.Digest, bytes.NewReader(data), desc.Size)
+}
+
+// ociTag names a checkpoint's manifest after its job, so the registry keeps it
+func ociTag(jobID string, d digest.Digest) string {
+	tag := ociTagInvalid.ReplaceAllString(jobID, "_")
+	if len(tag) > 100 {
+		tag = tag[:100]
+	}
+	if tag == "" || tag[0] == '.' || tag[0] == '-' {
+		tag = "checkpoint" + tag
+	}
+	return tag + "-" + d.Encoded()[:12]
+}
+
+func (o *OCIStore) PushCheckpoint(ctx context.Context, jobID, checkpointPath string) (*CheckpointMeta, error) {
+	ctx, pushSpan := o.tracer.Start(ctx, "OCIPushCheckpoint")
+	defer pushSpan.End()
+
+	meta, err := o.push(ctx, jobID, checkpointPath)
+	if err != nil {
+		pushSpan.RecordError(err)
+	}
+	return meta, err
+}
+
+func (o *OCIStore) push(ctx context.Context, jobID, checkpointPath string) (*CheckpointMeta, error) {
+	layers, summary, err := ociLayers(ctx, checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	summary.JobID = jobID
+	summary.Created = time.Now().UTC()
+
+	f, err := os.Open(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	manifest := ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: OCIArtifactType,
+		Annotations: map[string]string{
+			OCIJobIDAnnotation:        jobID,
+			OCIGPUAnnotation:          strconv.FormatBool(summary.GPU),
+			ocispec.AnnotationCreated: summary.Created.Format(time.RFC3339),
+		},
+	}
+	manifest.SchemaVersion = 2
+	if summary.PID != 0 {
+		manifest.Annotations[OCIPIDAnnotation] = strconv.Itoa(int(summary.PID))
+	}
+	if summary.CRIUVersion != 0 {
+		manifest.Annotations[OCICRIUVersionAnnotation] = strconv.Itoa(int(summary.CRIUVersion))
+	}
+
+	var size int64
+	for _, layer := range layers {
+		if err := o.pushBlob(ctx, layer.digest, io.NewSectionReader(f, layer.off, layer.size), layer.size); err != nil {
+			return nil, fmt.Errorf("pushing layer %s: %w", layer.digest, err)
+		}
+		manifest.Layers = append(manifest.Layers, ocispec.Descriptor{MediaType: layer.mediaType, Digest: layer.digest, Size: layer.size})
+		size += layer.size
+	}
+
+	if signed, err := os.ReadFile(checkpointPath + manifestSuffix); err == nil {
+		desc, err := o.pushBytes(ctx, OCISignatureMediaType, signed)
+		if err != nil {
+			return nil, fmt.Errorf("pushing signature: %w", err)
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	config, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Config, err = o.pushBytes(ctx, OCIConfigMediaType, config); err != nil {
+		return nil, fmt.Errorf("pushing config: %w", err)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	d := digest.FromBytes(data)
+	tag := ociTag(jobID, d)
+	header := http.Header{"Content-Type": {ocispec.MediaTypeImageManifest}}
+	err = withRetries(ctx, maxPartAttempts, func() error {
+		resp, err := o.do(ctx, http.MethodPut, o.url("manifests/"+tag), header, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return checkStatus(resp)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pushing manifest: %w", err)
+	}
+
+	return &CheckpointMeta{
+		ID:      d.Encoded(),
+		JobID:   jobID,
+		Name:    tag,
+		ModTime: summary.Created,
+		Size:    uint64(size),
+	}, nil
+}
+
+// fetchManifest gets a checkpoint's manifest by its digest, or by tag when
+// listing
+func (o *OCIStore) fetchManifest(ctx context.Context, ref string) (*ocispec.Manifest, digest.Digest, error) {
+	header := http.Header{"Accept": {ocispec.MediaTypeImageManifest}}
+	resp, err := o.do(ctx, http.MethodGet, o.url("manifests/"+ref), header, nil, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if err := checkRegistryStatus(resp); err != nil {
+		return nil, "", err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOCIManifestSize))
+	if err != nil {
+		return nil, "", err
+	}
+	d := digest.FromBytes(data)
+	if strings.HasPrefix(ref, "sha256:") && d.String() != ref {
+		return nil, "", fmt.Errorf("manifest %s failed verification, got %s", ref, d)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, "", fmt.Errorf("corrupt manifest %s: %w", ref, err)
+	}
+	if manifest.ArtifactType != OCIArtifactType && manifest.Config.MediaType != OCIConfigMediaType {
+		return nil, "", fmt.Errorf("%s is not a cedana checkpoint: %w", ref, os.ErrNotExist)
+	}
+	return &manifest, d, nil
+}
+
+// fetchBlob copies a blob to w, checking it against its digest
+func (o *OCIStore) fetchBlob(ctx context.Context, desc ocispec.Descriptor, w io.Writer) error {
+	resp, err := o.do(ctx, http.MethodGet, o.url("blobs/"+desc.Digest.String()), nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkRegistryStatus(resp); err != nil {
+		return err
+	}
+
+	verifier := desc.Digest.Verifier()
+	n, err := io.Copy(io.MultiWriter(w, verifier), &ctxReader{ctx: ctx, r: resp.Body})
+	if err != nil {
+		return err
+	}
+	if n != desc.Size || !verifier.Verified() {
+		return fmt.Errorf("blob %s failed verification", desc.Digest)
+	}
+	return nil
+}
+
+// signedManifest returns the checkpoint's signed archive manifest, or nil if
+// it was pushed without one
+func (o *OCIStore) signedManifest(ctx context.Context, manifest *ocispec.Manifest) (*Manifest, error) {
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != OCISignatureMediaType {
+			continue
+		}
+		if layer.Size > maxOCIManifestSize {
+			return nil, fmt.Errorf("signature layer is %d bytes", layer.Size)
+		}
+		var buf bytes.Buffer
+		if err := o.fetchBlob(ctx, layer, &buf); err != nil {
+			return nil, err
+		}
+		var signed Manifest
+		if err := json.Unmarshal(buf.Bytes(), &signed); err != nil {
+			return nil, fmt.Errorf("corrupt signature layer: %w", err)
+		}
+		return &signed, nil
+	}
+	return nil, nil
+}
+
+func (o *OCIStore) GetCheckpoint(ctx context.Context, cid string) (*string, func(), error) {
+	ctx, getSpan := o.tracer.Start(ctx, "OCIGetCheckpoint")
+	defer getSpan.End()
+
+	path, release, err := o.get(ctx, cid)
+	if err != nil {
+		getSpan.RecordError(err)
+		return nil, nil, err
+	}
+	return &path, release, nil
+}
+
+func (o *OCIStore) get(ctx context.Context, cid string) (string, func(), error) {
+	if !validCheckpointID(cid) {
+		return "", nil, fmt.Errorf("invalid checkpoint id %q", cid)
+	}
+	manifest, _, err := o.fetchManifest(ctx, "sha256:"+cid)
+	if err != nil {
+		return "", nil, err
+	}
+	signed, err := o.signedManifest(ctx, manifest)
+	if err != nil {
+		return "", nil, err
+	}
+	if o.trusted != nil {
+		if signed == nil {
+			return "", nil, fmt.Errorf("%w: checkpoint %s carries no signature", ErrUntrustedCheckpoint, cid)
+		}
+		if err := signed.Verify(o.trusted); err != nil {
+			return "", nil, fmt.Errorf("checkpoint %s: %w", cid, err)
+		}
+	}
+
+	return o.cache.Get(ctx, cid, func(ctx context.Context, partial string) error {
+		if signed != nil {
+			if err := writeManifest(partial, signed); err != nil {
+				return err
+			}
+		}
+
+		f, err := os.Create(partial)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		archive := sha256.New()
+		for _, layer := range manifest.Layers {
+			if layer.MediaType == OCISignatureMediaType {
+				continue
+			}
+			if err := o.fetchBlob(ctx, layer, io.MultiWriter(f, archive)); err != nil {
+				return fmt.Errorf("layer %s: %w", layer.Digest, err)
+			}
+		}
+		// the layers are bound to cid, but the signature only vouches for
+		// the archive it was made over
+		if signed != nil && "sha256:"+hex.EncodeToString(archive.Sum(nil)) != signed.Checksum {
+			return fmt.Errorf("%w: checkpoint %s doesn't match its signed manifest", ErrUntrustedCheckpoint, cid)
+		}
+		return f.Close()
+	})
+}
+
+func (o *OCIStore) ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) {
+	var tags []string
+	next := o.url("tags/list")
+	for next != "" {
+		resp, err := o.do(ctx, http.MethodGet, next, nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = checkRegistryStatus(resp)
+		if err == nil {
+			err = json.NewDecoder(resp.Body).Decode(&page)
+		}
+		resp.Body.Close()
+		if errors.Is(err, os.ErrNotExist) {
+			// nothing has been pushed to the repository yet
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Tags...)
+		next = nextPage(resp)
+	}
+
+	metas := []CheckpointMeta{}
+	for _, tag := range tags {
+		manifest, d, err := o.fetchManifest(ctx, tag)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		meta := CheckpointMeta{ID: d.Encoded(), JobID: manifest.Annotations[OCIJobIDAnnotation], Name: tag}
+		meta.ModTime, _ = time.Parse(time.RFC3339, manifest.Annotations[ocispec.AnnotationCreated])
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != OCISignatureMediaType {
+				meta.Size += uint64(layer.Size)
+			}
+		}
+		metas = append(metas, meta)
+	}
+	return &metas, nil
+}
+
+// nextPage follows a paginated listing's Link header
+func nextPage(resp *http.Response) string {
+	link := resp.Header.Get("Link")
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	u, err := resp.Request.URL.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+func (o *OCIStore) DeleteCheckpoint(ctx context.Context, cid string) error {
+	if !validCheckpointID(cid) {
+		return fmt.Errorf("invalid checkpoint id %q", cid)
+	}
+	resp, err := o.do(ctx, http.MethodDelete, o.url("manifests/sha256:"+cid), nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		// registry:2 only deletes with REGISTRY_STORAGE_DELETE_ENABLED
+		return ErrNotSupported
+	}
+	return checkRegistryStatus(resp)
+}
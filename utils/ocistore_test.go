@@ -0,0 +1,316 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fakeRegistry serves the parts of the distribution API the store uses, behind
+// a bearer token handed out for basic credentials.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	tags      map[string]string
+	uploads   int
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, *httptest.Server) {
+	reg := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}, tags: map[string]string{}}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": "secret"})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:test/ckpt:pull,push"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.serve(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return reg, srv
+}
+
+func (reg *fakeRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/test/ckpt/")
+	switch {
+	case strings.HasPrefix(path, "blobs/uploads/") && r.Method == http.MethodPost:
+		w.Header().Set("Location", "/v2/test/ckpt/blobs/uploads/"+uuid.NewString()+"?state=x")
+		w.WriteHeader(http.StatusAccepted)
+
+	case strings.HasPrefix(path, "blobs/uploads/") && r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		d := r.URL.Query().Get("digest")
+		if r.URL.Query().Get("state") != "x" || digest.FromBytes(data).String() != d {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reg.blobs[d] = data
+		reg.uploads++
+		w.WriteHeader(http.StatusCreated)
+
+	case strings.HasPrefix(path, "blobs/"):
+		data, ok := reg.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+
+	case strings.HasPrefix(path, "manifests/"):
+		ref := strings.TrimPrefix(path, "manifests/")
+		if d, ok := reg.tags[ref]; ok {
+			ref = d
+		}
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			d := digest.FromBytes(data).String()
+			reg.manifests[d] = data
+			reg.tags[ref] = d
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := reg.manifests[ref]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Write(data)
+		case http.MethodDelete:
+			if _, ok := reg.manifests[ref]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(reg.manifests, ref)
+			for tag, d := range reg.tags {
+				if d == ref {
+					delete(reg.tags, tag)
+				}
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}
+
+	case path == "tags/list":
+		var tags []string
+		for tag := range reg.tags {
+			tags = append(tags, tag)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "test/ckpt", "tags": tags})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testArchive(t *testing.T) string {
+	images := t.TempDir()
+	files := map[string][]byte{
+		"core-1.img":            randomBytes(7, 3000),
+		"checkpoint_state.json": []byte(`{"PID": 42, "GPUCheckpointed": true, "CRIUVersion": 31900}`),
+		"gpu-ckpt.bin":          randomBytes(8, 5000),
+		"pages-1.img":           randomBytes(9, 70000),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(images, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(context.Background(), images, archive); err != nil {
+		t.Fatal(err)
+	}
+	return archive
+}
+
+func newTestOCIStore(t *testing.T, cfg OCIConfig) *OCIStore {
+	cache, err := NewCheckpointCache(StoreConfig{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewOCIStore(cfg, cache, trace.NewNoopTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestOCIStore(t *testing.T) {
+	ctx := context.Background()
+	reg, srv := newFakeRegistry(t)
+	store := newTestOCIStore(t, OCIConfig{
+		Repository: strings.TrimPrefix(srv.URL, "http://") + "/test/ckpt",
+		Username:   "user",
+		Password:   "pass",
+		Insecure:   true,
+	})
+
+	archive := testArchive(t)
+	key, _ := testNodeKey(t)
+	if err := SignArchive(ctx, archive, "job/a", key); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := store.PushCheckpoint(ctx, "job/a", archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var manifest ocispec.Manifest
+	json.Unmarshal(reg.manifests["sha256:"+meta.ID], &manifest)
+	var kinds []string
+	for _, layer := range manifest.Layers {
+		kinds = append(kinds, layer.MediaType)
+	}
+	// in archive order: checkpoint_state.json, core-1.img, gpu-ckpt.bin, pages-1.img
+	expected := []string{OCIStateMediaType, OCIImagesMediaType, OCIGPUMediaType, OCIImagesMediaType, OCISignatureMediaType}
+	if strings.Join(kinds, " ") != strings.Join(expected, " ") {
+		t.Errorf("unexpected layers %v", kinds)
+	}
+	if manifest.ArtifactType != OCIArtifactType || manifest.Annotations[OCIJobIDAnnotation] != "job/a" ||
+		manifest.Annotations[OCIPIDAnnotation] != "42" || manifest.Annotations[OCICRIUVersionAnnotation] != "31900" ||
+		manifest.Annotations[OCIGPUAnnotation] != "true" {
+		t.Errorf("unexpected manifest %s %v", manifest.ArtifactType, manifest.Annotations)
+	}
+
+	// pushing again only sends what the registry lacks: the config's timestamp
+	uploads := reg.uploads
+	if _, err := store.PushCheckpoint(ctx, "job/a", archive); err != nil {
+		t.Fatal(err)
+	}
+	if reg.uploads != uploads+1 {
+		t.Errorf("expected only the config to be uploaded again, got %d uploads", reg.uploads-uploads)
+	}
+
+	store.trusted = trust(key)
+	path, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := os.ReadFile(archive)
+	if got, _ := os.ReadFile(*path); !bytes.Equal(got, want) {
+		t.Error("pulled archive differs from the one pushed")
+	}
+
+	other, _ := testNodeKey(t)
+	store.trusted = trust(other)
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected a foreign checkpoint to be rejected, got %v", err)
+	}
+	store.trusted = nil
+
+	list, err := store.ListCheckpoints(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*list) != 2 || (*list)[0].JobID != "job/a" || (*list)[0].Size != uint64(len(want)) {
+		t.Errorf("unexpected listing %+v", *list)
+	}
+
+	if err := store.DeleteCheckpoint(ctx, meta.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected ErrNotExist after delete, got %v", err)
+	}
+}
+
+func TestOCIStore_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	reg, srv := newFakeRegistry(t)
+	store := newTestOCIStore(t, OCIConfig{
+		Repository: strings.TrimPrefix(srv.URL, "http://") + "/test/ckpt",
+		Username:   "user",
+		Password:   "pass",
+		Insecure:   true,
+	})
+
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "pages-1.img"), randomBytes(10, 10000), 0o644)
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(ctx, src, archive, WithEncryption(testKey, "test-key")); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := store.PushCheckpoint(ctx, "job-a", archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest ocispec.Manifest
+	json.Unmarshal(reg.manifests["sha256:"+meta.ID], &manifest)
+	if len(manifest.Layers) != 1 || manifest.Layers[0].MediaType != OCIEncryptedMediaType {
+		t.Errorf("expected one encrypted layer, got %+v", manifest.Layers)
+	}
+
+	path, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := os.ReadFile(archive)
+	if got, _ := os.ReadFile(*path); !bytes.Equal(got, want) {
+		t.Error("pulled archive differs from the one pushed")
+	}
+}
+
+// Runs against a real registry:
+//
+//	docker run -p 5000:5000 -e REGISTRY_STORAGE_DELETE_ENABLED=true registry:2
+//	CEDANA_TEST_REGISTRY=localhost:5000 go test ./utils/
+func TestOCIStore_Registry(t *testing.T) {
+	registry := os.Getenv("CEDANA_TEST_REGISTRY")
+	if registry == "" {
+		t.Skip("CEDANA_TEST_REGISTRY not set")
+	}
+
+	ctx := context.Background()
+	store := newTestOCIStore(t, OCIConfig{Repository: registry + "/cedana-test/" + uuid.NewString(), Insecure: true})
+
+	archive := testArchive(t)
+	meta, err := store.PushCheckpoint(ctx, "job-a", archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := os.ReadFile(archive)
+	if got, _ := os.ReadFile(*path); !bytes.Equal(got, want) {
+		t.Error("pulled archive differs from the one pushed")
+	}
+
+	list, err := store.ListCheckpoints(ctx)
+	if err != nil || len(*list) != 1 || (*list)[0].ID != meta.ID {
+		t.Errorf("unexpected listing %v, %v", list, err)
+	}
+	if err := store.DeleteCheckpoint(ctx, meta.ID); err != nil && !errors.Is(err, ErrNotSupported) {
+		t.Fatal(err)
+	}
+}
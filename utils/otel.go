@@ -3,7 +3,6 @@ package utils
 import (
 	"context"
 	"errors"
-	"os"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
@@ -16,9 +15,8 @@ import (
 
 // setupOTelSDK bootstraps the OpenTelemetry pipeline.
 // If it does not return an error, make sure to call shutdown for proper cleanup.
-func InitOtel(ctx context.Context, version string) (shutdown func(context.Context) error, err error) {
-	telemetryOn := os.Getenv("CEDANA_OTEL_ENABLED") == "true"
-	if !telemetryOn {
+func InitOtel(ctx context.Context, version string, cfg OtelConfig) (shutdown func(context.Context) error, err error) {
+	if !cfg.Enabled {
 		otel.SetTracerProvider(noop.NewTracerProvider())
 		return
 	}
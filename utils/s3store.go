@@ -0,0 +1,376 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultS3PartSize    = 64 << 20
+	defaultS3Concurrency = 4
+
+	// user metadata key holding the sha256 of the whole tarball
+	s3ChecksumKey = "Cedana-Sha256"
+	s3JobKey      = "Cedana-Job"
+)
+
+// S3Store pushes checkpoints to an S3-compatible bucket (AWS, MinIO, GCS's
+// S3 API, ...). Objects are laid out per job as
+//
+//	<prefix>/<job id>/<checkpoint id>.tar
+//
+// or <prefix>/<checkpoint id>.tar for checkpoints that aren't tied to a job,
+// with a small <prefix>/.index/<checkpoint id> object pointing at the tarball,
+// so a checkpoint can be found from its ID alone. A signed checkpoint's
+// manifest is kept beside the tarball, as <checkpoint id>.tar.manifest.
+type S3Store struct {
+	client *minio.Client
+	cfg    S3Config
+	cache  *CheckpointCache
+	tracer trace.Tracer
+}
+
+func NewS3Store(cfg S3Config, cache *CheckpointCache, tracer trace.Tracer) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("store.s3.endpoint and store.s3.bucket must be set for the s3 store")
+	}
+
+	// static keys from config win, otherwise the usual AWS env vars, shared
+	// credentials file or instance role
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.Static{Value: credentials.Value{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+			SignerType:      credentials.SignatureV4,
+		}},
+		&credentials.EnvAWS{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{},
+	})
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: !cfg.Insecure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.PartSize <= 0 {
+		cfg.PartSize = defaultS3PartSize
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultS3Concurrency
+	}
+
+	return &S3Store{client: client, cfg: cfg, cache: cache, tracer: tracer}, nil
+}
+
+func (s *S3Store) key(parts ...string) string {
+	return path.Join(append([]string{s.cfg.Prefix}, parts...)...)
+}
+
+func (s *S3Store) indexKey(cid string) string {
+	return s.key(".index", cid)
+}
+
+// validS3JobID reports whether jobID can name a directory of its own under
+// the prefix: a single path segment that isn't hidden, as .index and .chunks
+// hold the store's own objects. "" is the checkpoints that aren't tied to a
+// job.
+func validS3JobID(jobID string) bool {
+	return !strings.Contains(jobID, "/") && !strings.HasPrefix(jobID, ".")
+}
+
+// PushCheckpoint uploads in parts of cfg.PartSize, cfg.Concurrency at a time.
+// Every part carries a Content-MD5 the server checks on receipt; the sha256
+// of the whole tarball goes in the object's metadata for downloads to check.
+func (s *S3Store) PushCheckpoint(ctx context.Context, jobID, checkpointPath string) (*CheckpointMeta, error) {
+	if !validS3JobID(jobID) {
+		return nil, fmt.Errorf("invalid job id %q for the s3 store", jobID)
+	}
+
+	ctx, pushSpan := s.tracer.Start(ctx, "S3PushCheckpoint")
+	defer pushSpan.End()
+
+	f, err := os.Open(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := fileSHA256(ctx, checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cid := uuid.New().String()
+	objectKey := s.key(jobID, cid+".tar")
+
+	uploaded, err := s.client.PutObject(ctx, s.cfg.Bucket, objectKey, f, info.Size(), minio.PutObjectOptions{
+		ContentType:    "application/x-tar",
+		PartSize:       uint64(s.cfg.PartSize),
+		NumThreads:     uint(s.cfg.Concurrency),
+		SendContentMd5: true,
+		UserMetadata: map[string]string{
+			s3ChecksumKey: checksum,
+			s3JobKey:      jobID,
+		},
+	})
+	if err != nil {
+		pushSpan.RecordError(err)
+		return nil, err
+	}
+
+	if signed, err := os.ReadFile(checkpointPath + manifestSuffix); err == nil {
+		_, err := s.client.PutObject(ctx, s.cfg.Bucket, objectKey+manifestSuffix, bytes.NewReader(signed), int64(len(signed)), minio.PutObjectOptions{ContentType: "application/json"})
+		if err != nil {
+			pushSpan.RecordError(err)
+			s.client.RemoveObject(context.Background(), s.cfg.Bucket, objectKey, minio.RemoveObjectOptions{})
+			return nil, fmt.Errorf("pushing signed manifest: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	index := strings.NewReader(objectKey)
+	if _, err := s.client.PutObject(ctx, s.cfg.Bucket, s.indexKey(cid), index, index.Size(), minio.PutObjectOptions{ContentType: "text/plain"}); err != nil {
+		pushSpan.RecordError(err)
+		// without its index the tarball can't be found, so don't leave it behind
+		s.client.RemoveObject(context.Background(), s.cfg.Bucket, objectKey, minio.RemoveObjectOptions{})
+		s.client.RemoveObject(context.Background(), s.cfg.Bucket, objectKey+manifestSuffix, minio.RemoveObjectOptions{})
+		return nil, err
+	}
+
+	return &CheckpointMeta{
+		ID:       cid,
+		JobID:    jobID,
+		Name:     path.Base(objectKey),
+		Bucket:   s.cfg.Bucket,
+		ModTime:  uploaded.LastModified,
+		Size:     uint64(uploaded.Size),
+		Checksum: "sha256:" + checksum,
+	}, nil
+}
+
+// objectKey resolves a checkpoint ID through the index.
+func (s *S3Store) objectKey(ctx context.Context, cid string) (string, error) {
+	if _, err := uuid.Parse(cid); err != nil {
+		return "", fmt.Errorf("invalid checkpoint id %q", cid)
+	}
+
+	obj, err := s.client.GetObject(ctx, s.cfg.Bucket, s.indexKey(cid), minio.GetObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(io.LimitReader(obj, 4096))
+	if isNotFound(err) {
+		return "", fmt.Errorf("checkpoint %s: %w", cid, os.ErrNotExist)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetCheckpoint downloads the tarball into the cache as parallel ranged GETs,
+// then checks it against the sha256 recorded at upload. Its signed manifest,
+// if it has one, comes along.
+func (s *S3Store) GetCheckpoint(ctx context.Context, cid string) (*string, func(), error) {
+	ctx, getSpan := s.tracer.Start(ctx, "S3GetCheckpoint")
+	defer getSpan.End()
+
+	objectKey, err := s.objectKey(ctx, cid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path, release, err := s.cache.Get(ctx, cid, func(ctx context.Context, partial string) error {
+		info, err := s.client.StatObject(ctx, s.cfg.Bucket, objectKey, minio.StatObjectOptions{})
+		if isNotFound(err) {
+			return fmt.Errorf("checkpoint %s: %w", cid, os.ErrNotExist)
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.getManifest(ctx, objectKey, partial+manifestSuffix); err != nil {
+			return err
+		}
+
+		err = rangedDownload(ctx, partial, info.Size, info.ETag, s.cfg.PartSize, s.cfg.Concurrency, func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+			opts := minio.GetObjectOptions{}
+			if err := opts.SetRange(start, end); err != nil {
+				return nil, err
+			}
+			// a checkpoint is never rewritten in place, but don't stitch
+			// together two different objects if it somehow were
+			if err := opts.SetMatchETag(info.ETag); err != nil {
+				return nil, err
+			}
+			return s.client.GetObject(ctx, s.cfg.Bucket, objectKey, opts)
+		})
+		if err != nil {
+			return err
+		}
+
+		if want := info.UserMetadata[s3ChecksumKey]; want != "" {
+			return verifyChecksum(ctx, partial, want)
+		}
+		return nil
+	})
+	if err != nil {
+		getSpan.RecordError(err)
+		return nil, nil, err
+	}
+	return &path, release, nil
+}
+
+// getManifest downloads the signed manifest of the tarball at objectKey to
+// path, unless it has none.
+func (s *S3Store) getManifest(ctx context.Context, objectKey, path string) error {
+	obj, err := s.client.GetObject(ctx, s.cfg.Bucket, objectKey+manifestSuffix, minio.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(io.LimitReader(obj, maxManifestSize))
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ListCheckpoints lists every checkpoint under the prefix, including those
+// that aren't tied to a job. Checksums aren't included since they'd take a
+// request per checkpoint.
+func (s *S3Store) ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) {
+	checkpoints := []CheckpointMeta{}
+
+	prefix := s.cfg.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	for obj := range s.client.ListObjects(ctx, s.cfg.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		rel := strings.TrimPrefix(obj.Key, prefix)
+		jobID, name := path.Split(rel)
+		if strings.HasPrefix(rel, ".index/") || !strings.HasSuffix(name, ".tar") {
+			continue
+		}
+
+		checkpoints = append(checkpoints, CheckpointMeta{
+			ID:      strings.TrimSuffix(name, ".tar"),
+			JobID:   strings.TrimSuffix(jobID, "/"),
+			Name:    name,
+			Bucket:  s.cfg.Bucket,
+			ModTime: obj.LastModified,
+			Size:    uint64(obj.Size),
+		})
+	}
+
+	return &checkpoints, nil
+}
+
+func (s *S3Store) DeleteCheckpoint(ctx context.Context, cid string) error {
+	objectKey, err := s.objectKey(ctx, cid)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range []string{objectKey, objectKey + manifestSuffix} {
+		if err := s.client.RemoveObject(ctx, s.cfg.Bucket, key, minio.RemoveObjectOptions{}); err != nil {
+			return err
+		}
+	}
+	return s.client.RemoveObject(ctx, s.cfg.Bucket, s.indexKey(cid), minio.RemoveObjectOptions{})
+}
+
+func (s *S3Store) chunkKey(digest string) string {
+	return s.key(".chunks", digest[:2], digest)
+}
+
+func (s *S3Store) HasChunk(ctx context.Context, digest string) (string, bool, error) {
+	if !validCheckpointID(digest) {
+		return "", false, fmt.Errorf("invalid chunk digest %q", digest)
+	}
+	// objects can't be touched, so a found chunk is copied over itself to
+	// bump its modification time
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.cfg.Bucket, Object: s.chunkKey(digest), ReplaceMetadata: true},
+		minio.CopySrcOptions{Bucket: s.cfg.Bucket, Object: s.chunkKey(digest)})
+	if isNotFound(err) {
+		return "", false, nil
+	}
+	return "", err == nil, err
+}
+
+func (s *S3Store) PutChunk(ctx context.Context, digest string, data []byte) (string, error) {
+	if !validCheckpointID(digest) {
+		return "", fmt.Errorf("invalid chunk digest %q", digest)
+	}
+	_, err := s.client.PutObject(ctx, s.cfg.Bucket, s.chunkKey(digest), bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType:    "application/octet-stream",
+		SendContentMd5: true,
+	})
+	return "", err
+}
+
+func (s *S3Store) GetChunk(ctx context.Context, ref ChunkRef) (io.ReadCloser, error) {
+	if !validCheckpointID(ref.Digest) {
+		return nil, fmt.Errorf("invalid chunk digest %q", ref.Digest)
+	}
+	return s.client.GetObject(ctx, s.cfg.Bucket, s.chunkKey(ref.Digest), minio.GetObjectOptions{})
+}
+
+func (s *S3Store) ListChunks(ctx context.Context) ([]ChunkInfo, error) {
+	chunks := []ChunkInfo{}
+	for obj := range s.client.ListObjects(ctx, s.cfg.Bucket, minio.ListObjectsOptions{Prefix: s.key(".chunks") + "/", Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		if digest := path.Base(obj.Key); validCheckpointID(digest) {
+			chunks = append(chunks, ChunkInfo{Digest: digest, ModTime: obj.LastModified})
+		}
+	}
+	return chunks, nil
+}
+
+func (s *S3Store) DeleteChunk(ctx context.Context, digest string) error {
+	if !validCheckpointID(digest) {
+		return fmt.Errorf("invalid chunk digest %q", digest)
+	}
+	return s.client.RemoveObject(ctx, s.cfg.Bucket, s.chunkKey(digest), minio.RemoveObjectOptions{})
+}
+
+// isNotFound reports whether err is S3 saying the object doesn't exist
+func isNotFound(err error) bool {
+	return err != nil && minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
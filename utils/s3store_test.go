@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Runs against a real S3-compatible service, e.g. a local MinIO:
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	CEDANA_TEST_S3_ENDPOINT=localhost:9000 CEDANA_TEST_S3_BUCKET=test \
+//	AWS_ACCESS_KEY_ID=minioadmin AWS_SECRET_ACCESS_KEY=minioadmin go test ./utils/
+func TestS3Store(t *testing.T) {
+	endpoint := os.Getenv("CEDANA_TEST_S3_ENDPOINT")
+	bucket := os.Getenv("CEDANA_TEST_S3_BUCKET")
+	if endpoint == "" || bucket == "" {
+		t.Skip("CEDANA_TEST_S3_ENDPOINT and CEDANA_TEST_S3_BUCKET not set")
+	}
+
+	ctx := context.Background()
+	cache, err := NewCheckpointCache(StoreConfig{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewS3Store(S3Config{
+		Endpoint:    endpoint,
+		Bucket:      bucket,
+		Prefix:      "cedana-test-" + uuid.NewString(),
+		Insecure:    true,
+		PartSize:    5 << 20, // the S3 minimum, so the upload is multipart
+		Concurrency: 3,
+	}, cache, trace.NewNoopTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, err := store.client.BucketExists(ctx, bucket); err != nil {
+		t.Fatal(err)
+	} else if !exists {
+		t.Fatalf("bucket %s does not exist", bucket)
+	}
+
+	data := make([]byte, 12<<20+123)
+	rand.Read(data)
+	src := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := os.WriteFile(src, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := testNodeKey(t)
+	if err := SignArchive(ctx, src, "job-a", key); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := store.PushCheckpoint(ctx, "job-a", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.DeleteCheckpoint(context.Background(), meta.ID) })
+
+	path, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(*path)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("downloaded checkpoint differs from upload (err %v)", err)
+	}
+	if _, err := VerifyArchive(ctx, *path, trust(key)); err != nil {
+		t.Errorf("expected the signed manifest to come with the checkpoint, got %v", err)
+	}
+
+	// e.g. a container dump, which has no job
+	unowned, err := store.PushCheckpoint(ctx, "", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.DeleteCheckpoint(context.Background(), unowned.ID) })
+
+	list, err := store.ListCheckpoints(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := map[string]string{}
+	for _, c := range *list {
+		jobs[c.ID] = c.JobID
+	}
+	if len(jobs) != 2 || jobs[meta.ID] != "job-a" {
+		t.Fatalf("unexpected listing %+v", *list)
+	}
+	if job, ok := jobs[unowned.ID]; !ok || job != "" {
+		t.Fatalf("expected the checkpoint without a job to be listed, got %+v", *list)
+	}
+
+	if err := store.DeleteCheckpoint(ctx, meta.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected deleted checkpoint to be gone, got %v", err)
+	}
+}
+
+// job ids become a directory under the prefix, which they mustn't escape
+func TestS3Store_InvalidJobIDs(t *testing.T) {
+	store, err := NewS3Store(S3Config{Endpoint: "localhost:9000", Bucket: "test", Prefix: "checkpoints"}, nil, trace.NewNoopTracerProvider().Tracer("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := os.WriteFile(src, []byte("tar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, jobID := range []string{"..", "../other", ".index", ".chunks", "a/b", "/job"} {
+		if _, err := store.PushCheckpoint(context.Background(), jobID, src); err == nil || !strings.Contains(err.Error(), "invalid job id") {
+			t.Errorf("%q: expected the job id to be rejected, got %v", jobID, err)
+		}
+	}
+}
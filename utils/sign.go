@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureAlgorithm = "ed25519"
+
+	defaultNodeKeyFile = "/etc/cedana/node_key"
+	// a local archive's signed manifest sits next to it, <archive>.manifest
+	manifestSuffix = ".manifest"
+)
+
+// ErrUntrustedCheckpoint is wrapped by every verification failure: unsigned,
+// badly signed or signed by a key that isn't trusted.
+var ErrUntrustedCheckpoint = errors.New("checkpoint is not signed by a trusted key")
+
+type ManifestSignature struct {
+	Algorithm string `json:"algorithm"`
+	// base64 public key of the node that produced the checkpoint
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// signedBytes is what a manifest's signature covers: everything but the chunk
+// list, which is bound to the image by the checksum instead. That way the
+// signature made over an archive at dump time carries over unchanged into the
+// manifest of the chunked checkpoint pushed from it.
+func (m *Manifest) signedBytes() ([]byte, error) {
+	signed := *m
+	signed.Chunks = nil
+	signed.Signature = nil
+	return json.Marshal(&signed)
+}
+
+// Sign signs the manifest with the node's key
+func (m *Manifest) Sign(key ed25519.PrivateKey) error {
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	m.Signature = &ManifestSignature{
+		Algorithm: SignatureAlgorithm,
+		PublicKey: PublicKeyString(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)),
+	}
+	return nil
+}
+
+// Signer checks the signature is valid and returns the key that made it,
+// without regard to whether that key is trusted.
+func (m *Manifest) Signer() (ed25519.PublicKey, error) {
+	sig := m.Signature
+	if sig == nil {
+		return nil, fmt.Errorf("%w: checkpoint is unsigned", ErrUntrustedCheckpoint)
+	}
+	if sig.Algorithm != SignatureAlgorithm {
+		return nil, fmt.Errorf("%w: unsupported signature algorithm %q", ErrUntrustedCheckpoint, sig.Algorithm)
+	}
+	pub, err := parsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUntrustedCheckpoint, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrUntrustedCheckpoint)
+	}
+	data, err := m.signedBytes()
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(pub, data, signature) {
+		return nil, fmt.Errorf("%w: signature doesn't match, the manifest was modified after signing", ErrUntrustedCheckpoint)
+	}
+	return pub, nil
+}
+
+// Verify checks the manifest is validly signed by one of trusted. A nil
+// trusted only checks that the signature is valid, whoever made it.
+func (m *Manifest) Verify(trusted TrustedKeys) error {
+	pub, err := m.Signer()
+	if err != nil {
+		return err
+	}
+	if trusted != nil && !trusted[PublicKeyString(pub)] {
+		return fmt.Errorf("%w: signed by %s", ErrUntrustedCheckpoint, PublicKeyString(pub))
+	}
+	return nil
+}
+
+func PublicKeyString(pub ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+func parsePublicKey(s string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%q is not a base64 ed25519 public key", s)
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+func nodeKeyFile(path string) string {
+	if path == "" {
+		return defaultNodeKeyFile
+	}
+	return path
+}
+
+func readNodeKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%s is not a node key", path)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// LoadNodeKey returns the node's signing key, generating it on first use. The
+// file holds the base64 ed25519 seed and is only readable by root.
+func LoadNodeKey(path string) (ed25519.PrivateKey, error) {
+	path = nodeKeyFile(path)
+	key, err := readNodeKey(path)
+	if !errors.Is(err, os.ErrNotExist) {
+		return key, err
+	}
+
+	_, key, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating node key: %w", err)
+	}
+	// written in full before it's linked into place, so a concurrent
+	// LoadNodeKey never reads a half-written key
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path)+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating node key: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(base64.StdEncoding.EncodeToString(key.Seed()) + "\n")
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating node key: %w", err)
+	}
+	// unlike a rename, linking fails if the key was generated concurrently,
+	// so every caller ends up with the same key
+	if err := os.Link(tmp.Name(), path); errors.Is(err, os.ErrExist) {
+		return readNodeKey(path)
+	} else if err != nil {
+		return nil, fmt.Errorf("creating node key: %w", err)
+	}
+	return key, nil
+}
+
+// TrustedKeys is a set of base64 public keys checkpoints may be signed by
+type TrustedKeys map[string]bool
+
+// NewTrustedKeys returns the keys restores accept checkpoints from, or nil
+// when cfg trusts none and signatures aren't enforced. The node's own key is
+// trusted along with the configured ones.
+func NewTrustedKeys(cfg Signing) (TrustedKeys, error) {
+	if len(cfg.TrustedKeys) == 0 {
+		return nil, nil
+	}
+	trusted := TrustedKeys{}
+	for _, s := range cfg.TrustedKeys {
+		pub, err := parsePublicKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("signing.trusted_keys: %w", err)
+		}
+		trusted[PublicKeyString(pub)] = true
+	}
+
+	own, err := readNodeKey(nodeKeyFile(cfg.KeyFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if own != nil {
+		trusted[PublicKeyString(own.Public().(ed25519.PublicKey))] = true
+	}
+	return trusted, nil
+}
+
+// SignArchive writes a manifest for the archive at path, signed with key, to
+// <path>.manifest.
+func SignArchive(ctx context.Context, path, jobID string, key ed25519.PrivateKey) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	checksum, err := fileSHA256(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	manifest := &Manifest{
+		Format:   manifestFormat,
+		JobID:    jobID,
+		Created:  time.Now().UTC(),
+		Size:     info.Size(),
+		Checksum: "sha256:" + checksum,
+	}
+	if header, err := ReadEncryptionHeaderFile(path); err == nil {
+		manifest.Encryption = &ManifestEncryption{Algorithm: header.Algorithm, KeyID: header.KeyID}
+	} else if !errors.Is(err, ErrNotEncrypted) {
+		return err
+	}
+	if err := manifest.Sign(key); err != nil {
+		return err
+	}
+	return writeManifest(path, manifest)
+}
+
+// writeManifest writes manifest next to the archive at path
+func writeManifest(path string, manifest *Manifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+manifestSuffix, data, 0o644)
+}
+
+// VerifyArchive checks the archive at path against its signed manifest, and
+// that the manifest is signed by one of trusted.
+func VerifyArchive(ctx context.Context, path string, trusted TrustedKeys) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return verifyArchiveFile(ctx, f, path, trusted)
+}
+
+// verifyArchiveFile is VerifyArchive for the archive already open as f. It's
+// left at its start.
+func verifyArchiveFile(ctx context.Context, f *os.File, path string, trusted TrustedKeys) (*Manifest, error) {
+	manifest, err := readManifest(path + manifestSuffix)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, errNotManifest) {
+		return nil, fmt.Errorf("%w: %s has no signed manifest", ErrUntrustedCheckpoint, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.Verify(trusted); err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, &ctxReader{ctx: ctx, r: f}); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if info.Size() != manifest.Size || "sha256:"+checksum != manifest.Checksum {
+		return nil, fmt.Errorf("%w: %s doesn't match its signed manifest", ErrUntrustedCheckpoint, path)
+	}
+	return manifest, nil
+}
+
+// VerifyCheckpointFile verifies either a local archive, against the manifest
+// next to it, or a chunked checkpoint's manifest on its own. The chunks of the
+// latter are checked against the signed checksum when they're assembled.
+func VerifyCheckpointFile(ctx context.Context, path string, trusted TrustedKeys) (*Manifest, error) {
+	manifest, err := readManifest(path)
+	if err == nil {
+		return manifest, manifest.Verify(trusted)
+	}
+	if !errors.Is(err, errNotManifest) {
+		return nil, err
+	}
+	return VerifyArchive(ctx, path, trusted)
+}
@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func testNodeKey(t *testing.T) (ed25519.PrivateKey, string) {
+	path := filepath.Join(t.TempDir(), "node_key")
+	key, err := LoadNodeKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, path
+}
+
+func trust(keys ...ed25519.PrivateKey) TrustedKeys {
+	trusted := TrustedKeys{}
+	for _, key := range keys {
+		trusted[PublicKeyString(key.Public().(ed25519.PublicKey))] = true
+	}
+	return trusted
+}
+
+func TestLoadNodeKey(t *testing.T) {
+	key, path := testNodeKey(t)
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a 0600 key file, got %v, %v", info, err)
+	}
+	again, err := LoadNodeKey(path)
+	if err != nil || !key.Equal(again) {
+		t.Errorf("expected the generated key to be reused, got %v", err)
+	}
+
+	other, _ := testNodeKey(t)
+	trusted, err := NewTrustedKeys(Signing{KeyFile: path, TrustedKeys: []string{PublicKeyString(other.Public().(ed25519.PublicKey))}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trusted) != 2 || !trusted[PublicKeyString(key.Public().(ed25519.PublicKey))] {
+		t.Errorf("expected the configured key and the node's own, got %v", trusted)
+	}
+
+	if trusted, err := NewTrustedKeys(Signing{KeyFile: path}); trusted != nil || err != nil {
+		t.Errorf("expected no enforcement without trusted keys, got %v, %v", trusted, err)
+	}
+	if _, err := NewTrustedKeys(Signing{TrustedKeys: []string{"not a key"}}); err == nil {
+		t.Error("expected a malformed trusted key to be an error")
+	}
+}
+
+func TestLoadNodeKey_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node_key")
+	keys := make([]ed25519.PrivateKey, 8)
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			keys[i], errs[i] = LoadNodeKey(path)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, key := range keys {
+		if errs[i] != nil || !key.Equal(keys[0]) {
+			t.Fatalf("expected every caller to get the same key, got %v", errs[i])
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected only the key to be left, found %d files", len(entries))
+	}
+}
+
+func TestSignArchive(t *testing.T) {
+	ctx := context.Background()
+	key, _ := testNodeKey(t)
+	other, _ := testNodeKey(t)
+
+	archive := writeFile(t, []byte("checkpoint archive"))
+	if err := SignArchive(ctx, archive, "job-a", key); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := VerifyArchive(ctx, archive, trust(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.JobID != "job-a" || manifest.Size != int64(len("checkpoint archive")) {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+	if _, err := VerifyCheckpointFile(ctx, archive, nil); err != nil {
+		t.Errorf("expected a valid signature to pass without trusted keys, got %v", err)
+	}
+
+	if _, err := VerifyArchive(ctx, archive, trust(other)); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected a foreign signer to be rejected, got %v", err)
+	}
+
+	// a re-signed manifest doesn't vouch for a different archive
+	tampered := writeFile(t, []byte("tampered archive!!"))
+	data, _ := os.ReadFile(archive + manifestSuffix)
+	os.WriteFile(tampered+manifestSuffix, data, 0o644)
+	if _, err := VerifyArchive(ctx, tampered, trust(key)); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected a modified archive to be rejected, got %v", err)
+	}
+
+	manifest.JobID = "job-b"
+	if err := manifest.Verify(trust(key)); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected a modified manifest to be rejected, got %v", err)
+	}
+
+	if _, err := VerifyArchive(ctx, writeFile(t, []byte("unsigned")), trust(key)); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected an unsigned archive to be rejected, got %v", err)
+	}
+}
+
+func TestUntarFolder_TrustedKeys(t *testing.T) {
+	ctx := context.Background()
+	key, _ := testNodeKey(t)
+	other, _ := testNodeKey(t)
+
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "pages-1.img"), []byte("pages"), 0o600)
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := TarFolder(ctx, src, archive); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UntarFolder(ctx, archive, t.TempDir(), WithTrustedKeys(trust(key))); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected an unsigned archive to be refused, got %v", err)
+	}
+	if err := SignArchive(ctx, archive, "job-a", key); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := UntarFolder(ctx, archive, dest, WithTrustedKeys(trust(other))); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected a foreign archive to be refused, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dest); len(entries) != 0 {
+		t.Errorf("expected nothing to be extracted from a refused archive, got %v", entries)
+	}
+	if err := UntarFolder(ctx, archive, dest, WithTrustedKeys(trust(key))); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "pages-1.img")); string(data) != "pages" {
+		t.Errorf("expected the archive to be extracted, got %q", data)
+	}
+}
+
+func TestChunkedStore_Signatures(t *testing.T) {
+	ctx := context.Background()
+	key, _ := testNodeKey(t)
+	other, _ := testNodeKey(t)
+
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newTestChunkedStore(t, local)
+
+	archive := writeFile(t, randomBytes(5, 2<<20))
+	if err := SignArchive(ctx, archive, "job-a", key); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := store.PushCheckpoint(ctx, "job-a", archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the dump-time signature carries over to the pushed manifest
+	path, _, _ := local.GetCheckpoint(ctx, meta.ID)
+	if _, err := VerifyCheckpointFile(ctx, *path, trust(key)); err != nil {
+		t.Fatalf("pushed manifest doesn't verify: %v", err)
+	}
+
+	store.trusted = trust(other)
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected a foreign checkpoint to be rejected, got %v", err)
+	}
+	store.trusted = trust(key, other)
+	if _, _, err := store.GetCheckpoint(ctx, meta.ID); err != nil {
+		t.Errorf("expected a trusted checkpoint to restore, got %v", err)
+	}
+
+	// restores verify the image against the manifest left beside it
+	image, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyArchive(ctx, *image, trust(key)); err != nil {
+		t.Errorf("expected the reassembled image to verify, got %v", err)
+	}
+
+	// checkpoints pushed whole keep their signed manifest beside them
+	signed := writeFile(t, []byte("a signed tarball"))
+	if err := SignArchive(ctx, signed, "job-a", key); err != nil {
+		t.Fatal(err)
+	}
+	whole, err := local.PushCheckpoint(ctx, "job-a", signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _, err = store.GetCheckpoint(ctx, whole.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyArchive(ctx, *path, trust(key)); err != nil {
+		t.Errorf("expected a signed whole checkpoint to verify, got %v", err)
+	}
+
+	unsigned, err := local.PushCheckpoint(ctx, "job-a", writeFile(t, []byte("a plain tarball")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _, err = store.GetCheckpoint(ctx, unsigned.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyArchive(ctx, *path, trust(key)); !errors.Is(err, ErrUntrustedCheckpoint) {
+		t.Errorf("expected an unsigned whole checkpoint to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Page images of big, lightly used heaps are mostly zero pages. WriteTar packs
+// them sparsely: only the runs of blocks with data in them are archived, and
+// the extractor leaves holes for the rest, so the zeros cost nothing to write,
+// compress, upload or extract.
+//
+// archive/tar can't write GNU sparse entries, so sparse page images are
+// entries of their own type, which readers that predate them refuse rather
+// than extracting a corrupt image. Their data is a map, then the runs it lists
+// back to back. The map is the file's real size, the number of runs, then each
+// run's offset and length, all little endian uint64s.
+const (
+	typeSparse byte = 'P'
+	// the page size, which is what CRIU dumps memory in
+	sparseBlockSize = 4096
+	sparseMapHeader = 16
+	sparseMapRun    = 16
+)
+
+var zeroBlock [sparseBlockSize]byte
+
+type sparseRun struct {
+	off, size int64
+}
+
+// isPagesImage is whether name is one of CRIU's memory dumps
+func isPagesImage(name string) bool {
+	matched, _ := filepath.Match("pages-*.img", filepath.Base(name))
+	return matched
+}
+
+// sparseRuns scans the first size bytes of r for the runs of blocks that
+// aren't all zeros.
+func sparseRuns(ctx context.Context, r io.Reader, size int64) ([]sparseRun, error) {
+	var runs []sparseRun
+	buf := make([]byte, 256*sparseBlockSize)
+	for off := int64(0); off < size; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := int64(len(buf))
+		if n > size-off {
+			n = size - off
+		}
+		if _, err := io.ReadFull(r, buf[:n]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		for i := int64(0); i < n; i += sparseBlockSize {
+			block := buf[i:n]
+			if len(block) > sparseBlockSize {
+				block = block[:sparseBlockSize]
+			}
+			if bytes.Equal(block, zeroBlock[:len(block)]) {
+				continue
+			}
+			if last := len(runs) - 1; last >= 0 && runs[last].off+runs[last].size == off+i {
+				runs[last].size += int64(len(block))
+			} else {
+				runs = append(runs, sparseRun{off: off + i, size: int64(len(block))})
+			}
+		}
+		off += n
+	}
+	return runs, nil
+}
+
+func sparseMap(size int64, runs []sparseRun) []byte {
+	m := make([]byte, sparseMapHeader+sparseMapRun*len(runs))
+	binary.LittleEndian.PutUint64(m, uint64(size))
+	binary.LittleEndian.PutUint64(m[8:], uint64(len(runs)))
+	for i, run := range runs {
+		binary.LittleEndian.PutUint64(m[sparseMapHeader+sparseMapRun*i:], uint64(run.off))
+		binary.LittleEndian.PutUint64(m[sparseMapHeader+sparseMapRun*i+8:], uint64(run.size))
+	}
+	return m
+}
+
+// writeSparse writes the page image at path to tw under header, packed
+// sparsely if that makes it smaller, and as it is otherwise.
+func writeSparse(ctx context.Context, tw *tar.Writer, header *tar.Header, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	runs, err := sparseRuns(ctx, f, header.Size)
+	if err != nil {
+		return err
+	}
+	m := sparseMap(header.Size, runs)
+	packed := int64(len(m))
+	for _, run := range runs {
+		packed += run.size
+	}
+	if packed >= header.Size {
+		runs = []sparseRun{{off: 0, size: header.Size}}
+	} else {
+		header.Typeflag = typeSparse
+		header.Size = packed
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if header.Typeflag == typeSparse {
+		if _, err := tw.Write(m); err != nil {
+			return err
+		}
+	}
+	for _, run := range runs {
+		if _, err := io.Copy(tw, &ctxReader{ctx: ctx, r: io.NewSectionReader(f, run.off, run.size)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSparseMap reads a sparse entry's map from r, checking it describes a
+// file of no more than maxSize bytes whose runs fill the rest of the entry's
+// size bytes. It returns the file's real size and its runs.
+func readSparseMap(r io.Reader, size, maxSize int64) (int64, []sparseRun, error) {
+	head := make([]byte, sparseMapHeader)
+	if size < sparseMapHeader {
+		return 0, nil, unsafeArchive("malformed sparse map")
+	}
+	if _, err := io.ReadFull(r, head); err != nil {
+		return 0, nil, err
+	}
+	realSize := binary.LittleEndian.Uint64(head)
+	count := binary.LittleEndian.Uint64(head[8:])
+	if realSize > uint64(maxSize) {
+		return 0, nil, unsafeArchive("%d bytes is over the %d byte limit for a file", realSize, maxSize)
+	}
+	data := size - sparseMapHeader
+	if count > uint64(data/sparseMapRun) {
+		return 0, nil, unsafeArchive("malformed sparse map")
+	}
+	data -= int64(count) * sparseMapRun
+
+	// read a block's worth of runs at a time, so a map's memory is bounded by
+	// what's actually in the archive
+	var runs []sparseRun
+	buf := make([]byte, 256*sparseMapRun)
+	end := uint64(0)
+	for left := count; left > 0; {
+		n := left
+		if n > 256 {
+			n = 256
+		}
+		if _, err := io.ReadFull(r, buf[:n*sparseMapRun]); err != nil {
+			return 0, nil, err
+		}
+		for i := uint64(0); i < n; i++ {
+			off := binary.LittleEndian.Uint64(buf[i*sparseMapRun:])
+			length := binary.LittleEndian.Uint64(buf[i*sparseMapRun+8:])
+			if off < end || length == 0 || off > realSize || length > realSize-off {
+				return 0, nil, unsafeArchive("malformed sparse map")
+			}
+			end = off + length
+			data -= int64(length)
+			runs = append(runs, sparseRun{off: int64(off), size: int64(length)})
+		}
+		left -= n
+	}
+	if data != 0 {
+		return 0, nil, unsafeArchive("sparse map doesn't match its data")
+	}
+	return int64(realSize), runs, nil
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestTarFolder_Sparse(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+
+	// leading, interior and trailing holes, and a short last block
+	pages := make([]byte, 64*sparseBlockSize+100)
+	copy(pages[3*sparseBlockSize:], randomBytes(1, 2*sparseBlockSize))
+	copy(pages[40*sparseBlockSize+17:], randomBytes(2, 100))
+	copy(pages[64*sparseBlockSize:], randomBytes(3, 100))
+	os.WriteFile(filepath.Join(src, "pages-1.img"), pages, 0o644)
+	os.WriteFile(filepath.Join(src, "pages-2.img"), make([]byte, 20*sparseBlockSize), 0o644)
+	// only page images are packed
+	os.WriteFile(filepath.Join(src, "zeros.img"), make([]byte, 4*sparseBlockSize), 0o644)
+
+	for _, spec := range []string{"none", "lz4"} {
+		compression, _ := ParseCompression(spec)
+		archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+		var stats TarStats
+		if err := TarFolder(ctx, src, archive, WithCompression(compression), WithStats(&stats)); err != nil {
+			t.Fatal(err)
+		}
+		if fi, _ := os.Stat(archive); fi.Size() > 20*sparseBlockSize {
+			t.Errorf("%s: expected the zero pages to be left out, archive is %d bytes", spec, fi.Size())
+		}
+		if stats.Size > 20*sparseBlockSize {
+			t.Errorf("%s: expected the zero pages to be left out, tarball is %d bytes", spec, stats.Size)
+		}
+
+		dest := t.TempDir()
+		if err := UntarFolder(ctx, archive, dest); err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range map[string][]byte{
+			"pages-1.img": pages,
+			"pages-2.img": make([]byte, 20*sparseBlockSize),
+			"zeros.img":   make([]byte, 4*sparseBlockSize),
+		} {
+			got, err := os.ReadFile(filepath.Join(dest, name))
+			if err != nil || !bytes.Equal(got, want) {
+				t.Errorf("%s: %s wasn't extracted as it was: %v", spec, name, err)
+			}
+		}
+		if fi, _ := os.Stat(filepath.Join(dest, "pages-2.img")); fi.Sys().(*syscall.Stat_t).Blocks != 0 {
+			t.Errorf("%s: expected an all zero page image to be extracted as a hole", spec)
+		}
+	}
+}
+
+func TestUntarFolder_SparseUnsafe(t *testing.T) {
+	ctx := context.Background()
+	sparse := func(size int64, runs []sparseRun, data int) []byte {
+		body := append(sparseMap(size, runs), make([]byte, data)...)
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		tw.WriteHeader(&tar.Header{Typeflag: typeSparse, Name: "pages-1.img", Mode: 0o644, Size: int64(len(body))})
+		tw.Write(body)
+		tw.Close()
+		return buf.Bytes()
+	}
+	cases := map[string][]byte{
+		"overlapping":   sparse(100, []sparseRun{{0, 10}, {5, 10}}, 20),
+		"past the end":  sparse(100, []sparseRun{{95, 10}}, 10),
+		"empty run":     sparse(100, []sparseRun{{0, 0}}, 0),
+		"short data":    sparse(100, []sparseRun{{0, 10}}, 5),
+		"too big":       sparse(1<<40, []sparseRun{{0, 10}}, 10),
+		"runs overflow": sparse(100, []sparseRun{{0, 10}, {20, 1<<63 - 1}}, 10),
+	}
+	for name, data := range cases {
+		archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+		os.WriteFile(archive, data, 0o644)
+		err := UntarFolder(ctx, archive, t.TempDir(), WithLimits(ExtractLimits{MaxFileSize: 1000}))
+		if !errors.Is(err, ErrUnsafeArchive) {
+			t.Errorf("%s: expected ErrUnsafeArchive, got %v", name, err)
+		}
+	}
+}
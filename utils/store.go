@@ -3,7 +3,9 @@ package utils
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,37 +15,101 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
-	"github.com/spf13/afero"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 // Abstraction for storing and retreiving checkpoints
 type Store interface {
-	GetCheckpoint(ctx context.Context, cid string) (*string, error) // returns filepath to downloaded chekcpoint
-	PushCheckpoint(ctx context.Context, filepath string) error
-	ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) // fix
+	// GetCheckpoint returns a local path to checkpoint cid, downloading it if
+	// needed, with its signed manifest at <path>.manifest if it has one. The
+	// file may belong to the store, so callers must not modify it, and it's
+	// only kept for them until they call release.
+	GetCheckpoint(ctx context.Context, cid string) (path *string, release func(), err error)
+	// PushCheckpoint stores the checkpoint tarball at filepath for job jobID,
+	// along with its signed manifest, <filepath>.manifest, if it has one
+	PushCheckpoint(ctx context.Context, jobID, filepath string) (*CheckpointMeta, error)
+	ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error)
+	DeleteCheckpoint(ctx context.Context, cid string) error
 }
 
-type CheckpointMeta struct {
-	ID       string
-	Name     string
-	Bucket   string
-	ModTime  time.Time
-	Size     uint64
-	Checksum string
+// StreamingStore is implemented by stores that can take a checkpoint archive
+// while it's still being written, so the archive never has to land on disk.
+// The images it's made from still do.
+type StreamingStore interface {
+	// PushCheckpointStream stores the archive read from r for job jobID,
+	// signing it with key unless key is nil
+	PushCheckpointStream(ctx context.Context, jobID string, r io.Reader, key ed25519.PrivateKey) (*CheckpointMeta, error)
 }
 
-type S3Store struct {
-}
+// ErrNotSupported is returned by stores that can't perform an operation
+var ErrNotSupported = errors.New("not supported by this store")
 
-func (s *S3Store) GetCheckpoint() (*string, error) {
-	return nil, nil
+type CheckpointMeta struct {
+	ID    string `json:"id"`
+	JobID string `json:"job_id"`
+	Name  string `json:"name"`
+	// set by stores that upload in parts
+	UploadID string    `json:"upload_id,omitempty"`
+	Bucket   string    `json:"bucket,omitempty"`
+	ModTime  time.Time `json:"mod_time"`
+	Size     uint64    `json:"size"`
+	Checksum string    `json:"checksum,omitempty"`
+	// set on push by ChunkedStore: chunks in the image, bytes that had to be
+	// uploaded, and the share of the image the store already had
+	Chunks        int     `json:"chunks,omitempty"`
+	UploadedBytes uint64  `json:"uploaded_bytes,omitempty"`
+	DedupRatio    float64 `json:"dedup_ratio,omitempty"`
 }
 
-func (s *S3Store) PushCheckpoint(filepath string) error {
-	return nil
+// NewStore returns the store REMOTE checkpoints go to, per cfg.Store: the
+// configured backend, deduplicated by a ChunkedStore if chunking is enabled.
+func NewStore(cfg *Config, tracer trace.Tracer) (Store, error) {
+	cache, err := NewCheckpointCache(cfg.Store)
+	if err != nil {
+		return nil, err
+	}
+	trusted, err := NewTrustedKeys(cfg.Signing)
+	if err != nil {
+		return nil, err
+	}
+
+	var store Store
+	switch cfg.Store.Backend {
+	case "", "cedana":
+		store = NewCedanaStore(cfg, cache, tracer)
+	case "local":
+		if cfg.Store.LocalDir == "" {
+			return nil, fmt.Errorf("store.local_dir must be set for the local store")
+		}
+		if store, err = NewLocalStore(cfg.Store.LocalDir); err != nil {
+			return nil, err
+		}
+	case "s3":
+		if store, err = NewS3Store(cfg.Store.S3, cache, tracer); err != nil {
+			return nil, err
+		}
+	case "oci":
+		// registries deduplicate layers by digest, and carry signatures
+		// as a layer, so checkpoints go in unchunked
+		oci, err := NewOCIStore(cfg.Store.OCI, cache, tracer)
+		if err != nil {
+			return nil, err
+		}
+		oci.trusted = trusted
+		return oci, nil
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", cfg.Store.Backend)
+	}
+
+	if !cfg.Store.Chunking {
+		return store, nil
+	}
+	chunked, err := NewChunkedStore(store, cache)
+	if err != nil {
+		return nil, fmt.Errorf("store.chunking needs the local or s3 store: %w", err)
+	}
+	chunked.trusted = trusted
+	return chunked, nil
 }
 
 type UploadResponse struct {
@@ -57,121 +123,312 @@ type CedanaStore struct {
 	logger *zerolog.Logger
 	cfg    *Config
 	url    string
+	// shared by every request so connections are reused across parts
+	http  *http.Client
+	cache *CheckpointCache
 
 	tracer trace.Tracer
 }
 
-func NewCedanaStore(cfg *Config, tracer trace.Tracer) *CedanaStore {
+func NewCedanaStore(cfg *Config, cache *CheckpointCache, tracer trace.Tracer) *CedanaStore {
 	logger := GetLogger()
 	url := "https://" + cfg.Connection.CedanaUrl
 	return &CedanaStore{
 		logger: &logger,
 		cfg:    cfg,
 		url:    url,
+		http:   &http.Client{},
+		cache:  cache,
 		tracer: tracer,
 	}
 }
 
-// TODO NR - unimplemented stubs for now
+// ListCheckpoints isn't offered by the checkpoint endpoint.
 func (cs *CedanaStore) ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) {
-	return nil, nil
+	return nil, fmt.Errorf("%w: listing checkpoints", ErrNotSupported)
 }
 
-func (cs *CedanaStore) FullMultipartUpload(ctx context.Context, checkpointPath string) (*UploadResponse, error) {
-	ctx, mpuSpan := cs.tracer.Start(ctx, "FullMultipartUpload")
-	defer mpuSpan.End()
+// GetCheckpoint downloads the checkpoint into the cache, in parallel ranges
+// if the endpoint supports them, and checks it against the sha256 given at
+// upload. Its signed manifest, if it has one, comes along. A cached
+// checkpoint is returned without downloading it again.
+func (cs *CedanaStore) GetCheckpoint(ctx context.Context, cid string) (*string, func(), error) {
+	ctx, getSpan := cs.tracer.Start(ctx, "GetCheckpoint")
+	defer getSpan.End()
 
-	file, err := os.Open(checkpointPath)
+	path, release, err := cs.cache.Get(ctx, cid, func(ctx context.Context, partial string) error {
+		return cs.download(ctx, cid, partial)
+	})
 	if err != nil {
-		err := status.Error(codes.Unavailable, "StartMultiPartUpload failed")
-		return &UploadResponse{}, err
+		getSpan.RecordError(err)
+		return nil, nil, err
 	}
-	defer file.Close()
+	return &path, release, nil
+}
+
+// the endpoint's sha256 of the tarball, as given at upload
+const checksumHeader = "X-Checksum-Sha256"
 
-	// Get the file size
-	fileInfo, err := file.Stat()
+func (cs *CedanaStore) checkpointRequest(ctx context.Context, method, cid string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, cs.url+"/checkpoint/"+cid, nil)
 	if err != nil {
-		err = status.Error(codes.NotFound, "checkpoint zip not found")
-		return &UploadResponse{}, err
+		return nil, err
 	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
+	return req, nil
+}
 
-	// Get the size
-	size := fileInfo.Size()
-
-	checkpointFullSize := int64(size)
+// signed manifests are kept by the endpoint alongside their checkpoint
+func (cs *CedanaStore) manifestURL(cid string) string {
+	return cs.url + "/checkpoint/" + cid + "/manifest"
+}
 
-	multipartCheckpointResp, cid, err := cs.CreateMultiPartUpload(ctx, checkpointFullSize)
+// putManifest uploads the signed manifest of the archive at checkpointPath,
+// unless it has none.
+func (cs *CedanaStore) putManifest(ctx context.Context, cid, checkpointPath string) error {
+	signed, err := os.ReadFile(checkpointPath + manifestSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
-		err := status.Error(codes.Unavailable, "CreateMultiPartUpload failed")
-		return &UploadResponse{}, err
+		return err
 	}
 
-	err = cs.StartMultiPartUpload(ctx, cid, multipartCheckpointResp, checkpointPath)
+	return withRetries(ctx, maxPartAttempts, func() error {
+		req, err := http.NewRequestWithContext(ctx, "PUT", cs.manifestURL(cid), bytes.NewReader(signed))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
+
+		resp, err := cs.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		return checkStatus(resp)
+	})
+}
+
+// getManifest downloads checkpoint cid's signed manifest to path, unless it
+// has none.
+func (cs *CedanaStore) getManifest(ctx context.Context, cid, path string) error {
+	return withRetries(ctx, maxPartAttempts, func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", cs.manifestURL(cid), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
+
+		resp, err := cs.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		if err := checkStatus(resp); err != nil {
+			return err
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o644)
+	})
+}
+
+func (cs *CedanaStore) download(ctx context.Context, cid, partial string) error {
+	req, err := cs.checkpointRequest(ctx, "HEAD", cid)
+	if err != nil {
+		return err
+	}
+	resp, err := cs.http.Do(req)
 	if err != nil {
-		err := status.Error(codes.Unavailable, "StartMultiPartUpload failed")
-		return &UploadResponse{}, err
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("checkpoint %s: %w", cid, os.ErrNotExist)
+	}
+	if err := cs.getManifest(ctx, cid, partial+manifestSuffix); err != nil {
+		return fmt.Errorf("signed manifest: %w", err)
 	}
 
-	err = cs.CompleteMultiPartUpload(ctx, *multipartCheckpointResp, cid)
+	checksum := resp.Header.Get(checksumHeader)
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 && resp.Header.Get("Accept-Ranges") == "bytes" && resp.ContentLength > 0 {
+		version := resp.Header.Get("ETag")
+		if version == "" {
+			version = resp.Header.Get("Last-Modified")
+		}
+		err = rangedDownload(ctx, partial, resp.ContentLength, version, defaultDownloadPartSize, defaultDownloadConcurrency, func(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+			return cs.getRange(ctx, cid, version, start, end)
+		})
+	} else {
+		checksum, err = cs.downloadWhole(ctx, cid, partial)
+	}
 	if err != nil {
-		err := status.Error(codes.Unavailable, "CompleteMultiPartUpload failed")
-		return &UploadResponse{}, err
+		return err
 	}
-	return multipartCheckpointResp, nil
+
+	if checksum == "" {
+		cs.logger.Warn().Msgf("no checksum for checkpoint %s, skipping verification", cid)
+		return nil
+	}
+	return verifyChecksum(ctx, partial, checksum)
 }
 
-func (cs *CedanaStore) GetCheckpoint(ctx context.Context, cid string) (*string, error) {
-	_, getSpan := cs.tracer.Start(ctx, "GetCheckpoint")
-	defer getSpan.End()
-	url := cs.url + "/checkpoint/" + cid
-	downloadPath := "checkpoint.tar"
-	file, err := os.Create(downloadPath)
+func (cs *CedanaStore) getRange(ctx context.Context, cid, version string, start, end int64) (io.ReadCloser, error) {
+	req, err := cs.checkpointRequest(ctx, "GET", cid)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if version != "" {
+		req.Header.Set("If-Range", version)
+	}
 
+	resp, err := cs.http.Do(req)
 	if err != nil {
-		getSpan.RecordError(err)
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		if err := checkStatus(resp); err != nil {
+			return nil, err
+		}
+		// If-Range didn't match, so we were sent the whole of some other version
+		return nil, fmt.Errorf("checkpoint %s changed during download", cid)
+	}
+	return resp.Body, nil
+}
 
-	defer file.Close()
+// downloadWhole is the fallback for endpoints without range support
+func (cs *CedanaStore) downloadWhole(ctx context.Context, cid, partial string) (string, error) {
+	req, err := cs.checkpointRequest(ctx, "GET", cid)
+	if err != nil {
+		return "", err
+	}
+
+	var checksum string
+	err = withRetries(ctx, maxPartAttempts, func() error {
+		resp, err := cs.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("checkpoint %s: %w", cid, os.ErrNotExist)
+		}
+		if err := checkStatus(resp); err != nil {
+			return err
+		}
+		checksum = resp.Header.Get(checksumHeader)
 
-	httpClient := &http.Client{}
+		file, err := os.Create(partial)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if _, err := io.Copy(file, resp.Body); err != nil {
+			return err
+		}
+		return file.Sync()
+	})
+	return checksum, err
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// PushCheckpoint uploads the checkpoint in parts, then its signed manifest if
+// it has one. The ID is assigned here and the endpoint gives back the upload
+// ID. If an earlier push of the same file was interrupted, it picks up where
+// that one left off.
+func (cs *CedanaStore) PushCheckpoint(ctx context.Context, jobID, checkpointPath string) (*CheckpointMeta, error) {
+	ctx, pushSpan := cs.tracer.Start(ctx, "PushCheckpoint")
+	defer pushSpan.End()
 
+	info, err := os.Stat(checkpointPath)
 	if err != nil {
-		getSpan.RecordError(err)
+		pushSpan.RecordError(err)
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
-
-	resp, err := httpClient.Do(req)
+	if state := loadUploadState(checkpointPath, jobID, info); state != nil {
+		cs.logger.Info().Msgf("resuming upload %s of checkpoint %s, %d/%d parts already sent", state.Upload.UploadID, state.ID, len(state.Uploaded), state.Upload.PartCount)
+		meta, err := cs.upload(ctx, state, checkpointPath)
+		var statusErr *httpStatusError
+		if err == nil || !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+			if err != nil {
+				pushSpan.RecordError(err)
+			}
+			return meta, err
+		}
+		// the endpoint has expired or forgotten the upload
+		cs.logger.Warn().Msgf("upload %s no longer exists, starting over", state.Upload.UploadID)
+		state.remove()
+	}
 
+	// given to the endpoint so downloads can be verified
+	checksum, err := fileSHA256(ctx, checkpointPath)
 	if err != nil {
-		getSpan.RecordError(err)
+		pushSpan.RecordError(err)
 		return nil, err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, fmt.Errorf("unexpected status code: %v", resp.Status)
+	uploadResp, cid, err := cs.CreateMultiPartUpload(ctx, jobID, info.Size(), checksum)
+	if err != nil {
+		pushSpan.RecordError(err)
+		return nil, fmt.Errorf("CreateMultiPartUpload failed with error: %w", err)
 	}
 
-	defer resp.Body.Close()
+	state := newUploadState(checkpointPath, jobID, cid, *uploadResp, info)
+	state.Checksum = checksum
+	if err := state.save(); err != nil {
+		// still worth uploading, it just won't be resumable
+		cs.logger.Warn().Err(err).Msg("could not save upload state")
+	}
 
-	_, err = io.Copy(file, resp.Body)
+	meta, err := cs.upload(ctx, state, checkpointPath)
 	if err != nil {
-		getSpan.RecordError(err)
-		return nil, err
+		pushSpan.RecordError(err)
+	}
+	return meta, err
+}
+
+func (cs *CedanaStore) upload(ctx context.Context, state *uploadState, checkpointPath string) (*CheckpointMeta, error) {
+	if err := cs.StartMultiPartUpload(ctx, state.ID, &state.Upload, checkpointPath, state); err != nil {
+		return nil, fmt.Errorf("StartMultiPartUpload failed with error: %w", err)
 	}
 
-	return &downloadPath, nil
+	if err := cs.CompleteMultiPartUpload(ctx, state.Upload, state.ID); err != nil {
+		return nil, fmt.Errorf("CompleteMultiPartUpload failed with error: %w", err)
+	}
+	if err := cs.putManifest(ctx, state.ID, checkpointPath); err != nil {
+		return nil, fmt.Errorf("pushing signed manifest: %w", err)
+	}
+	state.remove()
+
+	meta := &CheckpointMeta{
+		ID:       state.ID,
+		JobID:    state.JobID,
+		Name:     state.JobID,
+		UploadID: state.Upload.UploadID,
+		ModTime:  time.Now(),
+		Size:     uint64(state.Size),
+	}
+	if state.Checksum != "" {
+		meta.Checksum = "sha256:" + state.Checksum
+	}
+	return meta, nil
 }
 
-func (cs *CedanaStore) PushCheckpoint(ctx context.Context, filepath string) error {
-	return nil
+func (cs *CedanaStore) DeleteCheckpoint(ctx context.Context, cid string) error {
+	return ErrNotSupported
 }
 
-func (cs *CedanaStore) CreateMultiPartUpload(ctx context.Context, fullSize int64) (*UploadResponse, string, error) {
+func (cs *CedanaStore) CreateMultiPartUpload(ctx context.Context, name string, fullSize int64, checksum string) (*UploadResponse, string, error) {
 	_, cmpSpan := cs.tracer.Start(ctx, "CreateMultiPartUpload")
 	defer cmpSpan.End()
 	var uploadResp UploadResponse
@@ -182,11 +439,12 @@ func (cs *CedanaStore) CreateMultiPartUpload(ctx context.Context, fullSize int64
 		Name     string `json:"name"`
 		FullSize int64  `json:"full_size"`
 		PartSize int    `json:"part_size"`
+		Checksum string `json:"checksum,omitempty"`
 	}{
-		// TODO BS Need to get TaskID properly...
-		Name:     "test",
+		Name:     name,
 		FullSize: fullSize,
 		PartSize: 0,
+		Checksum: checksum,
 	}
 
 	payload, err := json.Marshal(data)
@@ -194,10 +452,9 @@ func (cs *CedanaStore) CreateMultiPartUpload(ctx context.Context, fullSize int64
 		return &uploadResp, "", err
 	}
 
-	httpClient := &http.Client{}
 	url := cs.url + "/checkpoint/" + cid + "/upload"
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
 		return &uploadResp, "", err
 	}
@@ -206,17 +463,17 @@ func (cs *CedanaStore) CreateMultiPartUpload(ctx context.Context, fullSize int64
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
 
-	resp, err := httpClient.Do(req)
+	resp, err := cs.http.Do(req)
 	if err != nil {
 		return &uploadResp, "", err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return &uploadResp, "", fmt.Errorf("unexpected status code: %v", resp.Status)
-	}
-
 	defer resp.Body.Close()
 
+	if err := checkStatus(resp); err != nil {
+		return &uploadResp, "", err
+	}
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return &uploadResp, "", err
@@ -226,136 +483,137 @@ func (cs *CedanaStore) CreateMultiPartUpload(ctx context.Context, fullSize int64
 
 	// Parse the JSON response into the struct
 	if err := json.Unmarshal(respBody, &uploadResp); err != nil {
-		fmt.Println("Error parsing JSON response:", err)
-		return &uploadResp, "", err
+		return &uploadResp, "", fmt.Errorf("error parsing upload response: %w", err)
 	}
 
 	return &uploadResp, cid, nil
 }
 
-func (cs *CedanaStore) StartMultiPartUpload(ctx context.Context, cid string, uploadResp *UploadResponse, checkpointPath string) error {
-	_, smpSpan := cs.tracer.Start(ctx, "StartMultiPartUpload")
+// StartMultiPartUpload streams each part straight from the file, so memory
+// use is bounded by the number of parts in flight rather than the checkpoint
+// size. Failed parts are retried with backoff, and parts already recorded in
+// state are skipped.
+func (cs *CedanaStore) StartMultiPartUpload(ctx context.Context, cid string, uploadResp *UploadResponse, checkpointPath string, state *uploadState) error {
+	ctx, smpSpan := cs.tracer.Start(ctx, "StartMultiPartUpload")
 	defer smpSpan.End()
-	binaryOfFile, err := os.ReadFile(checkpointPath)
+
+	file, err := os.Open(checkpointPath)
 	if err != nil {
-		fmt.Println("Error reading zip file:", err)
 		return err
 	}
+	defer file.Close()
 
-	maxConcurrentUploads := 10
-	semaphore := make(chan struct{}, maxConcurrentUploads)
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
 
 	chunkSize := uploadResp.PartSize
 	numOfParts := uploadResp.PartCount
+	if chunkSize <= 0 || chunkSize*numOfParts < info.Size() {
+		return fmt.Errorf("upload %s has %d parts of %d bytes, too few for %d bytes", uploadResp.UploadID, numOfParts, chunkSize, info.Size())
+	}
+
+	// the first failed part stops the rest; what did succeed is kept in state
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	errChan := make(chan error, numOfParts)
+	semaphore := make(chan struct{}, maxConcurrentUploads)
 	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var uploadErr error
 
 	for i := 0; i < int(numOfParts); i++ {
-		wg.Add(1)
+		partNumber := i + 1
+		if state.uploaded(partNumber) {
+			continue
+		}
 
-		semaphore <- struct{}{}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
 
+		wg.Add(1)
 		go func(partNumber int) {
 			defer wg.Done()
 			defer func() { <-semaphore }()
 
-			start := partNumber * int(chunkSize)
-			end := (partNumber + 1) * int(chunkSize)
-			if end > len(binaryOfFile) {
-				end = len(binaryOfFile)
+			start := int64(partNumber-1) * chunkSize
+			length := chunkSize
+			if start+length > info.Size() {
+				length = info.Size() - start
 			}
 
-			partData := binaryOfFile[start:end]
-			buffer := bytes.NewBuffer(partData)
-
-			httpClient := &http.Client{}
-			url := cs.url + "/checkpoint/" + cid + "/upload/" + uploadResp.UploadID + "/part/" + fmt.Sprintf("%d", partNumber+1)
-
-			req, err := http.NewRequest("PUT", url, buffer)
-			if err != nil {
-				errChan <- err
-				return
+			url := cs.url + "/checkpoint/" + cid + "/upload/" + uploadResp.UploadID + "/part/" + fmt.Sprintf("%d", partNumber)
+			err := withRetries(ctx, maxPartAttempts, func() error {
+				return cs.uploadPart(ctx, url, io.NewSectionReader(file, start, length), length)
+			})
+			if err == nil {
+				cs.logger.Info().Msgf("Part %d uploaded", partNumber)
+				if err = state.markUploaded(partNumber); err != nil {
+					cs.logger.Warn().Err(err).Msg("could not save upload state")
+					err = nil
+				}
 			}
-
-			req.Header.Set("Content-Type", "application/octet-stream")
-			req.Header.Set("Transfer-Encoding", "chunked")
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
-
-			resp, err := httpClient.Do(req)
 			if err != nil {
-				errChan <- err
-				return
-			}
-			if resp.StatusCode < 200 || resp.StatusCode > 299 {
-				errChan <- fmt.Errorf("unexpected status code: %v", resp.Status)
-				return
+				errOnce.Do(func() {
+					uploadErr = fmt.Errorf("part %d: %w", partNumber, err)
+					cancel()
+				})
 			}
+		}(partNumber)
+	}
 
-			defer resp.Body.Close()
+	wg.Wait()
 
-			respBody, err := io.ReadAll(resp.Body)
-			if err != nil {
-				errChan <- err
-				return
-			}
-			cs.logger.Info().Msgf("Part %d uploaded: %s", partNumber+1, string(respBody))
-		}(i)
+	if uploadErr != nil {
+		smpSpan.RecordError(uploadErr)
+		return uploadErr
 	}
+	return ctx.Err()
+}
 
-	wg.Wait()
-	close(errChan)
+func (cs *CedanaStore) uploadPart(ctx context.Context, url string, body io.Reader, length int64) error {
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = length
 
-	// check for errs
-	// check for errors
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
+
+	resp, err := cs.http.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
 
-	return nil
+	return checkStatus(resp)
 }
+
 func (cs *CedanaStore) CompleteMultiPartUpload(ctx context.Context, uploadResp UploadResponse, cid string) error {
 	_, cmpuSpan := cs.tracer.Start(ctx, "CompleteMultiPartUpload")
 	defer cmpuSpan.End()
-	httpClient := &http.Client{}
 	url := cs.url + "/checkpoint/" + cid + "/upload/" + uploadResp.UploadID + "/complete"
 
-	req, err := http.NewRequest("PUT", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, nil)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cs.cfg.Connection.CedanaAuthToken))
 
-	resp, err := httpClient.Do(req)
+	resp, err := cs.http.Do(req)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("unexpected status code: %v", resp.Status)
-	}
-
 	defer resp.Body.Close()
-	return nil
-}
-
-type MockStore struct {
-	fs     *afero.Afero // we can use an in-memory store for testing
-	logger *zerolog.Logger
-}
-
-func (ms *MockStore) GetCheckpoint() (*string, error) {
-	// gets a mock checkpoint from the local filesystem - useful for testing
-	return nil, nil
-}
-
-func (ms *MockStore) PushCheckpoint(filepath string) error {
-	// pushes a mock checkpoint to the local filesystem
-	return nil
-}
 
-func (ms *MockStore) ListCheckpoints(ctx context.Context) (*[]CheckpointMeta, error) {
-	return nil, nil
+	return checkStatus(resp)
 }
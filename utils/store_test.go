@@ -0,0 +1,309 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fakeCheckpointEndpoint implements the multipart upload API of the cedana
+// checkpoint endpoint, and serves back the last checkpoint uploaded along
+// with its signed manifest. fail, if set, may reject a part upload by
+// returning a status code.
+type fakeCheckpointEndpoint struct {
+	partSize int64
+
+	mu       sync.Mutex
+	creates  int
+	puts     map[int]int // part number -> times received
+	parts    map[int][]byte
+	done     []byte
+	manifest []byte
+	fail     func(part, attempt int) int
+}
+
+func (f *fakeCheckpointEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == "POST" && len(path) == 3:
+		var req struct {
+			FullSize int64 `json:"full_size"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		f.creates++
+		json.NewEncoder(w).Encode(UploadResponse{
+			UploadID:  fmt.Sprintf("upload-%d", f.creates),
+			PartSize:  f.partSize,
+			PartCount: (req.FullSize + f.partSize - 1) / f.partSize,
+		})
+	case r.Method == "PUT" && len(path) == 6:
+		part, _ := strconv.Atoi(path[5])
+		f.puts[part]++
+		if f.fail != nil {
+			if code := f.fail(part, f.puts[part]); code != 0 {
+				w.WriteHeader(code)
+				return
+			}
+		}
+		f.parts[part], _ = io.ReadAll(r.Body)
+	case r.Method == "PUT" && len(path) == 5:
+		f.done = nil
+		for i := 1; i <= len(f.parts); i++ {
+			f.done = append(f.done, f.parts[i]...)
+		}
+	case r.Method == "PUT" && len(path) == 3 && path[2] == "manifest":
+		f.manifest, _ = io.ReadAll(r.Body)
+	case r.Method == "GET" && len(path) == 3 && path[2] == "manifest" && f.manifest != nil:
+		w.Write(f.manifest)
+	case (r.Method == "GET" || r.Method == "HEAD") && len(path) == 2 && f.done != nil:
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(f.done))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sent returns how many times each part has been received. Handlers for
+// requests the client gave up on may still be running, hence the lock.
+func (f *fakeCheckpointEndpoint) sent() map[int]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sent := map[int]int{}
+	for part, n := range f.puts {
+		sent[part] = n
+	}
+	return sent
+}
+
+func newTestCedanaStore(t *testing.T, endpoint *fakeCheckpointEndpoint) *CedanaStore {
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+	return testCedanaStore(t, srv)
+}
+
+func testCedanaStore(t *testing.T, srv *httptest.Server) *CedanaStore {
+	backoff := partRetryBackoff
+	partRetryBackoff = time.Millisecond
+	t.Cleanup(func() { partRetryBackoff = backoff })
+
+	cache, err := NewCheckpointCache(StoreConfig{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := GetLogger()
+	return &CedanaStore{
+		logger: &logger,
+		cfg:    &Config{},
+		url:    srv.URL,
+		http:   srv.Client(),
+		cache:  cache,
+		tracer: trace.NewNoopTracerProvider().Tracer("test"),
+	}
+}
+
+func writeTestCheckpoint(t *testing.T, size int) (string, []byte) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), size/16+1)[:size]
+	path := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+func TestCedanaStore_PushRetriesParts(t *testing.T) {
+	endpoint := &fakeCheckpointEndpoint{
+		partSize: 100,
+		puts:     map[int]int{},
+		parts:    map[int][]byte{},
+		// every part fails twice before going through
+		fail: func(part, attempt int) int {
+			if attempt <= 2 {
+				return http.StatusServiceUnavailable
+			}
+			return 0
+		},
+	}
+	store := newTestCedanaStore(t, endpoint)
+	path, data := writeTestCheckpoint(t, 1050)
+
+	meta, err := store.PushCheckpoint(context.Background(), "job-a", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Size != 1050 || meta.UploadID != "upload-1" {
+		t.Errorf("unexpected meta %+v", meta)
+	}
+	if !bytes.Equal(endpoint.done, data) {
+		t.Errorf("endpoint assembled %d bytes, expected the %d byte checkpoint", len(endpoint.done), len(data))
+	}
+	if _, err := os.Stat(uploadStatePath(path)); !os.IsNotExist(err) {
+		t.Errorf("expected upload state to be removed after completing, got %v", err)
+	}
+}
+
+func TestCedanaStore_PushResumes(t *testing.T) {
+	endpoint := &fakeCheckpointEndpoint{
+		partSize: 100,
+		puts:     map[int]int{},
+		parts:    map[int][]byte{},
+		// part 7 is rejected outright the first time round
+		fail: func(part, attempt int) int {
+			if part == 7 && attempt == 1 {
+				return http.StatusForbidden
+			}
+			return 0
+		},
+	}
+	store := newTestCedanaStore(t, endpoint)
+	path, checkpoint := writeTestCheckpoint(t, 1000)
+
+	if _, err := store.PushCheckpoint(context.Background(), "job-a", path); err == nil {
+		t.Fatal("expected the push to fail")
+	}
+	sent := endpoint.sent()
+	if sent[7] != 1 {
+		t.Errorf("expected a 403 not to be retried, part 7 was sent %d times", sent[7])
+	}
+	if _, err := os.Stat(uploadStatePath(path)); err != nil {
+		t.Fatalf("expected upload state to be kept: %v", err)
+	}
+
+	var state uploadState
+	data, err := os.ReadFile(uploadStatePath(path))
+	if err != nil || json.Unmarshal(data, &state) != nil || len(state.Uploaded) == 0 {
+		t.Fatalf("expected saved progress, got %s (%v)", data, err)
+	}
+
+	if _, err := store.PushCheckpoint(context.Background(), "job-a", path); err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.creates != 1 {
+		t.Errorf("expected the upload to be resumed, got %d uploads created", endpoint.creates)
+	}
+	// parts in flight when the push failed may go again, finished ones mustn't
+	resent := endpoint.sent()
+	for _, part := range state.Uploaded {
+		if resent[part] != sent[part] {
+			t.Errorf("part %d was sent again after it had succeeded", part)
+		}
+	}
+	if !bytes.Equal(endpoint.done, checkpoint) {
+		t.Errorf("endpoint assembled %d bytes, expected the %d byte checkpoint", len(endpoint.done), len(checkpoint))
+	}
+}
+
+func TestCedanaStore_SignedManifest(t *testing.T) {
+	ctx := context.Background()
+	endpoint := &fakeCheckpointEndpoint{partSize: 100, puts: map[int]int{}, parts: map[int][]byte{}}
+	store := newTestCedanaStore(t, endpoint)
+	key, _ := testNodeKey(t)
+
+	path, _ := writeTestCheckpoint(t, 1050)
+	if err := SignArchive(ctx, path, "job-a", key); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := store.PushCheckpoint(ctx, "job-a", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed, _ := os.ReadFile(path + manifestSuffix); !bytes.Equal(endpoint.manifest, signed) {
+		t.Fatalf("expected the signed manifest to be pushed, got %q", endpoint.manifest)
+	}
+
+	got, _, err := store.GetCheckpoint(ctx, meta.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyArchive(ctx, *got, trust(key)); err != nil {
+		t.Errorf("expected the downloaded checkpoint to verify, got %v", err)
+	}
+}
+
+// serves checkpoint "ckpt" with range support; the first ranged GET of each
+// range fails
+func newDownloadEndpoint(data []byte, checksum string) (*httptest.Server, *int32) {
+	var requests int32
+	var mu sync.Mutex
+	failed := map[string]bool{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/checkpoint/ckpt" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		first := r.Method == "GET" && !failed[r.Header.Get("Range")]
+		failed[r.Header.Get("Range")] = true
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set(checksumHeader, checksum)
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	})), &requests
+}
+
+func TestCedanaStore_GetCheckpoint(t *testing.T) {
+	data := bytes.Repeat([]byte("checkpoint"), 20<<20/10)
+	sum := sha256.Sum256(data)
+	srv, requests := newDownloadEndpoint(data, hex.EncodeToString(sum[:]))
+	defer srv.Close()
+	store := testCedanaStore(t, srv)
+
+	path, _, err := store.GetCheckpoint(context.Background(), "ckpt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(*path)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("downloaded checkpoint differs (err %v)", err)
+	}
+
+	before := atomic.LoadInt32(requests)
+	again, _, err := store.GetCheckpoint(context.Background(), "ckpt")
+	if err != nil || *again != *path {
+		t.Fatalf("expected the cached copy at %s, got %v, %v", *path, again, err)
+	}
+	if atomic.LoadInt32(requests) != before {
+		t.Error("expected a cached checkpoint not to be downloaded again")
+	}
+
+	if _, _, err := store.GetCheckpoint(context.Background(), "missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing checkpoint to be ErrNotExist, got %v", err)
+	}
+}
+
+func TestCedanaStore_GetCheckpointVerifies(t *testing.T) {
+	srv, _ := newDownloadEndpoint([]byte("checkpoint"), strings.Repeat("0", 64))
+	defer srv.Close()
+	store := testCedanaStore(t, srv)
+
+	if _, _, err := store.GetCheckpoint(context.Background(), "ckpt"); err == nil || !strings.Contains(err.Error(), "verification") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	if entries, _ := os.ReadDir(store.cache.dir); len(entries) != 0 {
+		t.Errorf("expected nothing left in the cache, found %d entries", len(entries))
+	}
+}
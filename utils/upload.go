@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	maxConcurrentUploads = 10
+	maxPartAttempts      = 5
+	maxPartBackoff       = 30 * time.Second
+)
+
+// first retry waits this long, doubling each attempt up to maxPartBackoff
+var partRetryBackoff = 500 * time.Millisecond
+
+// httpStatusError is a non-2xx response from the checkpoint endpoint
+type httpStatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %v", e.Status)
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &httpStatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
+
+// retryable reports whether a failed request is worth trying again: network
+// errors, timeouts, throttling and server errors are; other 4xx aren't.
+func retryable(err error) bool {
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500 || statusErr.Code == http.StatusTooManyRequests || statusErr.Code == http.StatusRequestTimeout
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// withRetries calls fn until it succeeds, fails with a non-retryable error,
+// runs out of attempts or ctx is done, backing off exponentially in between.
+func withRetries(ctx context.Context, attempts int, fn func() error) error {
+	backoff := partRetryBackoff
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil || !retryable(err) {
+			return err
+		}
+		if i == attempts-1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxPartBackoff {
+			backoff = maxPartBackoff
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+}
+
+// uploadState records the progress of a multipart upload next to the
+// checkpoint, so pushing the same checkpoint again after an interruption
+// only sends the parts the endpoint doesn't have yet.
+type uploadState struct {
+	JobID    string         `json:"job_id"`
+	ID       string         `json:"id"`
+	Upload   UploadResponse `json:"upload"`
+	Size     int64          `json:"size"`
+	ModTime  time.Time      `json:"mod_time"`
+	Checksum string         `json:"checksum,omitempty"`
+	Uploaded []int          `json:"uploaded_parts"`
+
+	path string
+	mu   sync.Mutex
+	done map[int]bool
+}
+
+func uploadStatePath(checkpointPath string) string {
+	return checkpointPath + ".upload.json"
+}
+
+// loadUploadState returns the saved state for checkpointPath, or nil if there
+// is none or it belongs to a different file or job.
+func loadUploadState(checkpointPath, jobID string, info os.FileInfo) *uploadState {
+	data, err := os.ReadFile(uploadStatePath(checkpointPath))
+	if err != nil {
+		return nil
+	}
+	var state uploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+	if state.JobID != jobID || state.Size != info.Size() || !state.ModTime.Equal(info.ModTime()) {
+		return nil
+	}
+
+	state.path = uploadStatePath(checkpointPath)
+	state.done = map[int]bool{}
+	for _, part := range state.Uploaded {
+		state.done[part] = true
+	}
+	return &state
+}
+
+func newUploadState(checkpointPath, jobID, cid string, upload UploadResponse, info os.FileInfo) *uploadState {
+	return &uploadState{
+		JobID:   jobID,
+		ID:      cid,
+		Upload:  upload,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		path:    uploadStatePath(checkpointPath),
+		done:    map[int]bool{},
+	}
+}
+
+func (u *uploadState) uploaded(part int) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.done[part]
+}
+
+// markUploaded records part as sent and saves the state
+func (u *uploadState) markUploaded(part int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.done[part] = true
+	return u.saveLocked()
+}
+
+func (u *uploadState) save() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.saveLocked()
+}
+
+func (u *uploadState) saveLocked() error {
+	u.Uploaded = u.Uploaded[:0]
+	for part := range u.done {
+		u.Uploaded = append(u.Uploaded, part)
+	}
+	sort.Ints(u.Uploaded)
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(u.path, data)
+}
+
+func (u *uploadState) remove() {
+	os.Remove(u.path)
+}